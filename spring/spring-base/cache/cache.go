@@ -19,7 +19,6 @@ package cache
 
 import (
 	"context"
-	"sync"
 	"time"
 )
 
@@ -28,14 +27,18 @@ var (
 	Engine Driver
 )
 
+// init creates the default Cache, which doesn't sweep the expired values in
+// the background, use a *Storage created with SweepInterval to do that.
 func init() {
-	Cache = NewStorage(1, SimpleHash)
+	Cache = NewStorage(16, SimpleHash)
 	Engine = &engine{}
 }
 
-// A Shardable cache can improve the performance of concurrency.
+// A Shardable cache can improve the performance of concurrency, its shards
+// are built with NewShard.
 type Shardable interface {
-	Sharding(key string) *sync.Map
+	Sharding(key string) *Shard
+	Shards() []*Shard
 }
 
 type LoadType int
@@ -45,6 +48,7 @@ const (
 	LoadOnCtx
 	LoadCache
 	LoadSource
+	LoadRemote // loaded from a remote cache, such as Redis.
 )
 
 // ResultLoader returns a wrapper for the source value of the key.
@@ -53,20 +57,39 @@ type ResultLoader func(ctx context.Context, key string) (Result, error)
 // Driver loads value from m, if there is no cached value, call the loader
 // to get value, and then stores it into m.
 type Driver interface {
-	Load(ctx context.Context, m *sync.Map, key string, loader ResultLoader, arg OptionArg) (loadType LoadType, result Result, err error)
+	Load(ctx context.Context, m *Shard, key string, loader ResultLoader, arg OptionArg) (loadType LoadType, result Result, err error)
 }
 
-// Has returns whether the key exists.
+// Has returns whether the key exists in the default namespace.
 func Has(key string) bool {
-	v, ok := Cache.Sharding(key).Load(key)
-	if ok && v != nil {
-		return !v.(*cacheItem).expired()
-	}
-	return false
+	return Default.Has(key)
+}
+
+// Invalidate deletes the key from the default namespace.
+func Invalidate(key string) {
+	Default.Invalidate(key)
+}
+
+// InvalidatePrefix deletes the keys which have the prefix from the default
+// namespace.
+func InvalidatePrefix(prefix string) {
+	Default.InvalidatePrefix(prefix)
+}
+
+// InvalidateAll deletes all keys from the default namespace.
+func InvalidateAll() {
+	Default.InvalidateAll()
+}
+
+// Refresh reloads the value of the key in the default namespace.
+func Refresh(ctx context.Context, key string) error {
+	return Default.Refresh(ctx, key)
 }
 
 type OptionArg struct {
-	ExpireAfterWrite time.Duration
+	ExpireAfterWrite  time.Duration
+	ExpireAfterAccess time.Duration
+	RefreshAfterWrite time.Duration
 }
 
 type Option func(*OptionArg)
@@ -78,23 +101,28 @@ func ExpireAfterWrite(v time.Duration) Option {
 	}
 }
 
+// ExpireAfterAccess sets the expiration time of the cache value after the
+// last access.
+func ExpireAfterAccess(v time.Duration) Option {
+	return func(arg *OptionArg) {
+		arg.ExpireAfterAccess = v
+	}
+}
+
+// RefreshAfterWrite sets the time after which the value is reloaded in the
+// background by the next access, it should be less than ExpireAfterWrite so
+// the hot keys are refreshed before they expire.
+func RefreshAfterWrite(v time.Duration) Option {
+	return func(arg *OptionArg) {
+		arg.RefreshAfterWrite = v
+	}
+}
+
 // Loader gets value from a background, such as Redis, MySQL, etc.
 type Loader func(ctx context.Context, key string) (interface{}, error)
 
-// Load loads value from cache, if there is no cached value, call the loader
-// to get value, and then stores it.
+// Load loads value from the default namespace, if there is no cached value,
+// call the loader to get value, and then stores it.
 func Load(ctx context.Context, key string, loader Loader, opts ...Option) (loadType LoadType, result Result, _ error) {
-	arg := OptionArg{}
-	for _, opt := range opts {
-		opt(&arg)
-	}
-	l := func(ctx context.Context, key string) (Result, error) {
-		v, err := loader(ctx, key)
-		if err != nil {
-			return nil, err
-		}
-		return NewValueResult(v), nil
-	}
-	m := Cache.Sharding(key)
-	return Engine.Load(ctx, m, key, l, arg)
+	return Default.Load(ctx, key, loader, opts...)
 }
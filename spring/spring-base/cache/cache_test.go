@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -231,3 +232,218 @@ func TestCache(t *testing.T) {
 		})
 	}
 }
+
+func withCache(t *testing.T, s *cache.Storage) {
+	old := cache.Cache
+	cache.Cache = s
+	t.Cleanup(func() { cache.Cache = old })
+}
+
+func loadString(key string, loader func(key string) (string, error), opts ...cache.Option) (string, cache.LoadType, error) {
+	loadType, result, err := cache.Load(context.Background(), key, func(ctx context.Context, key string) (interface{}, error) {
+		return loader(key)
+	}, opts...)
+	if err != nil {
+		return "", loadType, err
+	}
+	var s string
+	err = result.Load(&s)
+	return s, loadType, err
+}
+
+func TestBoundedStorage(t *testing.T) {
+	s := cache.NewStorage(2, cache.SimpleHash, cache.MaxSize(4))
+	withCache(t, s)
+	loader := func(key string) (string, error) { return key, nil }
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		_, _, err := loadString(key, loader)
+		assert.Nil(t, err)
+	}
+	n := 0
+	for _, m := range s.Shards() {
+		assert.True(t, m.Len() <= 2)
+		n += m.Len()
+	}
+	assert.Equal(t, n, 4)
+}
+
+func TestExpireAfterAccess(t *testing.T) {
+	withCache(t, cache.NewStorage(1, cache.SimpleHash))
+	count := 0
+	loader := func(key string) (string, error) {
+		count++
+		return key, nil
+	}
+	opts := []cache.Option{cache.ExpireAfterAccess(60 * time.Millisecond)}
+	for i := 0; i < 4; i++ {
+		_, _, err := loadString("a", loader, opts...)
+		assert.Nil(t, err)
+		time.Sleep(30 * time.Millisecond)
+	}
+	assert.Equal(t, count, 1)
+	time.Sleep(90 * time.Millisecond)
+	assert.False(t, cache.Has("a"))
+	_, loadType, err := loadString("a", loader, opts...)
+	assert.Nil(t, err)
+	assert.Equal(t, loadType, cache.LoadSource)
+	assert.Equal(t, count, 2)
+}
+
+func TestSweep(t *testing.T) {
+	s := cache.NewStorage(1, cache.SimpleHash)
+	withCache(t, s)
+	loader := func(key string) (string, error) { return key, nil }
+	_, _, err := loadString("a", loader, cache.ExpireAfterWrite(10*time.Millisecond))
+	assert.Nil(t, err)
+	_, _, err = loadString("b", loader)
+	assert.Nil(t, err)
+	_, _, err = loadString("c", func(key string) (string, error) {
+		return "", errors.New("this is an error")
+	})
+	assert.Error(t, err, "this is an error")
+	assert.Equal(t, s.Sharding("a").Len(), 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.SweepEvery(ctx, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, s.Sharding("a").Len(), 1)
+	assert.True(t, cache.Has("b"))
+}
+
+func TestSweepInterval(t *testing.T) {
+	s := cache.NewStorage(2, cache.SimpleHash, cache.SweepInterval(5*time.Millisecond))
+	defer s.Close()
+	withCache(t, s)
+	loader := func(key string) (string, error) { return key, nil }
+	_, _, err := loadString("a", loader, cache.ExpireAfterWrite(10*time.Millisecond))
+	assert.Nil(t, err)
+	_, _, err = loadString("b", loader)
+	assert.Nil(t, err)
+	time.Sleep(50 * time.Millisecond)
+	n := 0
+	for _, m := range s.Shards() {
+		n += m.Len()
+	}
+	assert.Equal(t, n, 1)
+	assert.True(t, cache.Has("b"))
+}
+
+func TestReset(t *testing.T) {
+	s := cache.NewStorage(2, cache.SimpleHash, cache.MaxSize(4), cache.SweepInterval(time.Millisecond))
+	defer s.Close()
+	withCache(t, s)
+	loader := func(key string) (string, error) { return key, nil }
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			_, _, err := loadString(strconv.Itoa(i), loader)
+			assert.Nil(t, err)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		s.Reset()
+		time.Sleep(time.Millisecond)
+	}
+	<-done
+	s.Reset()
+	for _, m := range s.Shards() {
+		assert.Equal(t, m.Len(), 0)
+	}
+	assert.Equal(t, cache.StatsOf(s.Shards()).Requests(), int64(0))
+}
+
+func TestInvalidate(t *testing.T) {
+	withCache(t, cache.NewStorage(3, cache.SimpleHash))
+	loader := func(key string) (string, error) { return key, nil }
+	for _, key := range []string{"user:1", "user:2", "order:1", "order:2"} {
+		_, _, err := loadString(key, loader)
+		assert.Nil(t, err)
+	}
+	cache.Invalidate("user:1")
+	assert.False(t, cache.Has("user:1"))
+	assert.True(t, cache.Has("user:2"))
+	cache.InvalidatePrefix("order:")
+	assert.False(t, cache.Has("order:1"))
+	assert.False(t, cache.Has("order:2"))
+	assert.True(t, cache.Has("user:2"))
+	cache.InvalidateAll()
+	assert.False(t, cache.Has("user:2"))
+}
+
+func TestRefresh(t *testing.T) {
+	withCache(t, cache.NewStorage(1, cache.SimpleHash))
+
+	var (
+		mutex   sync.Mutex
+		version int
+		fail    bool
+	)
+	loader := func(key string) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if fail {
+			return "", errors.New("this is an error")
+		}
+		version++
+		return key + strconv.Itoa(version), nil
+	}
+
+	assert.Nil(t, cache.Refresh(context.Background(), "a"))
+
+	v, _, err := loadString("a", loader)
+	assert.Nil(t, err)
+	assert.Equal(t, v, "a1")
+
+	assert.Nil(t, cache.Refresh(context.Background(), "a"))
+	v, loadType, err := loadString("a", loader)
+	assert.Nil(t, err)
+	assert.Equal(t, v, "a2")
+	assert.Equal(t, loadType, cache.LoadCache)
+
+	mutex.Lock()
+	fail = true
+	mutex.Unlock()
+	assert.Error(t, cache.Refresh(context.Background(), "a"), "this is an error")
+	v, _, err = loadString("a", loader)
+	assert.Nil(t, err)
+	assert.Equal(t, v, "a2")
+}
+
+func TestRefreshAfterWrite(t *testing.T) {
+	withCache(t, cache.NewStorage(1, cache.SimpleHash))
+
+	var (
+		mutex   sync.Mutex
+		version int
+	)
+	loader := func(key string) (string, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		version++
+		return key + strconv.Itoa(version), nil
+	}
+
+	opts := []cache.Option{
+		cache.ExpireAfterWrite(time.Second),
+		cache.RefreshAfterWrite(20 * time.Millisecond),
+	}
+	v, _, err := loadString("a", loader, opts...)
+	assert.Nil(t, err)
+	assert.Equal(t, v, "a1")
+
+	time.Sleep(30 * time.Millisecond)
+	v, loadType, err := loadString("a", loader, opts...)
+	assert.Nil(t, err)
+	assert.Equal(t, v, "a1")
+	assert.Equal(t, loadType, cache.LoadCache)
+
+	for i := 0; i < 100; i++ {
+		if v, _, _ = loadString("a", loader, opts...); v == "a2" {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, v, "a2")
+}
@@ -23,19 +23,47 @@ import (
 )
 
 type cacheItem struct {
-	value            Result
-	writeTime        time.Time
-	locker           sync.Mutex
-	loader           ResultLoader
-	expireAfterWrite time.Duration
-	loading          *cacheItemLoading
+	stats             *counters
+	value             Result
+	writeTime         time.Time
+	accessTime        time.Time
+	locker            sync.Mutex
+	loader            ResultLoader
+	expireAfterWrite  time.Duration
+	expireAfterAccess time.Duration
+	refreshAfterWrite time.Duration
+	loading           *cacheItemLoading
+	refreshing        bool
 }
 
-func (e *cacheItem) expired() bool {
-	if e.expireAfterWrite <= 0 {
+// expired returns whether the value is expired, it must be called with the
+// lock held.
+func (e *cacheItem) expired(now time.Time) bool {
+	if e.expireAfterWrite > 0 && now.Sub(e.writeTime) > e.expireAfterWrite {
+		return true
+	}
+	if e.expireAfterAccess > 0 && now.Sub(e.accessTime) > e.expireAfterAccess {
+		return true
+	}
+	return false
+}
+
+// available returns whether the value is cached and not expired.
+func (e *cacheItem) available() bool {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	return e.value != nil && !e.expired(time.Now())
+}
+
+// Sweepable returns whether the item can be deleted by sweeping, the items
+// being loaded are always kept.
+func (e *cacheItem) Sweepable() bool {
+	e.locker.Lock()
+	defer e.locker.Unlock()
+	if e.loading != nil || e.refreshing {
 		return false
 	}
-	return time.Since(e.writeTime)-e.expireAfterWrite > 0
+	return e.value == nil || e.expired(time.Now())
 }
 
 type cacheItemLoading struct {
@@ -53,23 +81,36 @@ func (e *cacheItem) load(ctx context.Context, key string) (LoadType, Result, err
 		if p.err != nil {
 			return LoadNone, nil, p.err
 		}
+		e.stats.recordHit()
 		return LoadCache, p.v, nil
 	}
-	if e.value != nil && !e.expired() {
+	now := time.Now()
+	if e.value != nil && !e.expired(now) {
+		e.accessTime = now
+		v := e.value
+		if e.refreshAfterWrite > 0 && !e.refreshing && now.Sub(e.writeTime) > e.refreshAfterWrite {
+			e.refreshing = true
+			go e.refreshAhead(key)
+		}
 		e.locker.Unlock()
-		return LoadCache, e.value, nil
+		e.stats.recordHit()
+		return LoadCache, v, nil
 	}
 	c := &cacheItemLoading{}
 	c.wg.Add(1)
 	e.loading = c
 	e.locker.Unlock()
 
+	e.stats.recordMiss()
+	start := time.Now()
 	c.v, c.err = e.loader(ctx, key)
+	e.stats.recordLoad(time.Since(start), c.err)
 	c.wg.Done()
 
 	e.locker.Lock()
 	e.value = c.v
 	e.writeTime = time.Now()
+	e.accessTime = e.writeTime
 	e.loading = nil
 	e.locker.Unlock()
 
@@ -79,14 +120,46 @@ func (e *cacheItem) load(ctx context.Context, key string) (LoadType, Result, err
 	return LoadSource, c.v, nil
 }
 
+// refreshAhead reloads the value in the background, the value being refreshed
+// is still returned until the new value is loaded, and it's kept if the
+// reload fails. The context of the request isn't used because it may be done
+// before the reload completes.
+func (e *cacheItem) refreshAhead(key string) {
+	_ = e.refresh(context.Background(), key)
+	e.locker.Lock()
+	e.refreshing = false
+	e.locker.Unlock()
+}
+
+// refresh reloads the value, the old value is kept if the reload fails.
+func (e *cacheItem) refresh(ctx context.Context, key string) error {
+	start := time.Now()
+	v, err := e.loader(ctx, key)
+	e.stats.recordLoad(time.Since(start), err)
+	if err != nil {
+		return err
+	}
+	e.locker.Lock()
+	e.value = v
+	e.writeTime = time.Now()
+	e.locker.Unlock()
+	return nil
+}
+
 type engine struct{}
 
 // Load loads value from m, if there is no cached value, call the loader
 // to get value, and then stores it into m.
-func (d *engine) Load(ctx context.Context, m *sync.Map, key string, loader ResultLoader, arg OptionArg) (loadType LoadType, result Result, err error) {
+func (d *engine) Load(ctx context.Context, m *Shard, key string, loader ResultLoader, arg OptionArg) (loadType LoadType, result Result, err error) {
+	if v, ok := m.Load(key); ok {
+		return v.(*cacheItem).load(ctx, key)
+	}
 	actual, _ := m.LoadOrStore(key, &cacheItem{
-		expireAfterWrite: arg.ExpireAfterWrite,
-		loader:           loader,
+		stats:             &m.stats,
+		expireAfterWrite:  arg.ExpireAfterWrite,
+		expireAfterAccess: arg.ExpireAfterAccess,
+		refreshAfterWrite: arg.RefreshAfterWrite,
+		loader:            loader,
 	})
 	return actual.(*cacheItem).load(ctx, key)
 }
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultNamespace is the name of the default namespace.
+const DefaultNamespace = "default"
+
+// Default is the default namespace, it stores the values in Cache and loads
+// them by Engine, so the package level functions follow the changes of them.
+var Default = &Namespace{name: DefaultNamespace}
+
+var namespaces = struct {
+	sync.RWMutex
+	m map[string]*Namespace
+}{m: map[string]*Namespace{DefaultNamespace: Default}}
+
+type NamespaceOption func(*Namespace)
+
+// UseDriver sets the driver of the namespace, the default is Engine.
+func UseDriver(d Driver) NamespaceOption {
+	return func(n *Namespace) {
+		n.driver = d
+	}
+}
+
+// Namespace is a named cache, it has its own storage and statistics.
+type Namespace struct {
+	name    string
+	storage Shardable
+	driver  Driver
+}
+
+// NewNamespace returns a new *Namespace and registers it by name, it returns
+// an error if the name is already registered.
+func NewNamespace(name string, storage Shardable, opts ...NamespaceOption) (*Namespace, error) {
+	n := &Namespace{name: name, storage: storage}
+	for _, opt := range opts {
+		opt(n)
+	}
+	namespaces.Lock()
+	defer namespaces.Unlock()
+	if _, ok := namespaces.m[name]; ok {
+		return nil, fmt.Errorf("cache: namespace %s already exists", name)
+	}
+	namespaces.m[name] = n
+	return n, nil
+}
+
+// GetNamespace returns the namespace registered by name.
+func GetNamespace(name string) (*Namespace, bool) {
+	namespaces.RLock()
+	defer namespaces.RUnlock()
+	n, ok := namespaces.m[name]
+	return n, ok
+}
+
+// RemoveNamespace unregisters the namespace, the default namespace can't be
+// removed.
+func RemoveNamespace(name string) {
+	if name == DefaultNamespace {
+		return
+	}
+	namespaces.Lock()
+	defer namespaces.Unlock()
+	delete(namespaces.m, name)
+}
+
+// Namespaces returns all registered namespaces sorted by name.
+func Namespaces() []*Namespace {
+	namespaces.RLock()
+	ret := make([]*Namespace, 0, len(namespaces.m))
+	for _, n := range namespaces.m {
+		ret = append(ret, n)
+	}
+	namespaces.RUnlock()
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].name < ret[j].name
+	})
+	return ret
+}
+
+// Name returns the name of the namespace.
+func (n *Namespace) Name() string {
+	return n.name
+}
+
+// Storage returns the storage of the namespace.
+func (n *Namespace) Storage() Shardable {
+	if n.storage == nil {
+		return Cache
+	}
+	return n.storage
+}
+
+func (n *Namespace) getDriver() Driver {
+	if n.driver == nil {
+		return Engine
+	}
+	return n.driver
+}
+
+// Stats returns a snapshot of the statistics of the namespace.
+func (n *Namespace) Stats() Stats {
+	return StatsOf(n.Storage().Shards())
+}
+
+// Has returns whether the key exists.
+func (n *Namespace) Has(key string) bool {
+	v, ok := n.Storage().Sharding(key).peek(key)
+	if ok && v != nil {
+		return v.(*cacheItem).available()
+	}
+	return false
+}
+
+// Invalidate deletes the key.
+func (n *Namespace) Invalidate(key string) {
+	n.Storage().Sharding(key).Delete(key)
+}
+
+// InvalidatePrefix deletes the keys which have the prefix.
+func (n *Namespace) InvalidatePrefix(prefix string) {
+	for _, m := range n.Storage().Shards() {
+		m.DeletePrefix(prefix)
+	}
+}
+
+// InvalidateAll deletes all keys.
+func (n *Namespace) InvalidateAll() {
+	n.InvalidatePrefix("")
+}
+
+// Refresh reloads the value of the key by its loader, the old value is
+// returned by Load until the new value is loaded, and it's kept if the
+// reload fails. It does nothing if the key doesn't exist.
+func (n *Namespace) Refresh(ctx context.Context, key string) error {
+	v, ok := n.Storage().Sharding(key).peek(key)
+	if !ok {
+		return nil
+	}
+	return v.(*cacheItem).refresh(ctx, key)
+}
+
+// Load loads value from cache, if there is no cached value, call the loader
+// to get value, and then stores it.
+func (n *Namespace) Load(ctx context.Context, key string, loader Loader, opts ...Option) (loadType LoadType, result Result, _ error) {
+	arg := OptionArg{}
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	l := func(ctx context.Context, key string) (Result, error) {
+		v, err := loader(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return NewValueResult(v), nil
+	}
+	m := n.Storage().Sharding(key)
+	return n.getDriver().Load(ctx, m, key, l, arg)
+}
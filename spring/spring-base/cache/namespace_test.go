@@ -0,0 +1,96 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/cache"
+)
+
+func TestNamespace(t *testing.T) {
+
+	n, err := cache.NewNamespace("users", cache.NewStorage(2, cache.SimpleHash, cache.MaxSize(2)))
+	assert.Nil(t, err)
+	defer cache.RemoveNamespace("users")
+
+	_, err = cache.NewNamespace("users", cache.NewStorage(1, cache.SimpleHash))
+	assert.Error(t, err, "cache: namespace users already exists")
+
+	v, ok := cache.GetNamespace("users")
+	assert.True(t, ok)
+	assert.Equal(t, v, n)
+
+	var names []string
+	for _, ns := range cache.Namespaces() {
+		names = append(names, ns.Name())
+	}
+	assert.Equal(t, names, []string{"default", "users"})
+
+	cache.RemoveNamespace(cache.DefaultNamespace)
+	_, ok = cache.GetNamespace(cache.DefaultNamespace)
+	assert.True(t, ok)
+
+	ctx := context.Background()
+	loader := func(ctx context.Context, key string) (interface{}, error) {
+		return key, nil
+	}
+	_, _, err = n.Load(ctx, "a", loader)
+	assert.Nil(t, err)
+	assert.True(t, n.Has("a"))
+	assert.False(t, cache.Has("a"))
+	n.InvalidateAll()
+	assert.False(t, n.Has("a"))
+}
+
+func TestStats(t *testing.T) {
+
+	n, err := cache.NewNamespace("stats", cache.NewStorage(1, cache.SimpleHash, cache.MaxSize(2)))
+	assert.Nil(t, err)
+	defer cache.RemoveNamespace("stats")
+
+	assert.Equal(t, n.Stats(), cache.Stats{})
+	assert.Equal(t, n.Stats().HitRatio(), 1.0)
+
+	ctx := context.Background()
+	loader := func(ctx context.Context, key string) (interface{}, error) {
+		time.Sleep(2 * time.Millisecond)
+		if key == "error" {
+			return nil, errors.New("this is an error")
+		}
+		return key, nil
+	}
+	for _, key := range []string{"a", "a", "a", "b", "error", "c"} {
+		_, _, _ = n.Load(ctx, key, loader)
+	}
+
+	s := n.Stats()
+	assert.Equal(t, s.Hits, int64(2))
+	assert.Equal(t, s.Misses, int64(4))
+	assert.Equal(t, s.Loads, int64(3))
+	assert.Equal(t, s.LoadErrors, int64(1))
+	assert.Equal(t, s.Evictions, int64(2))
+	assert.Equal(t, s.Size, 2)
+	assert.Equal(t, s.Requests(), int64(6))
+	assert.Equal(t, s.HitRatio(), 2.0/6)
+	assert.True(t, s.TotalLoadTime >= 8*time.Millisecond)
+	assert.Equal(t, s.AverageLoadTime(), s.TotalLoadTime/4)
+}
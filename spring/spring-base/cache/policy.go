@@ -0,0 +1,262 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"hash/fnv"
+)
+
+// Policy chooses the keys to be evicted from a bounded Shard, it's called
+// with the lock of the shard held, so it needn't be concurrency safe.
+type Policy interface {
+
+	// Record records an access of the key.
+	Record(key string)
+
+	// Add adds a new key, and returns the keys to be evicted.
+	Add(key string) []string
+
+	// Remove removes the key.
+	Remove(key string)
+}
+
+// lru evicts the least recently used key.
+type lru struct {
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+// NewLRU returns a Policy evicting the least recently used key.
+func NewLRU(capacity int) Policy {
+	return &lru{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (p *lru) Record(key string) {
+	if e, ok := p.items[key]; ok {
+		p.ll.MoveToFront(e)
+	}
+}
+
+func (p *lru) Add(key string) []string {
+	p.items[key] = p.ll.PushFront(key)
+	if p.ll.Len() <= p.capacity {
+		return nil
+	}
+	k := p.ll.Remove(p.ll.Back()).(string)
+	delete(p.items, k)
+	return []string{k}
+}
+
+func (p *lru) Remove(key string) {
+	if e, ok := p.items[key]; ok {
+		p.ll.Remove(e)
+		delete(p.items, key)
+	}
+}
+
+// sketch is a count-min sketch with 4 rows of 4-bit counters, the counters
+// are halved when the number of increments reaches the sample size, so the
+// frequencies of the keys decay over time.
+type sketch struct {
+	rows   [4][]uint8
+	mask   uint64
+	count  int
+	sample int
+}
+
+// newSketch returns a sketch whose rows have 8 counters per key at least,
+// and the counters are halved every 10*capacity increments.
+func newSketch(capacity int) *sketch {
+	width := 64
+	for width < 8*capacity {
+		width <<= 1
+	}
+	s := &sketch{mask: uint64(width - 1), sample: 10 * capacity}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+func (s *sketch) hash(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum64()
+}
+
+func (s *sketch) index(h uint64, i int) uint64 {
+	h += uint64(i) * 0x9e3779b97f4a7c15
+	h ^= h >> 31
+	return h & s.mask
+}
+
+func (s *sketch) increment(key string) {
+	h := s.hash(key)
+	for i := range s.rows {
+		if j := s.index(h, i); s.rows[i][j] < 15 {
+			s.rows[i][j]++
+		}
+	}
+	if s.count++; s.count >= s.sample {
+		s.reset()
+	}
+}
+
+func (s *sketch) estimate(key string) uint8 {
+	h := s.hash(key)
+	min := uint8(15)
+	for i := range s.rows {
+		if v := s.rows[i][s.index(h, i)]; v < min {
+			min = v
+		}
+	}
+	return min
+}
+
+func (s *sketch) reset() {
+	s.count /= 2
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+}
+
+const (
+	segmentWindow = iota
+	segmentProbation
+	segmentProtected
+)
+
+type tinyLFUEntry struct {
+	key     string
+	segment int
+}
+
+// tinyLFU is the W-TinyLFU policy, new keys enter a small LRU window, the
+// keys evicted from the window compete with the victims of the main SLRU
+// space, and the one with the higher estimated frequency is kept.
+type tinyLFU struct {
+	windowCap    int
+	protectedCap int
+	mainCap      int
+	window       *list.List
+	probation    *list.List
+	protected    *list.List
+	items        map[string]*list.Element
+	sketch       *sketch
+}
+
+// NewTinyLFU returns a Policy implementing W-TinyLFU, the window takes 1% of
+// the capacity, and the protected segment takes 80% of the main space.
+func NewTinyLFU(capacity int) Policy {
+	windowCap := capacity / 100
+	if windowCap < 1 {
+		windowCap = 1
+	}
+	mainCap := capacity - windowCap
+	return &tinyLFU{
+		windowCap:    windowCap,
+		protectedCap: mainCap * 8 / 10,
+		mainCap:      mainCap,
+		window:       list.New(),
+		probation:    list.New(),
+		protected:    list.New(),
+		items:        make(map[string]*list.Element),
+		sketch:       newSketch(capacity),
+	}
+}
+
+func (p *tinyLFU) list(segment int) *list.List {
+	switch segment {
+	case segmentWindow:
+		return p.window
+	case segmentProbation:
+		return p.probation
+	default:
+		return p.protected
+	}
+}
+
+func (p *tinyLFU) Record(key string) {
+	p.sketch.increment(key)
+	e, ok := p.items[key]
+	if !ok {
+		return
+	}
+	entry := e.Value.(*tinyLFUEntry)
+	switch entry.segment {
+	case segmentWindow:
+		p.window.MoveToFront(e)
+	case segmentProtected:
+		p.protected.MoveToFront(e)
+	case segmentProbation:
+		p.probation.Remove(e)
+		entry.segment = segmentProtected
+		p.items[key] = p.protected.PushFront(entry)
+		if p.protected.Len() > p.protectedCap {
+			demoted := p.protected.Remove(p.protected.Back()).(*tinyLFUEntry)
+			demoted.segment = segmentProbation
+			p.items[demoted.key] = p.probation.PushFront(demoted)
+		}
+	}
+}
+
+func (p *tinyLFU) Add(key string) []string {
+	p.sketch.increment(key)
+	p.items[key] = p.window.PushFront(&tinyLFUEntry{key: key, segment: segmentWindow})
+	if p.window.Len() <= p.windowCap {
+		return nil
+	}
+	candidate := p.window.Remove(p.window.Back()).(*tinyLFUEntry)
+	if p.probation.Len()+p.protected.Len() < p.mainCap {
+		candidate.segment = segmentProbation
+		p.items[candidate.key] = p.probation.PushFront(candidate)
+		return nil
+	}
+	victims := p.probation
+	if victims.Len() == 0 {
+		victims = p.protected
+	}
+	if victims.Len() == 0 {
+		delete(p.items, candidate.key)
+		return []string{candidate.key}
+	}
+	victim := victims.Back().Value.(*tinyLFUEntry)
+	if p.sketch.estimate(candidate.key) <= p.sketch.estimate(victim.key) {
+		delete(p.items, candidate.key)
+		return []string{candidate.key}
+	}
+	victims.Remove(victims.Back())
+	delete(p.items, victim.key)
+	candidate.segment = segmentProbation
+	p.items[candidate.key] = p.probation.PushFront(candidate)
+	return []string{victim.key}
+}
+
+func (p *tinyLFU) Remove(key string) {
+	if e, ok := p.items[key]; ok {
+		p.list(e.Value.(*tinyLFUEntry).segment).Remove(e)
+		delete(p.items, key)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/cache"
+)
+
+func TestLRU(t *testing.T) {
+	p := cache.NewLRU(2)
+	assert.Nil(t, p.Add("a"))
+	assert.Nil(t, p.Add("b"))
+	p.Record("a")
+	assert.Equal(t, p.Add("c"), []string{"b"})
+	p.Remove("a")
+	assert.Nil(t, p.Add("d"))
+	assert.Equal(t, p.Add("e"), []string{"c"})
+}
+
+func TestTinyLFU(t *testing.T) {
+	m := cache.NewShard(100, cache.NewTinyLFU)
+
+	// hot keys are accessed frequently before a scan of cold keys.
+	for i := 0; i < 50; i++ {
+		m.Store("hot"+strconv.Itoa(i), i)
+	}
+	for j := 0; j < 5; j++ {
+		for i := 0; i < 50; i++ {
+			m.Load("hot" + strconv.Itoa(i))
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		m.Store("cold"+strconv.Itoa(i), i)
+	}
+
+	assert.Equal(t, m.Len(), 100)
+	for i := 0; i < 50; i++ {
+		_, ok := m.Load("hot" + strconv.Itoa(i))
+		assert.True(t, ok)
+	}
+
+	m = cache.NewShard(100, cache.NewLRU)
+	for i := 0; i < 50; i++ {
+		m.Store("hot"+strconv.Itoa(i), i)
+		m.Load("hot" + strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		m.Store("cold"+strconv.Itoa(i), i)
+	}
+	_, ok := m.Load("hot0")
+	assert.False(t, ok)
+}
+
+func TestShard(t *testing.T) {
+	m := cache.NewShard(0, nil)
+	v, loaded := m.LoadOrStore("user:1", 1)
+	assert.False(t, loaded)
+	assert.Equal(t, v, 1)
+	v, loaded = m.LoadOrStore("user:1", 2)
+	assert.True(t, loaded)
+	assert.Equal(t, v, 1)
+	m.Store("user:2", 2)
+	m.Store("order:1", 3)
+	m.DeletePrefix("user:")
+	assert.Equal(t, m.Len(), 1)
+	var keys []string
+	m.Range(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	assert.Equal(t, keys, []string{"order:1"})
+	m.Delete("order:1")
+	assert.Equal(t, m.Len(), 0)
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// counters collects the statistics of a Shard, it's updated by the engine
+// and the shard concurrently.
+type counters struct {
+	hits       int64
+	misses     int64
+	loads      int64
+	loadErrors int64
+	loadTime   int64
+	evictions  int64
+}
+
+func (c *counters) reset() {
+	atomic.StoreInt64(&c.hits, 0)
+	atomic.StoreInt64(&c.misses, 0)
+	atomic.StoreInt64(&c.loads, 0)
+	atomic.StoreInt64(&c.loadErrors, 0)
+	atomic.StoreInt64(&c.loadTime, 0)
+	atomic.StoreInt64(&c.evictions, 0)
+}
+
+func (c *counters) recordHit() {
+	atomic.AddInt64(&c.hits, 1)
+}
+
+func (c *counters) recordMiss() {
+	atomic.AddInt64(&c.misses, 1)
+}
+
+func (c *counters) recordLoad(d time.Duration, err error) {
+	if err != nil {
+		atomic.AddInt64(&c.loadErrors, 1)
+	} else {
+		atomic.AddInt64(&c.loads, 1)
+	}
+	atomic.AddInt64(&c.loadTime, int64(d))
+}
+
+func (c *counters) recordEviction(n int) {
+	atomic.AddInt64(&c.evictions, int64(n))
+}
+
+// Stats is a snapshot of the statistics of a cache.
+type Stats struct {
+	Hits          int64         `json:"hits"`
+	Misses        int64         `json:"misses"`
+	Loads         int64         `json:"loads"`      // successful loads.
+	LoadErrors    int64         `json:"loadErrors"` // failed loads.
+	TotalLoadTime time.Duration `json:"totalLoadTime"`
+	Evictions     int64         `json:"evictions"` // keys evicted by the Policy.
+	Size          int           `json:"size"`
+}
+
+func (s *Stats) add(c *counters, size int) {
+	s.Hits += atomic.LoadInt64(&c.hits)
+	s.Misses += atomic.LoadInt64(&c.misses)
+	s.Loads += atomic.LoadInt64(&c.loads)
+	s.LoadErrors += atomic.LoadInt64(&c.loadErrors)
+	s.TotalLoadTime += time.Duration(atomic.LoadInt64(&c.loadTime))
+	s.Evictions += atomic.LoadInt64(&c.evictions)
+	s.Size += size
+}
+
+// Requests returns the number of hits and misses.
+func (s Stats) Requests() int64 {
+	return s.Hits + s.Misses
+}
+
+// HitRatio returns the ratio of hits to requests, or 1 if there is no request.
+func (s Stats) HitRatio() float64 {
+	n := s.Requests()
+	if n == 0 {
+		return 1
+	}
+	return float64(s.Hits) / float64(n)
+}
+
+// AverageLoadTime returns the average time spent loading values.
+func (s Stats) AverageLoadTime() time.Duration {
+	n := s.Loads + s.LoadErrors
+	if n == 0 {
+		return 0
+	}
+	return s.TotalLoadTime / time.Duration(n)
+}
+
+// StatsOf returns a snapshot of the statistics of the shards.
+func StatsOf(shards []*Shard) Stats {
+	var s Stats
+	for _, m := range shards {
+		s.add(&m.stats, m.Len())
+	}
+	return s
+}
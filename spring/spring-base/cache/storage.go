@@ -16,7 +16,12 @@
 
 package cache
 
-import "sync"
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
 
 // HashFunc returns the hash value of the key.
 type HashFunc func(key string) int
@@ -36,29 +41,282 @@ var (
 	}
 )
 
+// Sweepable is implemented by the values which can tell whether they are
+// expired, Sweep deletes them when Sweepable returns true.
+type Sweepable interface {
+	Sweepable() bool
+}
+
+// Shard stores a part of the keys of a cache, if it's bounded, the keys are
+// evicted by the Policy when the number of keys exceeds the capacity. Reads
+// of an unbounded shard only take a shared lock, a bounded shard locks
+// exclusively on reads because the policy records every access.
+type Shard struct {
+	stats    counters // must be the first field for 64-bit atomic alignment.
+	mutex    sync.RWMutex
+	items    map[string]interface{}
+	capacity int
+	policy   Policy
+}
+
+// NewShard returns a new *Shard, it's unbounded if capacity <= 0, and the
+// default policy of a bounded shard is LRU.
+func NewShard(capacity int, newPolicy func(capacity int) Policy) *Shard {
+	s := &Shard{items: make(map[string]interface{})}
+	if capacity > 0 {
+		if newPolicy == nil {
+			newPolicy = NewLRU
+		}
+		s.capacity = capacity
+		s.policy = newPolicy(capacity)
+	}
+	return s
+}
+
+// Reset deletes all keys and clears the statistics.
+func (s *Shard) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.policy != nil {
+		for key := range s.items {
+			s.policy.Remove(key)
+		}
+	}
+	s.items = make(map[string]interface{})
+	s.stats.reset()
+}
+
+// Len returns the number of the keys.
+func (s *Shard) Len() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return len(s.items)
+}
+
+// Load returns the value of the key, and records the access of the key.
+func (s *Shard) Load(key string) (value interface{}, ok bool) {
+	if s.policy == nil {
+		return s.peek(key)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	value, ok = s.items[key]
+	if ok {
+		s.policy.Record(key)
+	}
+	return
+}
+
+// peek returns the value of the key without recording the access.
+func (s *Shard) peek(key string) (interface{}, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	value, ok := s.items[key]
+	return value, ok
+}
+
+// Store sets the value of the key.
+func (s *Shard) Store(key string, value interface{}) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.items[key]; ok {
+		s.items[key] = value
+		if s.policy != nil {
+			s.policy.Record(key)
+		}
+		return
+	}
+	s.add(key, value)
+}
+
+// LoadOrStore returns the existing value of the key if present, otherwise it
+// stores and returns the given value. The loaded result is true if the value
+// was loaded, false if stored.
+func (s *Shard) LoadOrStore(key string, value interface{}) (actual interface{}, loaded bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if v, ok := s.items[key]; ok {
+		if s.policy != nil {
+			s.policy.Record(key)
+		}
+		return v, true
+	}
+	s.add(key, value)
+	return value, false
+}
+
+// add adds a new key, and then evicts the keys chosen by the policy, the new
+// key itself may be evicted if it's rejected by the policy.
+func (s *Shard) add(key string, value interface{}) {
+	s.items[key] = value
+	if s.policy != nil {
+		evicted := s.policy.Add(key)
+		for _, k := range evicted {
+			delete(s.items, k)
+		}
+		s.stats.recordEviction(len(evicted))
+	}
+}
+
+// Delete deletes the key.
+func (s *Shard) Delete(key string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.delete(key)
+}
+
+func (s *Shard) delete(key string) {
+	if _, ok := s.items[key]; !ok {
+		return
+	}
+	delete(s.items, key)
+	if s.policy != nil {
+		s.policy.Remove(key)
+	}
+}
+
+// DeletePrefix deletes the keys which have the prefix.
+func (s *Shard) DeletePrefix(prefix string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key := range s.items {
+		if strings.HasPrefix(key, prefix) {
+			s.delete(key)
+		}
+	}
+}
+
+// Range calls f for each key and value in a snapshot of the shard, it stops
+// the iteration if f returns false.
+func (s *Shard) Range(f func(key string, value interface{}) bool) {
+	s.mutex.RLock()
+	keys := make([]string, 0, len(s.items))
+	values := make([]interface{}, 0, len(s.items))
+	for k, v := range s.items {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	s.mutex.RUnlock()
+	for i := range keys {
+		if !f(keys[i], values[i]) {
+			return
+		}
+	}
+}
+
+// Sweep deletes the values which implement Sweepable and are expired.
+func (s *Shard) Sweep() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, v := range s.items {
+		if e, ok := v.(Sweepable); ok && e.Sweepable() {
+			s.delete(key)
+		}
+	}
+}
+
+type StorageOption func(*Storage)
+
+// MaxSize bounds the number of the keys of the storage, every shard holds
+// at most ceil(n/size) keys.
+func MaxSize(n int) StorageOption {
+	return func(s *Storage) {
+		s.maxSize = n
+	}
+}
+
+// EvictionPolicy sets the policy which chooses the keys to be evicted from a
+// bounded shard, the default is LRU.
+func EvictionPolicy(newPolicy func(capacity int) Policy) StorageOption {
+	return func(s *Storage) {
+		s.newPolicy = newPolicy
+	}
+}
+
+// SweepInterval starts a background goroutine which sweeps the storage every
+// interval, the goroutine exits when the storage is closed.
+func SweepInterval(interval time.Duration) StorageOption {
+	return func(s *Storage) {
+		s.sweepInterval = interval
+	}
+}
+
 // Storage is a Shardable cache implementation.
 type Storage struct {
-	n int
-	h HashFunc
-	m []*sync.Map
+	n             int
+	h             HashFunc
+	m             []*Shard
+	maxSize       int
+	newPolicy     func(capacity int) Policy
+	sweepInterval time.Duration
+	cancel        context.CancelFunc
 }
 
 // NewStorage returns a new *Storage.
-func NewStorage(size int, hash HashFunc) *Storage {
+func NewStorage(size int, hash HashFunc, opts ...StorageOption) *Storage {
 	s := &Storage{n: size, h: hash}
-	s.Reset()
+	for _, opt := range opts {
+		opt(s)
+	}
+	capacity := 0
+	if s.maxSize > 0 {
+		capacity = (s.maxSize + s.n - 1) / s.n
+	}
+	s.m = make([]*Shard, s.n, s.n)
+	for i := 0; i < s.n; i++ {
+		s.m[i] = NewShard(capacity, s.newPolicy)
+	}
+	if s.sweepInterval > 0 {
+		var ctx context.Context
+		ctx, s.cancel = context.WithCancel(context.Background())
+		go s.SweepEvery(ctx, s.sweepInterval)
+	}
 	return s
 }
 
-// Reset resets the Storage.
+// Close stops the background sweeping started by SweepInterval.
+func (s *Storage) Close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
+// Reset resets every shard of the Storage in place, so it's safe to call
+// while the shards are being used or swept.
 func (s *Storage) Reset() {
-	s.m = make([]*sync.Map, s.n, s.n)
-	for i := 0; i < s.n; i++ {
-		s.m[i] = &sync.Map{}
+	for _, m := range s.m {
+		m.Reset()
 	}
 }
 
 // Sharding returns the shard of the key.
-func (s *Storage) Sharding(key string) *sync.Map {
+func (s *Storage) Sharding(key string) *Shard {
 	return s.m[s.h(key)%s.n]
 }
+
+// Shards returns all shards.
+func (s *Storage) Shards() []*Shard {
+	return s.m
+}
+
+// Sweep deletes the expired items of all shards.
+func (s *Storage) Sweep() {
+	for _, m := range s.m {
+		m.Sweep()
+	}
+}
+
+// SweepEvery sweeps the storage every interval until ctx is done, it's
+// usually run in a background goroutine, see also SweepInterval.
+func (s *Storage) SweepEvery(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Sweep()
+		}
+	}
+}
@@ -4,4 +4,5 @@ go 1.14
 
 require (
 	github.com/golang/mock v1.6.0
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -69,6 +69,18 @@ func Store(ctx context.Context, key string, val interface{}) error {
 	return nil
 }
 
+// Set stores the key and value in the context.Context, if the key is already
+// in the context.Context, the value will be overwritten.
+// If knife is uninitialized, the error of knife uninitialized will be returned.
+func Set(ctx context.Context, key string, val interface{}) error {
+	m, ok := cache(ctx)
+	if !ok {
+		return errUninitialized
+	}
+	m.Store(key, val)
+	return nil
+}
+
 // LoadOrStore returns the existing value for the key if present. Otherwise, it
 // stores and returns the given value. The loaded result is true if the value was
 // loaded, false if stored.
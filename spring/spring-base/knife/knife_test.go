@@ -35,6 +35,9 @@ func TestKnife(t *testing.T) {
 	err = knife.Store(ctx, "a", "b")
 	assert.Error(t, err, "knife uninitialized")
 
+	err = knife.Set(ctx, "a", "b")
+	assert.Error(t, err, "knife uninitialized")
+
 	_, _, err = knife.LoadOrStore(ctx, "a", 3)
 	assert.Error(t, err, "knife uninitialized")
 
@@ -55,6 +58,16 @@ func TestKnife(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, v, "b")
 
+	err = knife.Set(ctx, "a", "c")
+	assert.Nil(t, err)
+
+	v, err = knife.Load(ctx, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, v, "c")
+
+	err = knife.Set(ctx, "a", "b")
+	assert.Nil(t, err)
+
 	{
 		var keys []interface{}
 		knife.Range(ctx, func(key, value interface{}) bool {
@@ -184,9 +184,10 @@ func (enc *JSONEncoder) AppendString(v string) error {
 	return nil
 }
 
-// AppendReflect appends an interface{}.
+// AppendReflect appends an interface{}, the struct fields tagged with
+// `log:"redact"` are masked.
 func (enc *JSONEncoder) AppendReflect(v interface{}) error {
-	b, err := json.Marshal(v)
+	b, err := json.Marshal(redact(v))
 	if err != nil {
 		return err
 	}
@@ -372,12 +373,13 @@ func (enc *FlatEncoder) AppendString(v string) error {
 	return nil
 }
 
-// AppendReflect appends an interface{}.
+// AppendReflect appends an interface{}, the struct fields tagged with
+// `log:"redact"` are masked.
 func (enc *FlatEncoder) AppendReflect(v interface{}) error {
 	if enc.jsonDepth > 0 {
 		return enc.jsonEncoder.AppendReflect(v)
 	}
-	b, err := json.Marshal(v)
+	b, err := json.Marshal(redact(v))
 	if err != nil {
 		return err
 	}
@@ -18,7 +18,9 @@ package log_test
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/go-spring/spring-base/assert"
 	"github.com/go-spring/spring-base/log"
@@ -79,3 +81,76 @@ func TestEncoder(t *testing.T) {
 		assert.Equal(t, buffer.String(), c.expect)
 	}
 }
+
+type redactAccount struct {
+	Name     string `json:"name"`
+	Password string `json:"password" log:"redact"`
+	PIN      int    `json:"pin" log:"redact"`
+}
+
+type redactUser struct {
+	ID       int                       `json:"id"`
+	Account  *redactAccount            `json:"account"`
+	Accounts []redactAccount           `json:"accounts"`
+	Tokens   map[string]*redactAccount `json:"tokens"`
+}
+
+func TestEncoder_Redact(t *testing.T) {
+	user := &redactUser{
+		ID:       1,
+		Account:  &redactAccount{Name: "jim", Password: "123456", PIN: 1234},
+		Accounts: []redactAccount{{Name: "tom", Password: "abc", PIN: 5678}},
+		Tokens:   map[string]*redactAccount{"a": {Name: "lucy", Password: "xyz"}},
+	}
+	buffer := bytes.NewBuffer(nil)
+	encoder := log.NewJSONEncoder(buffer)
+	err := log.Reflect("user", user).Val.Encode(encoder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, buffer.String(), `{"id":1,"account":{"name":"jim","password":"******","pin":0},"accounts":[{"name":"tom","password":"******","pin":0}],"tokens":{"a":{"name":"lucy","password":"******","pin":0}}}`)
+	assert.Equal(t, user.Account.Password, "123456")
+	assert.Equal(t, user.Accounts[0].Password, "abc")
+	assert.Equal(t, user.Tokens["a"].Password, "xyz")
+}
+
+type durationValue time.Duration
+
+func TestRegisterTypeEncoder(t *testing.T) {
+	typ := reflect.TypeOf(durationValue(0))
+	log.RegisterTypeEncoder(typ, func(enc log.Encoder, v interface{}) error {
+		return enc.AppendString(time.Duration(v.(durationValue)).String())
+	})
+	defer log.RegisterTypeEncoder(typ, nil)
+
+	fields := []log.Field{
+		log.Reflect("a", durationValue(time.Second)),
+		log.Reflect("b", []durationValue{durationValue(time.Second)}),
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	encoder := log.NewJSONEncoder(buffer)
+	_ = encoder.AppendEncoderBegin()
+	for _, f := range fields {
+		_ = encoder.AppendKey(f.Key)
+		if err := f.Val.Encode(encoder); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = encoder.AppendEncoderEnd()
+	assert.Equal(t, buffer.String(), `{"a":"1s","b":[1000000000]}`)
+
+	buffer.Reset()
+	encoder2 := log.NewFlatEncoder(buffer, "||")
+	if err := log.Reflect("a", durationValue(time.Minute)).Val.Encode(encoder2); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, buffer.String(), "1m0s")
+
+	log.RegisterTypeEncoder(typ, nil)
+	buffer.Reset()
+	if err := log.Reflect("a", durationValue(time.Second)).Val.Encode(log.NewJSONEncoder(buffer)); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, buffer.String(), "1000000000")
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"reflect"
+	"sync"
+)
+
+// RedactMask is the value used to replace the struct fields tagged with
+// `log:"redact"` when they are encoded by the reflect-based encoder.
+const RedactMask = "******"
+
+// redactTypes caches whether a type contains fields tagged with
+// `log:"redact"`, is safe for map[reflect.Type]bool.
+var redactTypes sync.Map
+
+func isRedactField(f reflect.StructField) bool {
+	return f.Tag.Get("log") == "redact"
+}
+
+// hasRedact returns whether the type t contains fields tagged with
+// `log:"redact"`, directly or through its elements.
+func hasRedact(t reflect.Type) bool {
+	if v, ok := redactTypes.Load(t); ok {
+		return v.(bool)
+	}
+	return checkRedact(t, make(map[reflect.Type]bool))
+}
+
+func checkRedact(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if v, ok := redactTypes.Load(t); ok {
+		return v.(bool)
+	}
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+	ret := false
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		ret = checkRedact(t.Elem(), visiting)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if isRedactField(f) || checkRedact(f.Type, visiting) {
+				ret = true
+				break
+			}
+		}
+	}
+	delete(visiting, t)
+	if ret || len(visiting) == 0 {
+		redactTypes.Store(t, ret)
+	}
+	return ret
+}
+
+// redact returns a copy of v in which the string fields tagged with
+// `log:"redact"` are replaced by RedactMask and other tagged fields are set
+// to zero values, v itself is never modified. Values of interface types are
+// not inspected because their dynamic types are unknown in advance.
+func redact(v interface{}) interface{} {
+	if v == nil || !hasRedact(reflect.TypeOf(v)) {
+		return v
+	}
+	return redactValue(reflect.ValueOf(v)).Interface()
+}
+
+func redactValue(v reflect.Value) reflect.Value {
+	t := v.Type()
+	if !hasRedact(t) {
+		return v
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		p := reflect.New(t.Elem())
+		p.Elem().Set(redactValue(v.Elem()))
+		return p
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		s := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			s.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return s
+	case reflect.Array:
+		a := reflect.New(t).Elem()
+		for i := 0; i < v.Len(); i++ {
+			a.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return a
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		m := reflect.MakeMapWithSize(t, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m.SetMapIndex(iter.Key(), redactValue(iter.Value()))
+		}
+		return m
+	case reflect.Struct:
+		s := reflect.New(t).Elem()
+		s.Set(v)
+		for i := 0; i < t.NumField(); i++ {
+			fv := s.Field(i)
+			if !fv.CanSet() {
+				continue
+			}
+			ft := t.Field(i)
+			if !isRedactField(ft) {
+				fv.Set(redactValue(fv))
+				continue
+			}
+			if ft.Type.Kind() == reflect.String {
+				fv.SetString(RedactMask)
+			} else {
+				fv.Set(reflect.Zero(ft.Type))
+			}
+		}
+		return s
+	}
+	return v
+}
@@ -16,6 +16,11 @@
 
 package log
 
+import (
+	"reflect"
+	"sync"
+)
+
 // Value represents a data and encodes it to an Encoder.
 type Value interface {
 	Encode(enc Encoder) error
@@ -66,11 +71,40 @@ type ReflectValue struct {
 	Val interface{}
 }
 
-// Encode encodes the data represented by v to an Encoder.
+// Encode encodes the data represented by v to an Encoder, the TypeEncoder
+// registered for the dynamic type of v.Val is used if it exists.
 func (v ReflectValue) Encode(enc Encoder) error {
+	if v.Val != nil {
+		if fn, ok := typeEncoders.Load(reflect.TypeOf(v.Val)); ok {
+			return fn.(TypeEncoder)(enc, v.Val)
+		}
+	}
 	return enc.AppendReflect(v.Val)
 }
 
+// TypeEncoder encodes a value of the type it is registered for.
+type TypeEncoder func(enc Encoder, v interface{}) error
+
+// typeEncoders is safe for map[reflect.Type]TypeEncoder.
+var typeEncoders sync.Map
+
+// RegisterTypeEncoder registers a TypeEncoder for the ReflectValue whose
+// dynamic type is t, it replaces the reflect-based encoding, so the struct
+// fields tagged with `log:"redact"` are not masked unless fn does it. Only
+// the top-level value is matched, values nested in structs, slices or maps
+// are encoded by the reflect-based encoder. A nil fn removes the TypeEncoder.
+//
+//	log.RegisterTypeEncoder(reflect.TypeOf(time.Time{}), func(enc log.Encoder, v interface{}) error {
+//		return enc.AppendString(v.(time.Time).Format(time.RFC3339))
+//	})
+func RegisterTypeEncoder(t reflect.Type, fn TypeEncoder) {
+	if fn == nil {
+		typeEncoders.Delete(t)
+		return
+	}
+	typeEncoders.Store(t, fn)
+}
+
 // BoolsValue represents a slice of bool carried by Field.
 type BoolsValue []bool
 
@@ -52,3 +52,13 @@ func BenchmarkCaller(b *testing.B) {
 		}
 	})
 }
+
+func TestGoroutineID(t *testing.T) {
+	id := internal.GoroutineID()
+	assert.True(t, id > 0)
+	ch := make(chan int64)
+	go func() { ch <- internal.GoroutineID() }()
+	other := <-ch
+	assert.True(t, other > 0)
+	assert.NotEqual(t, other, id)
+}
@@ -26,6 +26,7 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/go-spring/spring-base/atomic"
 )
@@ -36,6 +37,10 @@ var configLoggers atomic.Value
 // usingLoggers 用户代码中的 Logger 对象，is safe for map[string]*Logger.
 var usingLoggers sync.Map
 
+// refreshStopTimeout 刷新配置后停止旧配置的最长等待时间，避免远程 Appender
+// 不可用时停止旧配置的协程永远不退出。
+const refreshStopTimeout = 30 * time.Second
+
 type Initializer interface {
 	Init() error
 }
@@ -46,14 +51,15 @@ type LifeCycle interface {
 }
 
 type privateConfigMap struct {
-	loggers map[string]privateConfig
+	loggers   map[string]privateConfig
+	appenders map[string]Appender
 }
 
 func (m *privateConfigMap) Get(name string) privateConfig {
 	if v, ok := m.loggers[name]; ok {
 		return v
 	}
-	return m.loggers["<<ROOT>>"]
+	return m.loggers[RootLoggerName]
 }
 
 type loggerHolder interface {
@@ -78,7 +84,7 @@ func (h *initLoggerHolder) Get() *Logger {
 	h.once.Do(func() {
 		h.logger = newLogger(h.name)
 		m := configLoggers.Load().(*privateConfigMap)
-		h.logger.reconfigure(m.Get(h.name))
+		h.logger.reconfigure(m.config(h.name))
 	})
 	return h.logger
 }
@@ -120,21 +126,23 @@ func RefreshBuffer(buffer string, ext string) error {
 // RefreshReader 加载日志配置文件。
 func RefreshReader(input io.Reader, ext string) error {
 
-	var rootNode *Node
-	{
-		r, ok := readers[ext]
-		if !ok {
-			return fmt.Errorf("unsupported file type %s", ext)
-		}
-		data, err := ioutil.ReadAll(input)
-		if err != nil {
-			return err
-		}
-		rootNode, err = r.Read(data)
-		if err != nil {
-			return err
-		}
+	r, ok := readers[ext]
+	if !ok {
+		return fmt.Errorf("unsupported file type %s", ext)
+	}
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	rootNode, err := r.Read(data)
+	if err != nil {
+		return err
 	}
+	return RefreshNode(rootNode)
+}
+
+// RefreshNode 使用已经解析的配置节点刷新日志配置。
+func RefreshNode(rootNode *Node) error {
 
 	if rootNode.Label != "Configuration" {
 		return errors.New("the Configuration root not found")
@@ -172,7 +180,7 @@ func RefreshReader(input io.Reader, ext string) error {
 				if cRoot != nil {
 					return errors.New("found more than one root loggers")
 				}
-				c.Attributes["name"] = "<<ROOT>>"
+				c.Attributes["name"] = RootLoggerName
 			}
 
 			p, ok := plugins[c.Label]
@@ -202,18 +210,16 @@ func RefreshReader(input io.Reader, ext string) error {
 
 	for name, config := range cLoggers {
 
-		var base *baseLoggerConfig
-		switch v := config.(type) {
-		case *loggerConfig:
-			base = &v.baseLoggerConfig
-		case *asyncLoggerConfig:
-			base = &v.baseLoggerConfig
-		}
-
+		base := config.getBase()
 		if name != cRoot.getName() {
 			base.root = cRoot
 		}
 
+		// 运行时设置的日志级别在重新加载配置之后仍然有效。
+		if v, ok := levels.Load(name); ok {
+			base.override.Store(int32(v.(Level)))
+		}
+
 		for _, r := range base.AppenderRefs {
 			appender, ok := cAppenders[r.Ref]
 			if !ok {
@@ -223,15 +229,94 @@ func RefreshReader(input io.Reader, ext string) error {
 		}
 	}
 
-	m := &privateConfigMap{cLoggers}
+	if err := start(cAppenders, cLoggers); err != nil {
+		return err
+	}
+
+	m := &privateConfigMap{loggers: cLoggers, appenders: cAppenders}
+	old, _ := configLoggers.Load().(*privateConfigMap)
 	configLoggers.Store(m)
 
 	// 对用户代码中的 Logger 对象应用最新的配置。
 	usingLoggers.Range(func(key, value interface{}) bool {
 		l := value.(loggerHolder).Get()
-		l.reconfigure(m.Get(key.(string)))
+		l.reconfigure(m.config(key.(string)))
 		return true
 	})
 
+	// 旧配置的异步队列中可能还有事件，处理完这些事件之后再停止旧的 Appender 。
+	if old != nil {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), refreshStopTimeout)
+			defer cancel()
+			_ = old.stop(ctx)
+		}()
+	}
+	return nil
+}
+
+// start 启动所有的 Appender 和异步 Logger ，出错时停止已经启动的对象。
+func start(appenders map[string]Appender, loggers map[string]privateConfig) error {
+	var started []LifeCycle
+	var objects []LifeCycle
+	for _, a := range appenders {
+		objects = append(objects, a)
+	}
+	for _, c := range loggers {
+		if v, ok := c.(LifeCycle); ok {
+			objects = append(objects, v)
+		}
+	}
+	for _, v := range objects {
+		if err := v.Start(); err != nil {
+			for _, s := range started {
+				s.Stop(context.Background())
+			}
+			return err
+		}
+		started = append(started, v)
+	}
 	return nil
 }
+
+// stop 在 ctx 结束之前处理完异步 Logger 队列中的事件，然后停止所有的 Appender 。
+func (m *privateConfigMap) stop(ctx context.Context) error {
+	var err error
+	for _, c := range m.loggers {
+		if v, ok := c.(*asyncLoggerConfig); ok {
+			if e := v.stop(ctx); e != nil && err == nil {
+				err = e
+			}
+		}
+	}
+	for _, a := range m.appenders {
+		a.Stop(ctx)
+	}
+	return err
+}
+
+// Stop 停止日志系统，在 ctx 结束之前处理完异步日志队列中的事件，然后停止所
+// 有的 Appender ，返回的错误表示队列中仍有事件未被处理。停止之后异步 Logger
+// 以同步的方式输出日志。
+func Stop(ctx context.Context) error {
+	m, ok := configLoggers.Load().(*privateConfigMap)
+	if !ok {
+		return nil
+	}
+	return m.stop(ctx)
+}
+
+// DroppedEvents 返回每个异步 Logger 因为队列已满而丢弃的事件数量。
+func DroppedEvents() map[string]int64 {
+	ret := make(map[string]int64)
+	m, ok := configLoggers.Load().(*privateConfigMap)
+	if !ok {
+		return ret
+	}
+	for name, c := range m.loggers {
+		if v, ok := c.(*asyncLoggerConfig); ok {
+			ret[name] = v.dropped()
+		}
+	}
+	return ret
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"sync"
+)
+
+type mdcKeyType int
+
+var mdcKey mdcKeyType
+
+// ContextExtractor 从 context.Context 中提取需要输出到日志的字段，比如从
+// tracing 的 span 中提取 traceID 。
+type ContextExtractor func(ctx context.Context) []Field
+
+var (
+	extractorsLock sync.RWMutex
+	extractors     []*ContextExtractor
+)
+
+// RegisterContextExtractor 注册 ContextExtractor ，提取的字段排在 MDC 字段的后面，
+// 返回取消注册的函数，例如单元测试结束时取消注册。
+func RegisterContextExtractor(fn ContextExtractor) (unregister func()) {
+	extractorsLock.Lock()
+	defer extractorsLock.Unlock()
+	p := &fn
+	extractors = append(extractors, p)
+	return func() {
+		extractorsLock.Lock()
+		defer extractorsLock.Unlock()
+		for i, e := range extractors {
+			if e == p {
+				extractors = append(extractors[:i:i], extractors[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// WithMDC 返回保存了 fields 的 context.Context ，已经存在的同名字段会被覆盖。
+// 使用该 context.Context 输出日志时，字段会被 PatternLayout 的 %X{key} 和
+// JSONLayout 自动输出。
+func WithMDC(ctx context.Context, fields ...Field) context.Context {
+	old := MDC(ctx)
+	m := make([]Field, 0, len(old)+len(fields))
+	for _, f := range old {
+		if indexField(fields, f.Key) < 0 {
+			m = append(m, f)
+		}
+	}
+	for i, f := range fields {
+		if indexField(fields[i+1:], f.Key) < 0 {
+			m = append(m, f)
+		}
+	}
+	return context.WithValue(ctx, mdcKey, m)
+}
+
+// MDC 返回 context.Context 中通过 WithMDC 保存的字段。
+func MDC(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	m, _ := ctx.Value(mdcKey).([]Field)
+	return m
+}
+
+// ContextFields 返回 context.Context 中需要输出到日志的字段，包括 MDC 字段和
+// ContextExtractor 提取的字段。
+func ContextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields := MDC(ctx)
+	extractorsLock.RLock()
+	defer extractorsLock.RUnlock()
+	if len(extractors) == 0 {
+		return fields
+	}
+	fields = append([]Field(nil), fields...)
+	for _, fn := range extractors {
+		fields = append(fields, (*fn)(ctx)...)
+	}
+	return fields
+}
+
+// ContextField 返回 context.Context 中名为 key 的日志字段。
+func ContextField(ctx context.Context, key string) (Field, bool) {
+	fields := ContextFields(ctx)
+	if i := indexField(fields, key); i >= 0 {
+		return fields[i], true
+	}
+	return Field{}, false
+}
+
+func indexField(fields []Field, key string) int {
+	for i, f := range fields {
+		if f.Key == key {
+			return i
+		}
+	}
+	return -1
+}
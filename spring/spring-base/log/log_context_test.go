@@ -0,0 +1,68 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+)
+
+type spanKey struct{}
+
+func TestContextFields(t *testing.T) {
+
+	assert.Nil(t, log.ContextFields(nil))
+
+	ctx := log.WithMDC(context.Background(), log.String("a", "1"), log.String("b", "2"))
+	ctx = log.WithMDC(ctx, log.String("a", "3"), log.String("c", "4"), log.String("c", "5"))
+	assert.Equal(t, log.MDC(ctx), []log.Field{
+		log.String("b", "2"),
+		log.String("a", "3"),
+		log.String("c", "5"),
+	})
+
+	unregister := log.RegisterContextExtractor(func(ctx context.Context) []log.Field {
+		if v, ok := ctx.Value(spanKey{}).(string); ok {
+			return []log.Field{log.String("span", v)}
+		}
+		return nil
+	})
+	t.Cleanup(unregister)
+
+	ctx = context.WithValue(ctx, spanKey{}, "s1")
+	assert.Equal(t, log.ContextFields(ctx), []log.Field{
+		log.String("b", "2"),
+		log.String("a", "3"),
+		log.String("c", "5"),
+		log.String("span", "s1"),
+	})
+
+	f, ok := log.ContextField(ctx, "span")
+	assert.True(t, ok)
+	assert.Equal(t, f, log.String("span", "s1"))
+
+	_, ok = log.ContextField(ctx, "none")
+	assert.False(t, ok)
+
+	unregister()
+	unregister()
+	_, ok = log.ContextField(ctx, "span")
+	assert.False(t, ok)
+}
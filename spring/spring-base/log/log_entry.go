@@ -45,10 +45,11 @@ type publisher interface {
 
 // Entry is an Entry implementation that has context and errno.
 type Entry struct {
-	ctx  context.Context
-	pub  publisher
-	tag  string
-	skip int
+	ctx    context.Context
+	pub    publisher
+	logger string
+	tag    string
+	skip   int
 }
 
 func (e *Entry) WithSkip(n int) *Entry {
@@ -200,6 +201,7 @@ func publish(p publisher, level Level, skip int, e *Entry, format string, args [
 	message := getMessage(format, args)
 	file, line, _ := internal.Caller(skip+2, true)
 	event := &Event{
+		Logger:  e.logger,
 		Tag:     e.tag,
 		Time:    clock.Now(e.ctx),
 		Context: e.ctx,
@@ -209,6 +211,9 @@ func publish(p publisher, level Level, skip int, e *Entry, format string, args [
 		Fields:  fields,
 		Message: message,
 	}
+	if len(args) > 0 {
+		event.Format = format
+	}
 	p.publish(event)
 	return event
 }
@@ -31,4 +31,14 @@ type Event struct {
 	Tag     string
 	Fields  []Field
 	Message string
+	Logger  string
+
+	// Format is the format of Message given to the methods like Infof, it is
+	// empty when Message is not formatted.
+	Format string
+
+	// Goroutine is the ID of the publishing goroutine, it is captured only
+	// by async loggers whose appenders lay out :goid, zero means the event is
+	// laid out on the publishing goroutine.
+	Goroutine int64
 }
@@ -22,8 +22,10 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-spring/spring-base/code"
+	"github.com/go-spring/spring-base/log/queue"
 	"github.com/go-spring/spring-base/util"
 )
 
@@ -32,6 +34,8 @@ var converters = map[reflect.Type]util.Converter{}
 func init() {
 	RegisterConverter(ParseLevel)
 	RegisterConverter(ParseColorStyle)
+	RegisterConverter(queue.ParsePolicy)
+	RegisterConverter(time.ParseDuration)
 }
 
 // RegisterConverter registers Converter for non-primitive type such as
@@ -64,17 +64,17 @@ func (l *Logger) Appenders() []Appender {
 
 // WithSkip 创建包含 skip 信息的 Entry 。
 func (l *Logger) WithSkip(n int) *Entry {
-	return &Entry{pub: l.config(), skip: n}
+	return &Entry{pub: l.config(), logger: l.name, skip: n}
 }
 
 // WithTag 创建包含 tag 信息的 Entry 。
 func (l *Logger) WithTag(tag string) *Entry {
-	return &Entry{pub: l.config(), tag: tag}
+	return &Entry{pub: l.config(), logger: l.name, tag: tag}
 }
 
 // WithContext 创建包含 context.Context 对象的 Entry 。
 func (l *Logger) WithContext(ctx context.Context) *Entry {
-	return &Entry{pub: l.config(), ctx: ctx}
+	return &Entry{pub: l.config(), logger: l.name, ctx: ctx}
 }
 
 // Trace outputs log with level TraceLevel.
@@ -0,0 +1,246 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RootLoggerName 根 Logger 的名称。
+const RootLoggerName = "<<ROOT>>"
+
+var (
+	// levelsLock 保证设置日志级别的操作按顺序执行。
+	levelsLock sync.Mutex
+
+	// levels 运行时设置的日志级别，is safe for map[string]Level.
+	levels sync.Map
+
+	// captures 运行时添加的捕获日志的 Appender ，is safe for map[string]*captureConfig.
+	captures sync.Map
+)
+
+// LoggerInfo Logger 的运行时信息。
+type LoggerInfo struct {
+	Name            string   // Logger 的名称
+	Level           Level    // 生效的日志级别
+	ConfiguredLevel Level    // 配置文件中的日志级别
+	Overridden      bool     // 是否在运行时设置了日志级别
+	Appenders       []string // 输出日志的 Appender ，包括从根 Logger 继承的
+}
+
+// levelConfig 在运行时为配置文件中不存在的 Logger 设置日志级别，除日志级别
+// 之外的行为和根 Logger 一致。
+type levelConfig struct {
+	privateConfig
+	name  string
+	level Level
+}
+
+func (c *levelConfig) getName() string {
+	return c.name
+}
+
+func (c *levelConfig) getLevel() Level {
+	return c.level
+}
+
+func (c *levelConfig) enableLevel(level Level) bool {
+	return level >= c.level
+}
+
+// captureConfig 将 Logger 输出的日志同时输出到捕获日志的 Appender 。
+type captureConfig struct {
+	privateConfig
+	appender Appender
+	level    Level
+}
+
+func (c *captureConfig) enableLevel(level Level) bool {
+	return level >= c.level || c.privateConfig.enableLevel(level)
+}
+
+func (c *captureConfig) publish(e *Event) {
+	if e.Level >= c.level {
+		c.appender.Append(e)
+	}
+	if c.privateConfig.enableLevel(e.Level) {
+		c.privateConfig.publish(e)
+	}
+}
+
+// config 返回名为 name 的 Logger 使用的配置，包括运行时设置的日志级别和捕获
+// 日志的 Appender 。
+func (m *privateConfigMap) config(name string) privateConfig {
+	c := m.Get(name)
+	if c.getName() != name {
+		if v, ok := levels.Load(name); ok {
+			c = &levelConfig{privateConfig: c, name: name, level: v.(Level)}
+		}
+	}
+	if v, ok := captures.Load(name); ok {
+		capture := *v.(*captureConfig)
+		capture.privateConfig = c
+		c = &capture
+	}
+	return c
+}
+
+// applyLevel 对名为 name 的 Logger 应用运行时设置的日志级别。
+func (m *privateConfigMap) applyLevel(name string) {
+	level := NoneLevel
+	if v, ok := levels.Load(name); ok {
+		level = v.(Level)
+	}
+	if c, ok := m.loggers[name]; ok {
+		c.getBase().override.Store(int32(level))
+		return
+	}
+	m.reconfigure(name)
+}
+
+// reconfigure 对用户代码中名为 name 的 Logger 应用最新的配置。
+func (m *privateConfigMap) reconfigure(name string) {
+	if v, ok := usingLoggers.Load(name); ok {
+		v.(loggerHolder).Get().reconfigure(m.config(name))
+	}
+}
+
+func loadConfigMap() (*privateConfigMap, error) {
+	m, ok := configLoggers.Load().(*privateConfigMap)
+	if !ok {
+		return nil, errors.New("should call refresh first")
+	}
+	return m, nil
+}
+
+// SetLevel 在运行时设置名为 name 的 Logger 的日志级别，不影响其他的 Logger ，
+// 重新加载配置文件之后仍然有效。设置根 Logger 的日志级别时使用 RootLoggerName 。
+func SetLevel(name string, level Level) error {
+	if level <= NoneLevel || level > OffLevel {
+		return fmt.Errorf("invalid level %s", level)
+	}
+	m, err := loadConfigMap()
+	if err != nil {
+		return err
+	}
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+	levels.Store(name, level)
+	m.applyLevel(name)
+	return nil
+}
+
+// ResetLevel 取消运行时设置的日志级别，恢复使用配置文件中的日志级别。
+func ResetLevel(name string) error {
+	m, err := loadConfigMap()
+	if err != nil {
+		return err
+	}
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+	levels.Delete(name)
+	m.applyLevel(name)
+	return nil
+}
+
+// Capture 将名为 name 的 Logger 输出的 level 及以上级别的日志同时输出到
+// appender ，即使 Logger 的日志级别高于 level 。返回的函数用于取消捕获，重新
+// 加载配置文件不会取消捕获。同一个 Logger 只能有一个捕获日志的 Appender ，
+// 后添加的会替换先添加的。
+func Capture(name string, level Level, appender Appender) (cancel func(), err error) {
+	m, err := loadConfigMap()
+	if err != nil {
+		return nil, err
+	}
+	levelsLock.Lock()
+	defer levelsLock.Unlock()
+	c := &captureConfig{appender: appender, level: level}
+	captures.Store(name, c)
+	m.reconfigure(name)
+	return func() {
+		levelsLock.Lock()
+		defer levelsLock.Unlock()
+		if v, ok := captures.Load(name); ok && v == c {
+			captures.Delete(name)
+		}
+		if m, err := loadConfigMap(); err == nil {
+			m.reconfigure(name)
+		}
+	}, nil
+}
+
+// Loggers 返回配置文件中的 Logger 、用户代码中的 Logger 以及运行时设置过日志
+// 级别的 Logger 的信息，按照名称排序。
+func Loggers() ([]*LoggerInfo, error) {
+	m, err := loadConfigMap()
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{})
+	for name := range m.loggers {
+		names[name] = struct{}{}
+	}
+	collect := func(key, value interface{}) bool {
+		names[key.(string)] = struct{}{}
+		return true
+	}
+	usingLoggers.Range(collect)
+	levels.Range(collect)
+	var ret []*LoggerInfo
+	for name := range names {
+		ret = append(ret, m.loggerInfo(name))
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret, nil
+}
+
+// GetLoggerInfo 返回名为 name 的 Logger 的信息。
+func GetLoggerInfo(name string) (*LoggerInfo, error) {
+	m, err := loadConfigMap()
+	if err != nil {
+		return nil, err
+	}
+	return m.loggerInfo(name), nil
+}
+
+func (m *privateConfigMap) loggerInfo(name string) *LoggerInfo {
+	c := m.config(name)
+	base := c.getBase()
+	_, overridden := levels.Load(name)
+	info := &LoggerInfo{
+		Name:            name,
+		Level:           c.getLevel(),
+		ConfiguredLevel: base.Level,
+		Overridden:      overridden,
+	}
+	for {
+		for _, r := range base.AppenderRefs {
+			info.Appenders = append(info.Appenders, r.Ref)
+		}
+		if base.root == nil || !base.Additivity {
+			break
+		}
+		base = base.root.getBase()
+	}
+	return info
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+)
+
+func TestSetLevel(t *testing.T) {
+
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Console name="Console"/>
+				<Console name="Other"/>
+			</Appenders>
+			<Loggers>
+				<Logger name="level/configured" level="warn" additivity="false">
+					<AppenderRef ref="Other"/>
+				</Logger>
+				<Root level="info">
+					<AppenderRef ref="Console"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+
+	err := log.RefreshBuffer(config, ".xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configured := log.GetLogger("level/configured")
+	other := log.GetLogger("level/other")
+	root := log.GetLogger(log.RootLoggerName)
+
+	assert.Equal(t, configured.Level(), log.WarnLevel)
+	assert.Equal(t, other.Level(), log.InfoLevel)
+	assert.Nil(t, other.Debug("debug"))
+
+	err = log.SetLevel("level/other", log.NoneLevel)
+	assert.Error(t, err, "invalid level NONE")
+
+	err = log.SetLevel("level/other", log.DebugLevel)
+	assert.Nil(t, err)
+	assert.Equal(t, other.Level(), log.DebugLevel)
+	assert.NotNil(t, other.Debug("debug"))
+	assert.Equal(t, root.Level(), log.InfoLevel)
+	assert.Equal(t, configured.Level(), log.WarnLevel)
+
+	err = log.SetLevel(log.RootLoggerName, log.ErrorLevel)
+	assert.Nil(t, err)
+	assert.Equal(t, root.Level(), log.ErrorLevel)
+	assert.Equal(t, other.Level(), log.DebugLevel)
+	assert.Equal(t, log.GetLogger("level/another").Level(), log.ErrorLevel)
+
+	err = log.SetLevel("level/configured", log.TraceLevel)
+	assert.Nil(t, err)
+	assert.Equal(t, configured.Level(), log.TraceLevel)
+
+	info, err := log.GetLoggerInfo("level/configured")
+	assert.Nil(t, err)
+	assert.Equal(t, info, &log.LoggerInfo{
+		Name:            "level/configured",
+		Level:           log.TraceLevel,
+		ConfiguredLevel: log.WarnLevel,
+		Overridden:      true,
+		Appenders:       []string{"Other"},
+	})
+
+	// 重新加载配置文件之后运行时设置的日志级别仍然有效。
+	err = log.RefreshBuffer(config, ".xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, configured.Level(), log.TraceLevel)
+	assert.Equal(t, other.Level(), log.DebugLevel)
+	assert.Equal(t, root.Level(), log.ErrorLevel)
+
+	loggers, err := log.Loggers()
+	assert.Nil(t, err)
+	var found bool
+	for _, l := range loggers {
+		if l.Name == "level/other" {
+			found = true
+			assert.Equal(t, l, &log.LoggerInfo{
+				Name:            "level/other",
+				Level:           log.DebugLevel,
+				ConfiguredLevel: log.InfoLevel,
+				Overridden:      true,
+				Appenders:       []string{"Console"},
+			})
+		}
+	}
+	assert.True(t, found)
+
+	for _, name := range []string{"level/configured", "level/other", log.RootLoggerName} {
+		err = log.ResetLevel(name)
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, configured.Level(), log.WarnLevel)
+	assert.Equal(t, other.Level(), log.InfoLevel)
+	assert.Equal(t, root.Level(), log.InfoLevel)
+}
@@ -17,10 +17,18 @@
 package log
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
 )
 
 var (
@@ -29,6 +37,9 @@ var (
 
 func init() {
 	RegisterReader(new(XMLReader), ".xml")
+	RegisterReader(new(JSONReader), ".json")
+	RegisterReader(new(YAMLReader), ".yaml", ".yml")
+	RegisterReader(new(PropertiesReader), ".properties")
 }
 
 type Node struct {
@@ -93,3 +104,336 @@ func (r *XMLReader) Read(b []byte) (*Node, error) {
 	}
 	return stack[0].Children[0], nil
 }
+
+// JSONReader 解析 JSON 格式的配置，结构参见 NewNode 函数，同一层级的子节点保持
+// 文档中的顺序。
+type JSONReader struct{}
+
+func (r *JSONReader) Read(b []byte) (*Node, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	v, err := decodeJSON(d)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(orderedMap)
+	if !ok {
+		return nil, errors.New("error json config")
+	}
+	return newConfigNode(m)
+}
+
+// decodeJSON 解析一个 JSON 值，对象解析为 orderedMap 以保持 key 的顺序。
+func decodeJSON(d *json.Decoder) (interface{}, error) {
+	token, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		var m orderedMap
+		for d.More() {
+			k, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeJSON(d)
+			if err != nil {
+				return nil, err
+			}
+			m = append(m, mapEntry{key: k.(string), value: v})
+		}
+		if _, err = d.Token(); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case json.Delim('['):
+		var arr []interface{}
+		for d.More() {
+			v, err := decodeJSON(d)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		if _, err = d.Token(); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	default:
+		return token, nil
+	}
+}
+
+// YAMLReader 解析 YAML 格式的配置，结构参见 NewNode 函数，同一层级的子节点保持
+// 文档中的顺序。
+type YAMLReader struct{}
+
+func (r *YAMLReader) Read(b []byte) (*Node, error) {
+	var m yaml.MapSlice
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	c, _ := toOrderedMap(m)
+	return newConfigNode(c)
+}
+
+// PropertiesReader 解析 properties 格式的配置，结构参见 NewNodeFromProperties
+// 函数，同一层级的子节点按照属性在文件中第一次出现的顺序排列。
+type PropertiesReader struct{}
+
+func (r *PropertiesReader) Read(b []byte) (*Node, error) {
+	var keys []string
+	m := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid properties line %q", line)
+		}
+		key := strings.TrimSpace(line[:i])
+		if _, ok := m[key]; !ok {
+			keys = append(keys, key)
+		}
+		m[key] = strings.TrimSpace(line[i+1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return newNodeFromProperties(keys, m)
+}
+
+type mapEntry struct {
+	key   string
+	value interface{}
+}
+
+// orderedMap 保持 key 顺序的 map 。
+type orderedMap []mapEntry
+
+func (m orderedMap) index(key string) int {
+	for i, e := range m {
+		if e.key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// newConfigNode 返回 Configuration 节点，m 可以只包含 Configuration 一个 key ，
+// 也可以直接是 Configuration 的内容。
+func newConfigNode(m orderedMap) (*Node, error) {
+	if len(m) == 1 && m[0].key == "Configuration" {
+		c, ok := toOrderedMap(m[0].value)
+		if !ok {
+			return nil, errors.New("the Configuration should be a map")
+		}
+		m = c
+	}
+	return newNode("Configuration", m)
+}
+
+// NewNode 将嵌套的 map 转换为 label 节点，值为 map 的 key 转换为子节点，值为
+// 数组的 key 转换为多个同名的子节点，其他的 key 转换为节点的属性，例如:
+//
+//	Appenders:
+//	  Console:
+//	    name: Console
+//	Loggers:
+//	  Logger:
+//	    - name: a
+//	      level: debug
+//	      AppenderRef:
+//	        ref: Console
+//	  Root:
+//	    level: info
+//
+// map 没有顺序，所以同一层级的子节点按照 label 排序，需要保持顺序的同名子节点
+// 可以使用数组。JSONReader 和 YAMLReader 保持文档中的顺序。
+func NewNode(label string, m map[string]interface{}) (*Node, error) {
+	c, _ := toOrderedMap(m)
+	return newNode(label, c)
+}
+
+func newNode(label string, m orderedMap) (*Node, error) {
+	node := &Node{Label: label, Attributes: make(map[string]string)}
+	for _, e := range m {
+		k, v := e.key, e.value
+		if v == nil {
+			node.Children = append(node.Children, &Node{Label: k, Attributes: make(map[string]string)})
+			continue
+		}
+		if c, ok := toOrderedMap(v); ok {
+			child, err := newNode(k, c)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = append(node.Children, child)
+			continue
+		}
+		if arr, ok := v.([]interface{}); ok {
+			for i, e := range arr {
+				c, ok := toOrderedMap(e)
+				if !ok && e != nil {
+					return nil, fmt.Errorf("element %s[%d] should be a map", k, i)
+				}
+				child, err := newNode(k, c)
+				if err != nil {
+					return nil, err
+				}
+				node.Children = append(node.Children, child)
+			}
+			continue
+		}
+		node.Attributes[k] = fmt.Sprint(v)
+	}
+	return node, nil
+}
+
+// toOrderedMap 将 JSON 、YAML 和属性解析出的 map 统一转换为 orderedMap ，普通
+// map 的 key 按照字母顺序排列。
+func toOrderedMap(v interface{}) (orderedMap, bool) {
+	switch m := v.(type) {
+	case orderedMap:
+		return m, true
+	case yaml.MapSlice:
+		r := make(orderedMap, 0, len(m))
+		for _, e := range m {
+			r = append(r, mapEntry{key: fmt.Sprint(e.Key), value: e.Value})
+		}
+		return r, true
+	case map[string]interface{}:
+		r := make(orderedMap, 0, len(m))
+		for k, e := range m {
+			r = append(r, mapEntry{key: k, value: e})
+		}
+		sort.Slice(r, func(i, j int) bool { return r[i].key < r[j].key })
+		return r, true
+	case map[interface{}]interface{}:
+		r := make(orderedMap, 0, len(m))
+		for k, e := range m {
+			r = append(r, mapEntry{key: fmt.Sprint(k), value: e})
+		}
+		sort.Slice(r, func(i, j int) bool { return r[i].key < r[j].key })
+		return r, true
+	}
+	return nil, false
+}
+
+// NewNodeFromProperties 将扁平的属性转换为 Configuration 节点，key 使用 . 分
+// 隔层级，使用 [i] 表示同名子节点的序号，例如:
+//
+//	Appenders.Console.name=Console
+//	Loggers.Logger[0].name=a
+//	Loggers.Logger[0].AppenderRef.ref=Console
+//	Loggers.Root.level=info
+//
+// key 可以带有 Configuration. 前缀。map 没有顺序，所以同一层级的子节点按照
+// key 排序，需要保持顺序的同名子节点可以使用 [i] 。
+func NewNodeFromProperties(m map[string]string) (*Node, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return newNodeFromProperties(keys, m)
+}
+
+// newNodeFromProperties 按照 keys 的顺序转换属性。
+func newNodeFromProperties(keys []string, m map[string]string) (*Node, error) {
+	var root interface{}
+	for _, key := range keys {
+		path, err := splitPropertyKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if root, err = setPropertyValue(root, path, m[key], key); err != nil {
+			return nil, err
+		}
+	}
+	if root == nil {
+		return newConfigNode(nil)
+	}
+	c, ok := root.(orderedMap)
+	if !ok {
+		return nil, errors.New("error properties config")
+	}
+	return newConfigNode(c)
+}
+
+// splitPropertyKey 将 a.b[0].c 拆分为 "a"、"b"、0、"c" 。
+func splitPropertyKey(key string) ([]interface{}, error) {
+	var path []interface{}
+	for _, s := range strings.Split(key, ".") {
+		name := s
+		var indexes []interface{}
+		if i := strings.IndexByte(s, '['); i >= 0 {
+			name = s[:i]
+			for _, t := range strings.Split(s[i+1:], "[") {
+				if !strings.HasSuffix(t, "]") {
+					return nil, fmt.Errorf("invalid property key %q", key)
+				}
+				n, err := strconv.Atoi(t[:len(t)-1])
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid property key %q", key)
+				}
+				indexes = append(indexes, n)
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid property key %q", key)
+		}
+		path = append(path, name)
+		path = append(path, indexes...)
+	}
+	return path, nil
+}
+
+func setPropertyValue(v interface{}, path []interface{}, val string, key string) (interface{}, error) {
+	if len(path) == 0 {
+		if v != nil {
+			return nil, fmt.Errorf("property %q conflicts with other properties", key)
+		}
+		return val, nil
+	}
+	var err error
+	switch p := path[0].(type) {
+	case string:
+		m, ok := v.(orderedMap)
+		if v == nil {
+			ok = true
+		}
+		if !ok {
+			return nil, fmt.Errorf("property %q conflicts with other properties", key)
+		}
+		i := m.index(p)
+		if i < 0 {
+			m = append(m, mapEntry{key: p})
+			i = len(m) - 1
+		}
+		if m[i].value, err = setPropertyValue(m[i].value, path[1:], val, key); err != nil {
+			return nil, err
+		}
+		return m, nil
+	default:
+		i := p.(int)
+		s, ok := v.([]interface{})
+		if v == nil {
+			ok = true
+		}
+		if !ok {
+			return nil, fmt.Errorf("property %q conflicts with other properties", key)
+		}
+		for len(s) <= i {
+			s = append(s, nil)
+		}
+		if s[i], err = setPropertyValue(s[i], path[1:], val, key); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
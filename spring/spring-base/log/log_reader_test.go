@@ -131,3 +131,176 @@ func TestXMLReader(t *testing.T) {
 		},
 	})
 }
+
+func TestStructuredReader(t *testing.T) {
+
+	expect := &log.Node{
+		Label:      "Configuration",
+		Attributes: map[string]string{},
+		Children: []*log.Node{
+			{
+				Label:      "Appenders",
+				Attributes: map[string]string{},
+				Children: []*log.Node{
+					{
+						Label:      "Console",
+						Attributes: map[string]string{"name": "Console"},
+						Children: []*log.Node{
+							{
+								Label:      "LevelFilter",
+								Attributes: map[string]string{"level": "warn"},
+							},
+						},
+					},
+				},
+			},
+			{
+				Label:      "Loggers",
+				Attributes: map[string]string{},
+				Children: []*log.Node{
+					{
+						Label:      "Logger",
+						Attributes: map[string]string{"name": "a", "level": "debug", "additivity": "false"},
+						Children: []*log.Node{
+							{
+								Label:      "AppenderRef",
+								Attributes: map[string]string{"ref": "Console"},
+								Children: []*log.Node{
+									{
+										Label:      "Filters",
+										Attributes: map[string]string{},
+										Children: []*log.Node{
+											{
+												Label:      "LevelFilter",
+												Attributes: map[string]string{"level": "info"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Label:      "Logger",
+						Attributes: map[string]string{"name": "b", "level": "info"},
+					},
+					{
+						Label:      "Root",
+						Attributes: map[string]string{"level": "debug"},
+						Children: []*log.Node{
+							{
+								Label:      "AppenderRef",
+								Attributes: map[string]string{"ref": "Console"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b := `{
+			"Configuration": {
+				"Appenders": {
+					"Console": {"name": "Console", "LevelFilter": {"level": "warn"}}
+				},
+				"Loggers": {
+					"Logger": [
+						{
+							"name": "a", "level": "debug", "additivity": false,
+							"AppenderRef": {"ref": "Console", "Filters": {"LevelFilter": {"level": "info"}}}
+						},
+						{"name": "b", "level": "info"}
+					],
+					"Root": {"level": "debug", "AppenderRef": {"ref": "Console"}}
+				}
+			}
+		}`
+		node, err := new(log.JSONReader).Read([]byte(b))
+		assert.Nil(t, err)
+		assert.Equal(t, node, expect)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		b := `
+Appenders:
+  Console:
+    name: Console
+    LevelFilter:
+      level: warn
+Loggers:
+  Logger:
+    - name: a
+      level: debug
+      additivity: false
+      AppenderRef:
+        ref: Console
+        Filters:
+          LevelFilter:
+            level: info
+    - name: b
+      level: info
+  Root:
+    level: debug
+    AppenderRef:
+      ref: Console
+`
+		node, err := new(log.YAMLReader).Read([]byte(b))
+		assert.Nil(t, err)
+		assert.Equal(t, node, expect)
+	})
+
+	t.Run("properties", func(t *testing.T) {
+		b := `
+# appenders
+Appenders.Console.name=Console
+Appenders.Console.LevelFilter.level=warn
+! loggers
+Loggers.Logger[0].name=a
+Loggers.Logger[0].level=debug
+Loggers.Logger[0].additivity=false
+Loggers.Logger[0].AppenderRef.ref=Console
+Loggers.Logger[0].AppenderRef.Filters.LevelFilter.level=info
+Loggers.Logger[1].name=b
+Loggers.Logger[1].level: info
+Loggers.Root.level=debug
+Loggers.Root.AppenderRef.ref=Console
+`
+		node, err := new(log.PropertiesReader).Read([]byte(b))
+		assert.Nil(t, err)
+		assert.Equal(t, node, expect)
+	})
+
+	t.Run("order", func(t *testing.T) {
+		labels := func(node *log.Node) []string {
+			var ret []string
+			for _, c := range node.Children {
+				ret = append(ret, c.Label)
+			}
+			return ret
+		}
+		expect := []string{"ThresholdFilter", "LevelFilter", "AppenderRef"}
+		node, err := new(log.JSONReader).Read([]byte(`{"Root": {"ThresholdFilter": {}, "LevelFilter": {}, "AppenderRef": {}}}`))
+		assert.Nil(t, err)
+		assert.Equal(t, labels(node.Children[0]), expect)
+		node, err = new(log.YAMLReader).Read([]byte("Root:\n  ThresholdFilter: {}\n  LevelFilter: {}\n  AppenderRef: {}\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, labels(node.Children[0]), expect)
+		node, err = new(log.PropertiesReader).Read([]byte("Root.ThresholdFilter.level=info\nRoot.LevelFilter.level=warn\nRoot.AppenderRef.ref=a\n"))
+		assert.Nil(t, err)
+		assert.Equal(t, labels(node.Children[0]), expect)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := log.NewNodeFromProperties(map[string]string{
+			"Loggers.Root":       "x",
+			"Loggers.Root.level": "debug",
+		})
+		assert.Error(t, err, "conflicts with other properties")
+		_, err = log.NewNodeFromProperties(map[string]string{"Loggers.Logger[a].name": "a"})
+		assert.Error(t, err, "invalid property key \"Loggers.Logger\\[a\\].name\"")
+		_, err = new(log.YAMLReader).Read([]byte("Loggers:\n  Logger:\n    - a\n"))
+		assert.Error(t, err, "element Logger\\[0\\] should be a map")
+	})
+}
@@ -0,0 +1,298 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/clock"
+)
+
+func init() {
+	RegisterPlugin("Slog", PluginTypeAppender, (*SlogAppender)(nil))
+}
+
+// FromSlogLevel 返回 slog 的日志级别对应的日志级别，低于 slog.LevelDebug 的
+// 为 TraceLevel ，高于 slog.LevelError 的为 ErrorLevel 。
+func FromSlogLevel(level slog.Level) Level {
+	switch {
+	case level < slog.LevelDebug:
+		return TraceLevel
+	case level < slog.LevelInfo:
+		return DebugLevel
+	case level < slog.LevelWarn:
+		return InfoLevel
+	case level < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+// ToSlogLevel 返回日志级别对应的 slog 的日志级别。
+func ToSlogLevel(level Level) slog.Level {
+	switch {
+	case level <= TraceLevel:
+		return slog.LevelDebug - 4
+	case level == DebugLevel:
+		return slog.LevelDebug
+	case level == InfoLevel:
+		return slog.LevelInfo
+	case level == WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// SlogHandler 将 slog 的日志输出到 Logger 的 slog.Handler ，属性转换为 Field ，
+// 分组转换为 Object 类型的 Field 。
+type SlogHandler struct {
+	logger *Logger
+	groups []string
+	attrs  [][]Field // attrs[0] 为顶层的属性，attrs[i] 为 groups[i-1] 的属性
+}
+
+// NewSlogHandler 返回将日志输出到 logger 的 slog.Handler ，用法如下：
+//
+//	slog.SetDefault(slog.New(log.NewSlogHandler(log.GetLogger("slog"))))
+func NewSlogHandler(logger *Logger) *SlogHandler {
+	return &SlogHandler{logger: logger, attrs: make([][]Field, 1)}
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.logger.config().enableLevel(FromSlogLevel(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	t := r.Time
+	if t.IsZero() {
+		t = clock.Now(ctx)
+	}
+	var file string
+	var line int
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		file, line = frame.File, frame.Line
+	}
+	var fields []Field
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+	h.logger.emit(&Event{
+		Context: ctx,
+		Level:   FromSlogLevel(r.Level),
+		Time:    t,
+		File:    file,
+		Line:    line,
+		Fields:  h.nest(fields),
+		Message: r.Message,
+	})
+	return nil
+}
+
+// nest 将 fields 放入当前的分组中，没有属性的分组会被忽略。
+func (h *SlogHandler) nest(fields []Field) []Field {
+	for i := len(h.groups); i > 0; i-- {
+		group := append(append([]Field(nil), h.attrs[i]...), fields...)
+		fields = nil
+		if len(group) > 0 {
+			fields = []Field{Object(h.groups[i-1], group...)}
+		}
+	}
+	return append(append([]Field(nil), h.attrs[0]...), fields...)
+}
+
+func (h *SlogHandler) clone() *SlogHandler {
+	c := &SlogHandler{logger: h.logger}
+	c.groups = append(c.groups, h.groups...)
+	c.attrs = append(c.attrs, h.attrs...)
+	return c
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	c := h.clone()
+	n := len(c.attrs) - 1
+	fields := append([]Field(nil), c.attrs[n]...)
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	c.attrs[n] = fields
+	return c
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := h.clone()
+	c.groups = append(c.groups, name)
+	c.attrs = append(c.attrs, nil)
+	return c
+}
+
+// appendAttr 将 slog.Attr 转换为 Field ，空的属性和没有属性的分组会被忽略，
+// 名称为空的分组中的属性会被展开。
+func appendAttr(fields []Field, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindGroup:
+		var group []Field
+		for _, attr := range v.Group() {
+			group = appendAttr(group, attr)
+		}
+		if len(group) == 0 {
+			return fields
+		}
+		if a.Key == "" {
+			return append(fields, group...)
+		}
+		return append(fields, Object(a.Key, group...))
+	case slog.KindString:
+		return append(fields, String(a.Key, v.String()))
+	case slog.KindInt64:
+		return append(fields, Int64(a.Key, v.Int64()))
+	case slog.KindUint64:
+		return append(fields, Uint64(a.Key, v.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, Float64(a.Key, v.Float64()))
+	case slog.KindBool:
+		return append(fields, Bool(a.Key, v.Bool()))
+	case slog.KindDuration:
+		return append(fields, String(a.Key, v.Duration().String()))
+	case slog.KindTime:
+		return append(fields, String(a.Key, v.Time().Format(time.RFC3339Nano)))
+	default:
+		if err, ok := v.Any().(error); ok {
+			return append(fields, String(a.Key, err.Error()))
+		}
+		return append(fields, Any(a.Key, v.Any()))
+	}
+}
+
+var slogHandlers sync.Map
+
+// RegisterSlogHandler 注册名为 name 的 slog.Handler ，供配置文件中的 Slog
+// Appender 引用。注意不要引用把日志输出到 SlogHandler 的 slog.Handler ，否则
+// 会导致循环调用。
+func RegisterSlogHandler(name string, h slog.Handler) {
+	slogHandlers.Store(name, h)
+}
+
+// SlogAppender 将日志转发到 slog.Handler 的 Appender ，Handler 为空时使用
+// RegisterSlogHandler 注册的名为 HandlerName 的 slog.Handler 。消息不经过
+// Layout 格式化，Field 和 context.Context 中的字段转换为 slog.Attr 。
+//
+//	<Slog name="slog" handler="json"/>
+type SlogAppender struct {
+	Name        string `PluginAttribute:"name"`
+	HandlerName string `PluginAttribute:"handler,default="`
+	Handler     slog.Handler
+}
+
+func (c *SlogAppender) Start() error {
+	if c.Handler != nil {
+		return nil
+	}
+	v, ok := slogHandlers.Load(c.HandlerName)
+	if !ok {
+		return fmt.Errorf("slog handler %s not found", c.HandlerName)
+	}
+	c.Handler = v.(slog.Handler)
+	return nil
+}
+
+func (c *SlogAppender) Stop(ctx context.Context) {}
+func (c *SlogAppender) GetName() string          { return c.Name }
+func (c *SlogAppender) GetLayout() Layout        { return nil }
+
+func (c *SlogAppender) Append(e *Event) {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	level := ToSlogLevel(e.Level)
+	if !c.Handler.Enabled(ctx, level) {
+		return
+	}
+	r := slog.NewRecord(e.Time, level, e.Message, 0)
+	for _, f := range e.Fields {
+		r.AddAttrs(toSlogAttr(f))
+	}
+	for _, f := range ContextFields(e.Context) {
+		r.AddAttrs(toSlogAttr(f))
+	}
+	if e.Tag != "" {
+		r.AddAttrs(slog.String("tag", e.Tag))
+	}
+	_ = c.Handler.Handle(ctx, r)
+}
+
+// toSlogAttr 将 Field 转换为 slog.Attr ，Object 类型的 Field 转换为分组。
+func toSlogAttr(f Field) slog.Attr {
+	return slog.Attr{Key: f.Key, Value: toSlogValue(f.Val)}
+}
+
+func toSlogValue(v Value) slog.Value {
+	switch x := v.(type) {
+	case BoolValue:
+		return slog.BoolValue(bool(x))
+	case Int64Value:
+		return slog.Int64Value(int64(x))
+	case Uint64Value:
+		return slog.Uint64Value(uint64(x))
+	case Float64Value:
+		return slog.Float64Value(float64(x))
+	case StringValue:
+		return slog.StringValue(string(x))
+	case ReflectValue:
+		return slog.AnyValue(x.Val)
+	case ObjectValue:
+		attrs := make([]slog.Attr, 0, len(x))
+		for _, f := range x {
+			attrs = append(attrs, toSlogAttr(f))
+		}
+		return slog.GroupValue(attrs...)
+	case ArrayValue:
+		s := make([]interface{}, 0, len(x))
+		for _, val := range x {
+			s = append(s, toSlogValue(val).Any())
+		}
+		return slog.AnyValue(s)
+	default:
+		return slog.AnyValue(v)
+	}
+}
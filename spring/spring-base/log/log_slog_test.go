@@ -0,0 +1,130 @@
+//go:build go1.21
+// +build go1.21
+
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+)
+
+func TestSlogLevel(t *testing.T) {
+	assert.Equal(t, log.FromSlogLevel(slog.LevelDebug-1), log.TraceLevel)
+	assert.Equal(t, log.FromSlogLevel(slog.LevelDebug), log.DebugLevel)
+	assert.Equal(t, log.FromSlogLevel(slog.LevelInfo+1), log.InfoLevel)
+	assert.Equal(t, log.FromSlogLevel(slog.LevelWarn), log.WarnLevel)
+	assert.Equal(t, log.FromSlogLevel(slog.LevelError+4), log.ErrorLevel)
+	assert.Equal(t, log.ToSlogLevel(log.TraceLevel), slog.LevelDebug-4)
+	assert.Equal(t, log.ToSlogLevel(log.InfoLevel), slog.LevelInfo)
+	assert.Equal(t, log.ToSlogLevel(log.FatalLevel), slog.LevelError)
+}
+
+func TestSlogHandler(t *testing.T) {
+	c := refreshCapture(t, "slog")
+
+	logger := slog.New(log.NewSlogHandler(log.GetLogger("slog")))
+	logger.Debug("debug", "a", 1)
+	assert.Equal(t, len(c.Events()), 1)
+
+	err := log.SetLevel("slog", log.InfoLevel)
+	assert.Nil(t, err)
+	defer func() { _ = log.ResetLevel("slog") }()
+	logger.Debug("disabled")
+	assert.Equal(t, len(c.Events()), 1)
+
+	logger = logger.With("app", "demo").WithGroup("req").With("id", 7).WithGroup("empty")
+	logger.Warn("hello", "cost", time.Second, slog.Group("user", "name", "jim", "vip", true), "err", errors.New("oops"))
+	logger.Info("no attrs")
+
+	events := c.Events()
+	assert.Equal(t, len(events), 3)
+
+	e := events[1]
+	assert.Equal(t, e.Level, log.WarnLevel)
+	assert.Equal(t, e.Message, "hello")
+	assert.True(t, strings.HasSuffix(e.File, "log_slog_test.go"))
+	assert.Equal(t, e.Fields, []log.Field{
+		log.String("app", "demo"),
+		log.Object("req",
+			log.Int64("id", 7),
+			log.Object("empty",
+				log.String("cost", "1s"),
+				log.Object("user", log.String("name", "jim"), log.Bool("vip", true)),
+				log.String("err", "oops"),
+			),
+		),
+	})
+
+	e = events[2]
+	assert.Equal(t, e.Fields, []log.Field{
+		log.String("app", "demo"),
+		log.Object("req", log.Int64("id", 7)),
+	})
+}
+
+func TestSlogAppender(t *testing.T) {
+
+	buf := bytes.NewBuffer(nil)
+	log.RegisterSlogHandler("text", slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Slog name="Slog" handler="text"/>
+			</Appenders>
+			<Loggers>
+				<Root level="debug">
+					<AppenderRef ref="Slog"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	assert.Nil(t, err)
+
+	ctx := log.WithMDC(context.Background(), log.String("traceID", "0001"))
+	logger := log.GetLogger("slog/appender")
+	logger.Debug("disabled")
+	logger.WithContext(ctx).WithTag("_com_request_in").Infow(
+		log.Int("status", 200),
+		log.Object("user", log.String("name", "jim")),
+	)
+	assert.Equal(t, buf.String(), "level=INFO msg=\"\" status=200 user.name=jim traceID=0001 tag=_com_request_in\n")
+
+	config = strings.Replace(config, `handler="text"`, `handler="xxx"`, 1)
+	err = log.RefreshBuffer(config, ".xml")
+	assert.Error(t, err, "slog handler xxx not found")
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/go-spring/spring-base/clock"
+)
+
+// emit 发布由桥接器创建的事件，事件的文件和行号等信息由调用者提供。
+func (l *Logger) emit(e *Event) bool {
+	c := l.config()
+	if !c.enableLevel(e.Level) {
+		return false
+	}
+	if e.Logger == "" {
+		e.Logger = l.name
+	}
+	c.publish(e)
+	return true
+}
+
+// stdWriter 将标准库 log 包输出的每一行作为一条日志消息。
+type stdWriter struct {
+	logger *Logger
+	level  Level
+}
+
+// NewStdWriter 返回把标准库 log 包的输出转发到 logger 的 io.Writer ，日志级别
+// 为 level ，用法如下：
+//
+//	stdlog.SetFlags(0)
+//	stdlog.SetOutput(log.NewStdWriter(log.GetLogger("std"), log.InfoLevel))
+//
+// 日志的文件和行号是调用标准库 log 包的位置，建议关闭标准库 log 包的 flags
+// 以免重复输出时间等信息。
+func NewStdWriter(logger *Logger, level Level) io.Writer {
+	return &stdWriter{logger: logger, level: level}
+}
+
+func (w *stdWriter) Write(p []byte) (int, error) {
+	ctx := context.Background()
+	file, line := stdCaller()
+	w.logger.emit(&Event{
+		Context: ctx,
+		Level:   w.level,
+		Time:    clock.Now(ctx),
+		File:    file,
+		Line:    line,
+		Message: strings.TrimSuffix(string(p), "\n"),
+	})
+	return len(p), nil
+}
+
+// stdCaller 返回调用标准库 log 包的位置，跳过标准库 log 包内部的调用。
+func stdCaller() (file string, line int) {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "log.") {
+			return frame.File, frame.Line
+		}
+		if !more {
+			return "", 0
+		}
+	}
+}
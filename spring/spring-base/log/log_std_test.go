@@ -0,0 +1,69 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log_test
+
+import (
+	stdlog "log"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+)
+
+// refreshCapture 使用名为 name 的 MemoryAppender 作为根 Logger 的 Appender 。
+func refreshCapture(t *testing.T, name string) *log.MemoryAppender {
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Memory name="` + name + `"/>
+			</Appenders>
+			<Loggers>
+				<Root level="debug">
+					<AppenderRef ref="` + name + `"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return log.GetLogger(log.RootLoggerName).Appenders()[0].(*log.MemoryAppender)
+}
+
+func TestStdWriter(t *testing.T) {
+	c := refreshCapture(t, "std")
+
+	logger := stdlog.New(log.NewStdWriter(log.GetLogger("std"), log.WarnLevel), "", 0)
+	logger.Println("hello std log")
+	logger.Printf("%d items", 3)
+
+	events := c.Events()
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].Level, log.WarnLevel)
+	assert.Equal(t, events[0].Message, "hello std log")
+	assert.Equal(t, events[1].Message, "3 items")
+	assert.Equal(t, events[0].Logger, "std")
+	assert.True(t, strings.HasSuffix(events[0].File, "log_std_test.go"))
+	assert.True(t, events[0].Line > 0)
+
+	logger = stdlog.New(log.NewStdWriter(log.GetLogger("std"), log.TraceLevel), "", 0)
+	logger.Println("disabled")
+	assert.Equal(t, len(c.Events()), 2)
+}
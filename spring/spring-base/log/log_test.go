@@ -794,3 +794,104 @@ func TestEntry(t *testing.T) {
 	assert.Equal(t, msg.Context, ctx)
 	//assert.Equal(t, msg.Msg().Text(), "Level:fatal")
 }
+
+type slowAppender struct {
+	log.BaseAppender
+	count int32
+}
+
+var slowAppenders = make(chan *slowAppender, 1)
+
+func (c *slowAppender) Start() error {
+	slowAppenders <- c
+	return nil
+}
+
+func (c *slowAppender) Append(e *log.Event) {
+	time.Sleep(5 * time.Millisecond)
+	atomic.AddInt32(&c.count, 1)
+}
+
+func init() {
+	log.RegisterPlugin("Slow", log.PluginTypeAppender, (*slowAppender)(nil))
+}
+
+func TestAsyncLogger(t *testing.T) {
+
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Slow name="Slow"/>
+			</Appenders>
+			<Loggers>
+				<AsyncLogger name="async" level="info" bufferSize="2" policy="DropNewest" additivity="false">
+					<AppenderRef ref="Slow"/>
+				</AsyncLogger>
+				<Root level="info"/>
+			</Loggers>
+		</Configuration>
+	`
+
+	err := log.RefreshBuffer(config, ".xml")
+	assert.Nil(t, err)
+	appender := <-slowAppenders
+
+	logger := log.GetLogger("async")
+	for i := 0; i < 10; i++ {
+		logger.Info(i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = log.Stop(ctx)
+	assert.Nil(t, err)
+
+	dropped := log.DroppedEvents()["async"]
+	assert.True(t, dropped > 0)
+	assert.Equal(t, int64(atomic.LoadInt32(&appender.count))+dropped, int64(10))
+
+	// 停止之后同步输出日志
+	logger.Info(10)
+	assert.Equal(t, int64(atomic.LoadInt32(&appender.count))+dropped, int64(11))
+}
+
+func TestAsyncLoggerGoroutine(t *testing.T) {
+
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Memory name="plain"/>
+				<Memory name="goid">
+					<PatternLayout pattern="[:goid] :msg"/>
+				</Memory>
+			</Appenders>
+			<Loggers>
+				<AsyncLogger name="async/plain" level="info" additivity="false">
+					<AppenderRef ref="plain"/>
+				</AsyncLogger>
+				<AsyncLogger name="async/goid" level="info">
+					<AppenderRef ref="plain"/>
+				</AsyncLogger>
+				<Root level="info">
+					<AppenderRef ref="goid"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+
+	err := log.RefreshBuffer(config, ".xml")
+	assert.Nil(t, err)
+
+	// 只有在布局需要时才获取 goroutine ID 。
+	e := log.GetLogger("async/plain").Info("plain")
+	assert.Equal(t, e.Goroutine, int64(0))
+	e = log.GetLogger("async/goid").Info("goid")
+	assert.True(t, e.Goroutine > 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = log.Stop(ctx)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package logtest 提供在测试中捕获和断言日志的工具。
+package logtest
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+)
+
+// DefaultSize Recorder 默认保存的日志数量。
+const DefaultSize = 1000
+
+// Recorder 保存捕获的日志。
+type Recorder struct {
+	appender *log.MemoryAppender
+}
+
+// Capture 在测试期间捕获名为 name 的 Logger 输出的所有级别的日志，日志仍然
+// 会按照原来的配置输出，测试结束时自动取消捕获。调用之前需要加载日志配置。
+func Capture(t testing.TB, name string) *Recorder {
+	t.Helper()
+	return CaptureN(t, name, DefaultSize)
+}
+
+// CaptureN 和 Capture 相同，只保存最近的 size 条日志。
+func CaptureN(t testing.TB, name string, size int) *Recorder {
+	t.Helper()
+	appender := log.NewMemoryAppender("logtest", size)
+	if err := appender.Start(); err != nil {
+		t.Fatal(err)
+	}
+	cancel, err := log.Capture(name, log.TraceLevel, appender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cancel)
+	return &Recorder{appender: appender}
+}
+
+// Events 返回捕获的日志，按照输出的顺序排列。
+func (r *Recorder) Events() []*log.Event {
+	return r.appender.Events()
+}
+
+// Reset 清空捕获的日志。
+func (r *Recorder) Reset() {
+	r.appender.Reset()
+}
+
+// Find 返回满足所有条件的日志。
+func (r *Recorder) Find(matchers ...Matcher) []*log.Event {
+	var ret []*log.Event
+	for _, e := range r.Events() {
+		if matchAll(e, matchers) {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// AssertLogged 断言存在满足所有条件的日志。
+func (r *Recorder) AssertLogged(t assert.T, matchers ...Matcher) {
+	t.Helper()
+	found := len(r.Find(matchers...)) > 0
+	assert.True(t, found, "found no event matching "+describe(matchers))
+}
+
+// AssertNotLogged 断言不存在满足所有条件的日志。
+func (r *Recorder) AssertNotLogged(t assert.T, matchers ...Matcher) {
+	t.Helper()
+	found := len(r.Find(matchers...)) > 0
+	assert.False(t, found, "found event matching "+describe(matchers))
+}
+
+// AssertCount 断言满足所有条件的日志的数量为 n 。
+func (r *Recorder) AssertCount(t assert.T, n int, matchers ...Matcher) {
+	t.Helper()
+	count := len(r.Find(matchers...))
+	assert.Equal(t, count, n, "count of events matching "+describe(matchers))
+}
+
+// Matcher 日志的匹配条件。
+type Matcher struct {
+	desc  string
+	match func(e *log.Event) bool
+}
+
+// String 返回匹配条件的描述。
+func (m Matcher) String() string {
+	return m.desc
+}
+
+// Match 返回日志是否满足匹配条件。
+func (m Matcher) Match(e *log.Event) bool {
+	return m.match(e)
+}
+
+func matchAll(e *log.Event, matchers []Matcher) bool {
+	for _, m := range matchers {
+		if !m.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func describe(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return "any"
+	}
+	var s []string
+	for _, m := range matchers {
+		s = append(s, m.desc)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Level 匹配日志级别为 level 的日志。
+func Level(level log.Level) Matcher {
+	return Matcher{
+		desc: "level=" + level.String(),
+		match: func(e *log.Event) bool {
+			return e.Level == level
+		},
+	}
+}
+
+// Message 匹配消息满足正则表达式 expr 的日志。
+func Message(expr string) Matcher {
+	r := regexp.MustCompile(expr)
+	return Matcher{
+		desc: "message=~" + expr,
+		match: func(e *log.Event) bool {
+			return r.MatchString(e.Message)
+		},
+	}
+}
+
+// Tag 匹配标签为 tag 的日志。
+func Tag(tag string) Matcher {
+	return Matcher{
+		desc: "tag=" + tag,
+		match: func(e *log.Event) bool {
+			return e.Tag == tag
+		},
+	}
+}
+
+// Field 匹配包含名为 key 值为 val 的字段的日志，包括 context.Context 中的字段，
+// val 按照 log.Any 的规则转换为 log.Value 之后进行比较。
+func Field(key string, val interface{}) Matcher {
+	expect, ok := val.(log.Value)
+	if !ok {
+		expect = log.Any(key, val).Val
+	}
+	return Matcher{
+		desc: fmt.Sprintf("%s=%v", key, val),
+		match: func(e *log.Event) bool {
+			for _, fields := range [][]log.Field{e.Fields, log.ContextFields(e.Context)} {
+				for _, f := range fields {
+					if f.Key == key && reflect.DeepEqual(f.Val, expect) {
+						return true
+					}
+				}
+			}
+			return false
+		},
+	}
+}
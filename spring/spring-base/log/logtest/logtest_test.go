@@ -0,0 +1,97 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package logtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/log/logtest"
+	"github.com/go-spring/spring-base/util"
+	"github.com/golang/mock/gomock"
+)
+
+func init() {
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Null name="Null"/>
+			</Appenders>
+			<Loggers>
+				<Root level="warn">
+					<AppenderRef ref="Null"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	util.Panic(err).When(err != nil)
+}
+
+func TestCapture(t *testing.T) {
+
+	logger := log.GetLogger("logtest/capture")
+	other := log.GetLogger("logtest/other")
+
+	t.Run("capture", func(t *testing.T) {
+		r := logtest.Capture(t, "logtest/capture")
+
+		ctx := log.WithMDC(context.Background(), log.String("traceID", "0001"))
+		logger.Debugf("user %s login", "jim")
+		logger.WithContext(ctx).WithTag("_com_request_out").Errorw(log.Int("status", 500), log.String("path", "/login"))
+		other.Error("not captured")
+
+		assert.Equal(t, len(r.Events()), 2)
+		r.AssertLogged(t, logtest.Level(log.DebugLevel), logtest.Message(`^user \w+ login$`))
+		r.AssertLogged(t, logtest.Tag("_com_request_out"), logtest.Field("status", 500), logtest.Field("traceID", "0001"))
+		r.AssertNotLogged(t, logtest.Message("not captured"))
+		r.AssertCount(t, 1, logtest.Level(log.ErrorLevel))
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		g := assert.NewMockT(ctrl)
+		g.EXPECT().Helper().AnyTimes()
+		g.EXPECT().Error([]interface{}{"got false but expect true; found no event matching level=INFO, tag=x"})
+		r.AssertLogged(g, logtest.Level(log.InfoLevel), logtest.Tag("x"))
+		g.EXPECT().Error([]interface{}{"got true but expect false; found event matching status=500"})
+		r.AssertNotLogged(g, logtest.Field("status", log.Int64Value(500)))
+		g.EXPECT().Error([]interface{}{"got (int) 2 but expect (int) 3; count of events matching any"})
+		r.AssertCount(g, 3)
+
+		r.Reset()
+		assert.Equal(t, len(r.Events()), 0)
+	})
+
+	// 测试结束之后取消捕获，Logger 恢复原来的配置。
+	assert.Nil(t, logger.Debug("not enabled"))
+	assert.Equal(t, logger.Level(), log.WarnLevel)
+}
+
+func TestCaptureN(t *testing.T) {
+	r := logtest.CaptureN(t, "logtest/ring", 2)
+	logger := log.GetLogger("logtest/ring")
+	for _, msg := range []string{"a", "b", "c"} {
+		logger.Info(msg)
+	}
+	events := r.Events()
+	assert.Equal(t, len(events), 2)
+	assert.Equal(t, events[0].Message, "b")
+	assert.Equal(t, events[1].Message, "c")
+}
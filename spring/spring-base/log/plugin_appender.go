@@ -18,7 +18,9 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sync"
 )
 
 func init() {
@@ -26,6 +28,7 @@ func init() {
 	RegisterPlugin("Console", PluginTypeAppender, (*ConsoleAppender)(nil))
 	RegisterPlugin("File", PluginTypeAppender, (*FileAppender)(nil))
 	RegisterPlugin("RollingFile", PluginTypeAppender, (*RollingFileAppender)(nil))
+	RegisterPlugin("Memory", PluginTypeAppender, (*MemoryAppender)(nil))
 }
 
 // Appender represents an output destination.
@@ -42,6 +45,7 @@ var (
 	_ Appender = (*ConsoleAppender)(nil)
 	_ Appender = (*FileAppender)(nil)
 	_ Appender = (*RollingFileAppender)(nil)
+	_ Appender = (*MemoryAppender)(nil)
 )
 
 type BaseAppender struct {
@@ -113,3 +117,63 @@ type RollingFileAppender struct {
 func (c *RollingFileAppender) Append(e *Event) {
 
 }
+
+// MemoryAppender is an Appender keeping the latest Size events in memory, it
+// is mostly used by tests to check the events.
+type MemoryAppender struct {
+	BaseAppender
+	Size   int `PluginAttribute:"size,default=1000"`
+	lock   sync.Mutex
+	events []*Event
+	next   int
+	full   bool
+}
+
+// NewMemoryAppender returns a MemoryAppender keeping the latest size events.
+func NewMemoryAppender(name string, size int) *MemoryAppender {
+	return &MemoryAppender{BaseAppender: BaseAppender{Name: name}, Size: size}
+}
+
+func (c *MemoryAppender) Start() error {
+	if c.Size <= 0 {
+		return fmt.Errorf("invalid memory appender size %d", c.Size)
+	}
+	return nil
+}
+
+func (c *MemoryAppender) Append(e *Event) {
+	if c.Size <= 0 {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.events == nil {
+		c.events = make([]*Event, c.Size)
+	}
+	c.events[c.next] = e
+	if c.next++; c.next == len(c.events) {
+		c.next = 0
+		c.full = true
+	}
+}
+
+// Events returns the kept events from the oldest to the newest.
+func (c *MemoryAppender) Events() []*Event {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.full {
+		return append([]*Event(nil), c.events[:c.next]...)
+	}
+	ret := make([]*Event, 0, len(c.events))
+	ret = append(ret, c.events[c.next:]...)
+	return append(ret, c.events[:c.next]...)
+}
+
+// Reset removes all the kept events.
+func (c *MemoryAppender) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.events = nil
+	c.next = 0
+	c.full = false
+}
@@ -0,0 +1,316 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/atomic"
+)
+
+func init() {
+	RegisterPlugin("Socket", PluginTypeAppender, (*SocketAppender)(nil))
+	RegisterPlugin("Syslog", PluginTypeAppender, (*SyslogAppender)(nil))
+}
+
+var (
+	_ Appender = (*SocketAppender)(nil)
+	_ Appender = (*SyslogAppender)(nil)
+)
+
+// SocketAppender is an Appender sending messages to a tcp, udp or unix socket.
+// Messages are put into a bounded buffer and sent by a background goroutine,
+// when the connection is broken the goroutine reconnects with exponential
+// backoff between ReconnectDelay and MaxReconnectDelay, messages are kept in
+// the buffer meanwhile and the new ones are dropped when the buffer is full.
+type SocketAppender struct {
+	BaseAppender
+	Network           string        `PluginAttribute:"network,default=tcp"`
+	Address           string        `PluginAttribute:"address"`
+	BufferSize        int           `PluginAttribute:"bufferSize,default=10000"`
+	DialTimeout       time.Duration `PluginAttribute:"dialTimeout,default=3s"`
+	WriteTimeout      time.Duration `PluginAttribute:"writeTimeout,default=3s"`
+	ReconnectDelay    time.Duration `PluginAttribute:"reconnectDelay,default=100ms"`
+	MaxReconnectDelay time.Duration `PluginAttribute:"maxReconnectDelay,default=30s"`
+	buffer            chan []byte
+	dropped           atomic.Int64
+	conn              net.Conn
+	stop              chan struct{}
+	stopOnce          sync.Once
+	abort             chan struct{}
+	exited            chan struct{}
+}
+
+func (c *SocketAppender) Start() error {
+	switch c.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return fmt.Errorf("unsupported network %s", c.Network)
+	}
+	if c.Address == "" {
+		return errors.New("attribute 'address' is empty")
+	}
+	if c.BufferSize <= 0 {
+		return fmt.Errorf("invalid buffer size %d", c.BufferSize)
+	}
+	c.buffer = make(chan []byte, c.BufferSize)
+	c.stop = make(chan struct{})
+	c.abort = make(chan struct{})
+	c.exited = make(chan struct{})
+	go c.loop()
+	return nil
+}
+
+// Stop sends the messages in the buffer before ctx is done, and then closes
+// the connection, the messages appended after stopped are dropped. Messages
+// are not retried while stopping, so the remaining ones are dropped once the
+// endpoint is unreachable, and Stop returns in about DialTimeout+WriteTimeout
+// even if ctx never ends. It's safe to call Stop more than once.
+func (c *SocketAppender) Stop(ctx context.Context) {
+	if c.stop == nil {
+		return
+	}
+	c.stopOnce.Do(func() {
+		close(c.stop)
+		select {
+		case <-c.exited:
+		case <-ctx.Done():
+			close(c.abort)
+			<-c.exited
+		}
+		c.dropped.Add(int64(len(c.buffer)))
+	})
+}
+
+// Dropped returns the count of messages discarded because the buffer is full
+// or the appender is stopped.
+func (c *SocketAppender) Dropped() int64 {
+	return c.dropped.Load()
+}
+
+func (c *SocketAppender) Append(e *Event) {
+	data, err := c.Layout.ToBytes(e)
+	if err != nil {
+		return
+	}
+	c.write(data)
+}
+
+// write puts data into the buffer, data is dropped when the buffer is full.
+func (c *SocketAppender) write(data []byte) {
+	select {
+	case <-c.stop:
+		c.dropped.Add(1)
+		return
+	default:
+	}
+	select {
+	case c.buffer <- data:
+	default:
+		c.dropped.Add(1)
+	}
+}
+
+func (c *SocketAppender) loop() {
+	defer close(c.exited)
+	defer func() {
+		if c.conn != nil {
+			_ = c.conn.Close()
+		}
+	}()
+	for {
+		select {
+		case data := <-c.buffer:
+			if !c.send(data) {
+				return
+			}
+		case <-c.stop:
+			for {
+				select {
+				case data := <-c.buffer:
+					if !c.send(data) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// send writes data to the connection, it reconnects with backoff until data
+// is written or the appender is aborted. Once the appender is stopped, it
+// retries only once more without waiting, returns false when data is dropped.
+func (c *SocketAppender) send(data []byte) bool {
+	delay := c.ReconnectDelay
+	stopping := false
+	for {
+		if c.conn == nil {
+			conn, err := net.DialTimeout(c.Network, c.Address, c.DialTimeout)
+			if err == nil {
+				c.conn = conn
+			}
+		}
+		if c.conn != nil {
+			if c.WriteTimeout > 0 {
+				_ = c.conn.SetWriteDeadline(time.Now().Add(c.WriteTimeout))
+			}
+			if _, err := c.conn.Write(data); err == nil {
+				return true
+			}
+			_ = c.conn.Close()
+			c.conn = nil
+		}
+		if stopping {
+			c.dropped.Add(1)
+			return false
+		}
+		select {
+		case <-c.stop:
+			stopping = true
+			continue
+		case <-c.abort:
+			c.dropped.Add(1)
+			return false
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > c.MaxReconnectDelay {
+			delay = c.MaxReconnectDelay
+		}
+	}
+}
+
+// isStream returns whether the network is stream oriented.
+func (c *SocketAppender) isStream() bool {
+	switch c.Network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		return true
+	}
+	return false
+}
+
+const (
+	SyslogRFC5424 = "RFC5424"
+	SyslogRFC3164 = "RFC3164"
+)
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSeverity returns the syslog severity of the level.
+func syslogSeverity(level Level) int {
+	switch {
+	case level >= FatalLevel:
+		return 1 // alert
+	case level >= PanicLevel:
+		return 2 // critical
+	case level >= ErrorLevel:
+		return 3 // error
+	case level >= WarnLevel:
+		return 4 // warning
+	case level >= InfoLevel:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// SyslogAppender is a SocketAppender sending messages in the RFC5424 or the
+// RFC3164 format, the Layout formats the MSG part. On stream networks the
+// RFC5424 messages are framed by octet counting and the RFC3164 messages are
+// terminated by '\n', as described in RFC6587. The tag of the event is used
+// as the MSGID of RFC5424.
+type SyslogAppender struct {
+	SocketAppender
+	Format   string `PluginAttribute:"format,default=RFC5424"`
+	Facility string `PluginAttribute:"facility,default=user"`
+	AppName  string `PluginAttribute:"appName,default="`
+	Hostname string `PluginAttribute:"hostname,default="`
+	facility int
+	pid      string
+}
+
+func (c *SyslogAppender) Start() error {
+	c.Format = strings.ToUpper(c.Format)
+	if c.Format != SyslogRFC5424 && c.Format != SyslogRFC3164 {
+		return fmt.Errorf("unsupported syslog format %s", c.Format)
+	}
+	facility, ok := syslogFacilities[strings.ToLower(c.Facility)]
+	if !ok {
+		return fmt.Errorf("unsupported syslog facility %s", c.Facility)
+	}
+	c.facility = facility
+	if c.AppName == "" {
+		c.AppName = filepath.Base(os.Args[0])
+	}
+	if c.Hostname == "" {
+		c.Hostname, _ = os.Hostname()
+	}
+	if c.Hostname == "" {
+		c.Hostname = "-"
+	}
+	c.pid = strconv.Itoa(os.Getpid())
+	return c.SocketAppender.Start()
+}
+
+func (c *SyslogAppender) Append(e *Event) {
+	data, err := c.Layout.ToBytes(e)
+	if err != nil {
+		return
+	}
+	c.write(c.format(e, bytes.TrimRight(data, "\n")))
+}
+
+// format returns the framed syslog message.
+func (c *SyslogAppender) format(e *Event, msg []byte) []byte {
+	pri := c.facility*8 + syslogSeverity(e.Level)
+	buf := bytes.NewBuffer(nil)
+	if c.Format == SyslogRFC3164 {
+		fmt.Fprintf(buf, "<%d>%s %s %s[%s]: ", pri, e.Time.Format(time.Stamp), c.Hostname, c.AppName, c.pid)
+		buf.Write(msg)
+		if c.isStream() {
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes()
+	}
+	msgID := e.Tag
+	if msgID == "" {
+		msgID = "-"
+	}
+	timestamp := e.Time.Format("2006-01-02T15:04:05.000000Z07:00")
+	fmt.Fprintf(buf, "<%d>1 %s %s %s %s %s - ", pri, timestamp, c.Hostname, c.AppName, c.pid, msgID)
+	buf.Write(msg)
+	if c.isStream() {
+		return append([]byte(strconv.Itoa(buf.Len())+" "), buf.Bytes()...)
+	}
+	return buf.Bytes()
+}
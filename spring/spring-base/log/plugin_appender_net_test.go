@@ -0,0 +1,293 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package log_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+)
+
+func newMsgLayout(t *testing.T) log.Layout {
+	layout := &log.PatternLayout{Pattern: ":msg"}
+	if err := layout.Init(); err != nil {
+		t.Fatal(err)
+	}
+	return layout
+}
+
+func newMsgEvent(msg string) *log.Event {
+	return &log.Event{
+		Level:   log.InfoLevel,
+		Time:    time.Date(2022, 3, 4, 5, 6, 7, 8000, time.UTC),
+		Message: msg,
+	}
+}
+
+// lineServer 是一个 tcp 服务端，通过 ch 返回读到的每一行数据。
+type lineServer struct {
+	l     net.Listener
+	ch    chan string
+	lock  sync.Mutex
+	conns []net.Conn
+}
+
+func newLineServer(t *testing.T, addr string, ch chan string) *lineServer {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &lineServer{l: l, ch: ch}
+	go s.serve()
+	return s
+}
+
+func (s *lineServer) serve() {
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+		s.lock.Lock()
+		s.conns = append(s.conns, conn)
+		s.lock.Unlock()
+		go func() {
+			r := bufio.NewReader(conn)
+			for {
+				str, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				s.ch <- str
+			}
+		}()
+	}
+}
+
+// Close 关闭监听以及所有已经建立的连接。
+func (s *lineServer) Close() {
+	_ = s.l.Close()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+}
+
+func receive(t *testing.T, ch <-chan string) string {
+	select {
+	case s := <-ch:
+		return s
+	case <-time.After(3 * time.Second):
+		t.Fatal("receive timeout")
+		return ""
+	}
+}
+
+func TestSocketAppender(t *testing.T) {
+
+	t.Run("error", func(t *testing.T) {
+		appender := &log.SocketAppender{Network: "ip", Address: "127.0.0.1:0", BufferSize: 1}
+		assert.Error(t, appender.Start(), "unsupported network ip")
+		appender = &log.SocketAppender{Network: "tcp", BufferSize: 1}
+		assert.Error(t, appender.Start(), "attribute 'address' is empty")
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		ch := make(chan string, 100)
+		server := newLineServer(t, "127.0.0.1:0", ch)
+		addr := server.l.Addr().String()
+
+		appender := &log.SocketAppender{
+			BaseAppender:      log.BaseAppender{Layout: newMsgLayout(t)},
+			Network:           "tcp",
+			Address:           addr,
+			BufferSize:        10,
+			ReconnectDelay:    10 * time.Millisecond,
+			MaxReconnectDelay: 50 * time.Millisecond,
+		}
+		err := appender.Start()
+		assert.Nil(t, err)
+		appender.Append(newMsgEvent("hello"))
+		assert.Equal(t, receive(t, ch), "hello\n")
+
+		// 服务端重启期间的消息保存在缓冲区中，重新连接之后继续发送。
+		server.Close()
+		for i := 0; i < 5; i++ {
+			appender.Append(newMsgEvent(fmt.Sprintf("msg-%d", i)))
+			time.Sleep(10 * time.Millisecond)
+		}
+		server = newLineServer(t, addr, ch)
+		defer server.Close()
+
+		appender.Append(newMsgEvent("last"))
+		appender.Stop(context.Background())
+		assert.Equal(t, appender.Dropped(), int64(0))
+
+		var last string
+		for last != "last\n" {
+			last = receive(t, ch)
+		}
+		appender.Append(newMsgEvent("stopped"))
+		assert.Equal(t, appender.Dropped(), int64(1))
+	})
+
+	t.Run("stop timeout", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := l.Addr().String()
+		_ = l.Close()
+
+		appender := &log.SocketAppender{
+			BaseAppender:      log.BaseAppender{Layout: newMsgLayout(t)},
+			Network:           "tcp",
+			Address:           addr,
+			BufferSize:        2,
+			ReconnectDelay:    10 * time.Millisecond,
+			MaxReconnectDelay: 10 * time.Millisecond,
+		}
+		err = appender.Start()
+		assert.Nil(t, err)
+		for i := 0; i < 10; i++ {
+			appender.Append(newMsgEvent("lost"))
+		}
+		assert.True(t, appender.Dropped() >= 7)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		appender.Stop(ctx)
+		assert.Equal(t, appender.Dropped(), int64(10))
+	})
+
+	t.Run("stop without deadline", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := l.Addr().String()
+		_ = l.Close()
+
+		appender := &log.SocketAppender{
+			BaseAppender:      log.BaseAppender{Layout: newMsgLayout(t)},
+			Network:           "tcp",
+			Address:           addr,
+			BufferSize:        5,
+			DialTimeout:       100 * time.Millisecond,
+			ReconnectDelay:    time.Hour,
+			MaxReconnectDelay: time.Hour,
+		}
+		err = appender.Start()
+		assert.Nil(t, err)
+		for i := 0; i < 3; i++ {
+			appender.Append(newMsgEvent("lost"))
+		}
+		done := make(chan struct{})
+		go func() {
+			appender.Stop(context.Background())
+			appender.Stop(context.Background())
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+			t.Fatal("stop timeout")
+		}
+		assert.Equal(t, appender.Dropped(), int64(3))
+	})
+}
+
+func TestSyslogAppender(t *testing.T) {
+
+	hostname, _ := os.Hostname()
+	pid := os.Getpid()
+
+	t.Run("error", func(t *testing.T) {
+		appender := &log.SyslogAppender{Format: "RFC1", Facility: "user"}
+		assert.Error(t, appender.Start(), "unsupported syslog format RFC1")
+		appender = &log.SyslogAppender{Format: "RFC5424", Facility: "xxx"}
+		assert.Error(t, appender.Start(), "unsupported syslog facility xxx")
+	})
+
+	t.Run("udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		appender := &log.SyslogAppender{
+			SocketAppender: log.SocketAppender{
+				BaseAppender: log.BaseAppender{Layout: newMsgLayout(t)},
+				Network:      "udp",
+				Address:      conn.LocalAddr().String(),
+				BufferSize:   10,
+			},
+			Format:   "RFC5424",
+			Facility: "local0",
+			AppName:  "app",
+		}
+		err = appender.Start()
+		assert.Nil(t, err)
+		defer appender.Stop(context.Background())
+
+		e := newMsgEvent("hello")
+		e.Level = log.ErrorLevel
+		appender.Append(e)
+
+		b := make([]byte, 1024)
+		_ = conn.SetReadDeadline(time.Now().Add(3 * time.Second))
+		n, _, err := conn.ReadFrom(b)
+		assert.Nil(t, err)
+		expect := fmt.Sprintf("<131>1 2022-03-04T05:06:07.000008Z %s app %d - - hello", hostname, pid)
+		assert.Equal(t, string(b[:n]), expect)
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		ch := make(chan string, 10)
+		server := newLineServer(t, "127.0.0.1:0", ch)
+		defer server.Close()
+
+		appender := &log.SyslogAppender{
+			SocketAppender: log.SocketAppender{
+				BaseAppender: log.BaseAppender{Layout: newMsgLayout(t)},
+				Network:      "tcp",
+				Address:      server.l.Addr().String(),
+				BufferSize:   10,
+			},
+			Format:   "rfc3164",
+			Facility: "user",
+			AppName:  "app",
+			Hostname: "host",
+		}
+		err := appender.Start()
+		assert.Nil(t, err)
+		defer appender.Stop(context.Background())
+
+		appender.Append(newMsgEvent("hello"))
+		expect := fmt.Sprintf("<14>Mar  4 05:06:07 host app[%d]: hello\n", pid)
+		assert.Equal(t, receive(t, ch), expect)
+	})
+}
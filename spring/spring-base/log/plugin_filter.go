@@ -20,8 +20,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/go-spring/spring-base/atomic"
 )
 
 func init() {
@@ -33,6 +39,9 @@ func init() {
 	RegisterPlugin("TimeFilter", PluginTypeFilter, (Filter)((*TimeFilter)(nil)))
 	RegisterPlugin("TagFilter", PluginTypeFilter, (Filter)((*TagFilter)(nil)))
 	RegisterPlugin("Filters", PluginTypeFilter, (Filter)((*CompositeFilter)(nil)))
+	RegisterPlugin("RedactFilter", PluginTypeFilter, (Filter)((*RedactFilter)(nil)))
+	RegisterPlugin("SamplingFilter", PluginTypeFilter, (Filter)((*SamplingFilter)(nil)))
+	RegisterPlugin("RedactPattern", "RedactPattern", (*RedactPattern)(nil))
 }
 
 type Result int
@@ -253,3 +262,258 @@ func (f *CompositeFilter) Filter(e *Event) Result {
 	}
 	return ResultAccept
 }
+
+// RedactPattern is a regular expression used by RedactFilter to mask the
+// substrings of messages and string field values, Replace can refer to the
+// capturing groups like $1, the mask of RedactFilter is used when it is empty.
+type RedactPattern struct {
+	Regex   string `PluginAttribute:"regex"`
+	Replace string `PluginAttribute:"replace,default="`
+	regexp  *regexp.Regexp
+}
+
+func (p *RedactPattern) Init() error {
+	r, err := regexp.Compile(p.Regex)
+	if err != nil {
+		return err
+	}
+	p.regexp = r
+	return nil
+}
+
+// RedactFilter masks sensitive data in the events and always accepts them.
+// The values of fields whose keys match one of the comma separated glob
+// patterns in Keys are replaced by Mask, keys are matched case-insensitively,
+// and the substrings matching RedactPattern(s) are replaced in the message
+// and string field values. It works on the Logger or the AppenderRef, so it
+// applies before any layout runs. On the AppenderRef it only affects the
+// referred appender, the filter runs on a copy of the event there, and the
+// masked fields are always copies, so the fields passed by user and the
+// events seen by other appenders are never modified.
+//
+//	<RedactFilter keys="*password*,token" mask="******">
+//	    <RedactPattern regex="\d{12}(\d{4})" replace="************$1"/>
+//	</RedactFilter>
+type RedactFilter struct {
+	Keys     string           `PluginAttribute:"keys,default="`
+	Mask     string           `PluginAttribute:"mask,default=******"`
+	Patterns []*RedactPattern `PluginElement:"RedactPattern"`
+	keys     []string
+}
+
+func (f *RedactFilter) Init() error {
+	for _, s := range strings.Split(f.Keys, ",") {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" {
+			continue
+		}
+		if _, err := path.Match(s, ""); err != nil {
+			return fmt.Errorf("invalid key pattern '%s'", s)
+		}
+		f.keys = append(f.keys, s)
+	}
+	if len(f.keys) == 0 && len(f.Patterns) == 0 {
+		return errors.New("RedactFilter needs keys attribute or RedactPattern element")
+	}
+	for _, p := range f.Patterns {
+		if p.Replace == "" {
+			p.Replace = f.Mask
+		}
+	}
+	return nil
+}
+
+func (f *RedactFilter) Filter(e *Event) Result {
+	e.Message = f.redactString(e.Message)
+	if fields, ok := f.redactFields(e.Fields); ok {
+		e.Fields = fields
+	}
+	if fields, ok := f.redactFields(MDC(e.Context)); ok {
+		e.Context = WithMDC(e.Context, fields...)
+	}
+	return ResultAccept
+}
+
+func (f *RedactFilter) matchKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range f.keys {
+		if ok, _ := path.Match(s, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *RedactFilter) redactString(s string) string {
+	for _, p := range f.Patterns {
+		s = p.regexp.ReplaceAllString(s, p.Replace)
+	}
+	return s
+}
+
+// redactFields returns the masked copy of fields, the fields passed by user
+// are never modified, the second return value reports whether any field is
+// changed.
+func (f *RedactFilter) redactFields(fields []Field) ([]Field, bool) {
+	var ret []Field
+	for i, field := range fields {
+		val, ok := f.redactValue(field)
+		if !ok {
+			continue
+		}
+		if ret == nil {
+			ret = append([]Field(nil), fields...)
+		}
+		ret[i] = Field{Key: field.Key, Val: val}
+	}
+	return ret, ret != nil
+}
+
+func (f *RedactFilter) redactValue(field Field) (Value, bool) {
+	if f.matchKey(field.Key) {
+		return StringValue(f.Mask), true
+	}
+	switch v := field.Val.(type) {
+	case StringValue:
+		if s := f.redactString(string(v)); s != string(v) {
+			return StringValue(s), true
+		}
+	case ObjectValue:
+		if fields, ok := f.redactFields(v); ok {
+			return ObjectValue(fields), true
+		}
+	}
+	return nil, false
+}
+
+const (
+	SamplingKeyFileLine = "fileline"
+	SamplingKeyMessage  = "message"
+)
+
+// samplingCounter counts the events of a key in the current interval.
+type samplingCounter struct {
+	start      time.Time
+	count      int
+	suppressed int64
+}
+
+// SamplingFilter limits the events of the same key in every interval, the key
+// is composed of the level and the file:line or the message template (the
+// format of Infof etc., or the message itself) of the event. The first First
+// events in an interval are accepted, and then one of every Thereafter events
+// is accepted, others are denied, Thereafter being 0 means all the following
+// events are denied. The first accepted event of the next interval carries a
+// field named "suppressed" reporting the count of events denied in the previous
+// interval. Counters idle for a whole interval are evicted once per interval,
+// the events they denied are reported by the next accepted event of any key in
+// a field named "suppressed_evicted".
+//
+//	<SamplingFilter interval="1s" first="100" thereafter="100" key="fileline"/>
+type SamplingFilter struct {
+	Interval   time.Duration `PluginAttribute:"interval,default=1s"`
+	First      int           `PluginAttribute:"first,default=100"`
+	Thereafter int           `PluginAttribute:"thereafter,default=100"`
+	Key        string        `PluginAttribute:"key,default=fileline"`
+	lock       sync.Mutex
+	counters   map[string]*samplingCounter
+	cleaned    time.Time
+	evicted    int64
+	suppressed atomic.Int64
+}
+
+func (f *SamplingFilter) Init() error {
+	if f.Interval <= 0 {
+		return fmt.Errorf("invalid sampling interval %s", f.Interval)
+	}
+	if f.First < 0 || f.Thereafter < 0 {
+		return errors.New("sampling first and thereafter should not be negative")
+	}
+	if f.Key != SamplingKeyFileLine && f.Key != SamplingKeyMessage {
+		return fmt.Errorf("invalid sampling key '%s'", f.Key)
+	}
+	f.counters = make(map[string]*samplingCounter)
+	return nil
+}
+
+// Suppressed returns the total count of events denied by the filter.
+func (f *SamplingFilter) Suppressed() int64 {
+	return f.suppressed.Load()
+}
+
+func (f *SamplingFilter) key(e *Event) string {
+	if f.Key == SamplingKeyMessage {
+		if e.Format != "" {
+			return e.Level.String() + " " + e.Format
+		}
+		return e.Level.String() + " " + e.Message
+	}
+	return e.Level.String() + " " + e.File + ":" + strconv.Itoa(e.Line)
+}
+
+func (f *SamplingFilter) Filter(e *Event) Result {
+
+	key := f.key(e)
+
+	f.lock.Lock()
+	if e.Time.Sub(f.cleaned) >= f.Interval {
+		f.cleanup(e.Time)
+	}
+	c, ok := f.counters[key]
+	if !ok {
+		c = &samplingCounter{start: e.Time}
+		f.counters[key] = c
+	}
+	var lastSuppressed int64
+	if e.Time.Sub(c.start) >= f.Interval {
+		lastSuppressed = c.suppressed
+		c.start = e.Time
+		c.count = 0
+		c.suppressed = 0
+	}
+	c.count++
+	n := c.count - f.First
+	accept := n <= 0 || (f.Thereafter > 0 && n%f.Thereafter == 1%f.Thereafter)
+	if !accept {
+		c.suppressed++
+		if lastSuppressed > 0 {
+			c.suppressed += lastSuppressed
+		}
+	}
+	var evicted int64
+	if accept {
+		evicted = f.evicted
+		f.evicted = 0
+	}
+	f.lock.Unlock()
+
+	if !accept {
+		f.suppressed.Add(1)
+		return ResultDeny
+	}
+	if lastSuppressed > 0 || evicted > 0 {
+		fields := make([]Field, 0, len(e.Fields)+2)
+		fields = append(fields, e.Fields...)
+		if lastSuppressed > 0 {
+			fields = append(fields, Int64("suppressed", lastSuppressed))
+		}
+		if evicted > 0 {
+			fields = append(fields, Int64("suppressed_evicted", evicted))
+		}
+		e.Fields = fields
+	}
+	return ResultAccept
+}
+
+// cleanup removes the counters which have been idle for a whole interval after
+// their own interval expired, the events they denied are kept to be reported
+// by the next accepted event.
+func (f *SamplingFilter) cleanup(now time.Time) {
+	f.cleaned = now
+	for k, c := range f.counters {
+		if now.Sub(c.start) >= 2*f.Interval {
+			f.evicted += c.suppressed
+			delete(f.counters, k)
+		}
+	}
+}
@@ -19,6 +19,8 @@ package log_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -169,3 +171,183 @@ func TestTimeFilter(t *testing.T) {
 		//assert.Equal(t, f.Filter(nil), c.expect)
 	}
 }
+
+func TestRedactFilter(t *testing.T) {
+
+	f := &log.RedactFilter{Keys: "*password*, Token", Mask: "******"}
+	err := f.Init()
+	assert.Nil(t, err)
+
+	f = &log.RedactFilter{Keys: "[", Mask: "******"}
+	err = f.Init()
+	assert.Error(t, err, "invalid key pattern '\\['")
+
+	f = &log.RedactFilter{Mask: "******"}
+	err = f.Init()
+	assert.Error(t, err, "RedactFilter needs keys attribute or RedactPattern element")
+
+	p := &log.RedactPattern{Regex: `\d{12}(\d{4})`, Replace: "************$1"}
+	err = p.Init()
+	assert.Nil(t, err)
+	q := &log.RedactPattern{Regex: `secret=\w+`}
+	err = q.Init()
+	assert.Nil(t, err)
+
+	f = &log.RedactFilter{
+		Keys:     "*password*,token",
+		Mask:     "******",
+		Patterns: []*log.RedactPattern{p, q},
+	}
+	err = f.Init()
+	assert.Nil(t, err)
+
+	fields := []log.Field{
+		log.String("user", "jim"),
+		log.String("Password", "123456"),
+		log.String("card", "6222020000001234"),
+		log.Object("auth", log.String("token", "abc"), log.Int("expire", 10)),
+	}
+	ctx := log.WithMDC(context.Background(), log.String("token", "xyz"), log.String("traceID", "0001"))
+	e := &log.Event{
+		Context: ctx,
+		Message: "pay with 6222020000001234 secret=abc",
+		Fields:  fields,
+	}
+	assert.Equal(t, f.Filter(e), log.ResultAccept)
+	assert.Equal(t, e.Message, "pay with ************1234 ******")
+	assert.Equal(t, e.Fields, []log.Field{
+		log.String("user", "jim"),
+		log.String("Password", "******"),
+		log.String("card", "************1234"),
+		log.Object("auth", log.String("token", "******"), log.Int("expire", 10)),
+	})
+	assert.Equal(t, log.MDC(e.Context), []log.Field{
+		log.String("token", "******"),
+		log.String("traceID", "0001"),
+	})
+
+	// 用户传入的字段不会被修改。
+	assert.Equal(t, fields[1], log.String("Password", "123456"))
+	assert.Equal(t, log.MDC(ctx)[0], log.String("token", "xyz"))
+}
+
+func TestRedactFilter_Config(t *testing.T) {
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Console name="Console"/>
+			</Appenders>
+			<Loggers>
+				<Root level="info">
+					<AppenderRef ref="Console"/>
+					<RedactFilter keys="*password*">
+						<RedactPattern regex="\d{12}(\d{4})" replace="************$1"/>
+					</RedactFilter>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	assert.Nil(t, err)
+}
+
+func TestRedactFilter_AppenderRef(t *testing.T) {
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Memory name="redacted"/>
+				<Memory name="raw"/>
+			</Appenders>
+			<Loggers>
+				<Root level="info">
+					<AppenderRef ref="redacted">
+						<RedactFilter keys="*password*"/>
+					</AppenderRef>
+					<AppenderRef ref="raw"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	assert.Nil(t, err)
+	appenders := log.GetLogger(log.RootLoggerName).Appenders()
+	log.GetLogger("redact/ref").Infow(log.String("password", "123456"))
+	redacted := appenders[0].(*log.MemoryAppender).Events()
+	raw := appenders[1].(*log.MemoryAppender).Events()
+	assert.Equal(t, len(redacted), 1)
+	assert.Equal(t, len(raw), 1)
+	assert.Equal(t, redacted[0].Fields, []log.Field{log.String("password", "******")})
+	assert.Equal(t, raw[0].Fields, []log.Field{log.String("password", "123456")})
+}
+
+func TestSamplingFilter(t *testing.T) {
+
+	f := &log.SamplingFilter{Interval: time.Second, First: 2, Thereafter: 3, Key: "xxx"}
+	assert.Error(t, f.Init(), "invalid sampling key 'xxx'")
+
+	f = &log.SamplingFilter{Interval: time.Second, First: 2, Thereafter: 3, Key: log.SamplingKeyFileLine}
+	err := f.Init()
+	assert.Nil(t, err)
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	newEvent := func(d time.Duration, line int) *log.Event {
+		return &log.Event{Level: log.ErrorLevel, Time: start.Add(d), File: "a.go", Line: line}
+	}
+
+	var results []log.Result
+	for i := 0; i < 9; i++ {
+		results = append(results, f.Filter(newEvent(time.Duration(i)*time.Millisecond, 10)))
+	}
+	assert.Equal(t, results, []log.Result{
+		log.ResultAccept, log.ResultAccept, // first 2
+		log.ResultAccept, log.ResultDeny, log.ResultDeny, // 1 of every 3
+		log.ResultAccept, log.ResultDeny, log.ResultDeny,
+		log.ResultAccept,
+	})
+	assert.Equal(t, f.Suppressed(), int64(4))
+
+	// 不同的 file:line 单独计数。
+	e := newEvent(10*time.Millisecond, 20)
+	assert.Equal(t, f.Filter(e), log.ResultAccept)
+	assert.Nil(t, e.Fields)
+
+	// 下一个周期的第一条日志报告上一个周期被抑制的数量。
+	e = newEvent(time.Second, 10)
+	assert.Equal(t, f.Filter(e), log.ResultAccept)
+	assert.Equal(t, e.Fields, []log.Field{log.Int64("suppressed", 4)})
+	e = newEvent(time.Second+time.Millisecond, 10)
+	assert.Equal(t, f.Filter(e), log.ResultAccept)
+	assert.Nil(t, e.Fields)
+
+	f = &log.SamplingFilter{Interval: time.Second, First: 1, Key: log.SamplingKeyMessage}
+	err = f.Init()
+	assert.Nil(t, err)
+	for i, expect := range []log.Result{log.ResultAccept, log.ResultDeny, log.ResultDeny} {
+		e = newEvent(time.Duration(i), i)
+		e.Message = "connection refused"
+		assert.Equal(t, f.Filter(e), expect)
+	}
+
+	// 格式化的日志按照模板计数。
+	f = &log.SamplingFilter{Interval: time.Second, First: 1, Key: log.SamplingKeyMessage}
+	err = f.Init()
+	assert.Nil(t, err)
+	for i, expect := range []log.Result{log.ResultAccept, log.ResultDeny, log.ResultDeny} {
+		e = newEvent(time.Duration(i), i)
+		e.Format = "dial %s: connection refused"
+		e.Message = fmt.Sprintf(e.Format, strconv.Itoa(i))
+		assert.Equal(t, f.Filter(e), expect)
+	}
+
+	// 空闲的计数器被清理，被抑制的数量由下一条通过的日志报告。
+	e = newEvent(2*time.Second, 30)
+	e.Message = "timeout"
+	assert.Equal(t, f.Filter(e), log.ResultAccept)
+	assert.Equal(t, e.Fields, []log.Field{log.Int64("suppressed_evicted", 2)})
+	e = newEvent(2*time.Second+time.Millisecond, 40)
+	e.Message = "no route to host"
+	assert.Equal(t, f.Filter(e), log.ResultAccept)
+	assert.Nil(t, e.Fields)
+}
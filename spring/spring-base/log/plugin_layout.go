@@ -18,16 +18,22 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-spring/spring-base/color"
+	"github.com/go-spring/spring-base/log/internal"
 	"github.com/go-spring/spring-base/util"
 )
 
 func init() {
 	RegisterPlugin("PatternLayout", PluginTypeLayout, (*PatternLayout)(nil))
 	RegisterPlugin("JSONLayout", PluginTypeLayout, (*JSONLayout)(nil))
+	RegisterPlugin("LogfmtLayout", PluginTypeLayout, (*LogfmtLayout)(nil))
 }
 
 // Layout lays out an Event in []byte format.
@@ -59,11 +65,23 @@ func ParseColorStyle(s string) (ColorStyle, error) {
 
 type FormatFunc func(e *Event) string
 
+// DefaultTimeFormat is the time format used when no one is configured.
+const DefaultTimeFormat = "2006-01-02T15:04:05.000"
+
+// timeFormat returns format, or DefaultTimeFormat if format is empty.
+func timeFormat(format string) string {
+	if format == "" {
+		return DefaultTimeFormat
+	}
+	return format
+}
+
 // A PatternLayout is a flexible layout configurable with pattern string.
 type PatternLayout struct {
 	ColorStyle ColorStyle `PluginAttribute:"colorStyle,default=none"`
-	Pattern    string     `PluginAttribute:"pattern,default=[:level][:time][:fileline][:msg]"`
+	Pattern    string     `PluginAttribute:"pattern,default=[:level][:time][:fileline] :msg"`
 	steps      []FormatFunc
+	goroutine  bool // whether the pattern contains :goid
 }
 
 func (c *PatternLayout) Init() error {
@@ -80,26 +98,197 @@ func (c *PatternLayout) ToBytes(e *Event) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// parse parses the pattern, the supported tokens are:
+//
+//	:level           the level in upper case
+//	:time            the time in DefaultTimeFormat
+//	:time{layout}    the time in the Go time layout, e.g. :time{15:04:05}
+//	:fileline        the file and line, contracted to 48 characters
+//	:logger          the name of the logger
+//	:tag             the tag
+//	:goid            the ID of the publishing goroutine
+//	:msg             the tag, the fields and the message
+//	%X{key}          the context field named key
+//	%X               all context fields
+//	%%               a literal '%'
+//
+// A token starting with ':' accepts a modifier between ':' and its name, in
+// the form of [-][min][.max], e.g. :-5level and :.20logger. The value is
+// padded with spaces to min characters, on the left by default or on the
+// right when '-' is present, and the values longer than max characters are
+// truncated from the beginning.
 func (c *PatternLayout) parse(pattern string) error {
 	write := func(s string) FormatFunc {
 		return func(e *Event) string {
 			return s
 		}
 	}
-	c.steps = append(c.steps, write("["))
-	c.steps = append(c.steps, c.getLevel)
-	c.steps = append(c.steps, write("]"))
-	c.steps = append(c.steps, write("["))
-	c.steps = append(c.steps, c.getTime)
-	c.steps = append(c.steps, write("]"))
-	c.steps = append(c.steps, write("["))
-	c.steps = append(c.steps, c.getFileLine)
-	c.steps = append(c.steps, write("]"))
-	c.steps = append(c.steps, write(" "))
-	c.steps = append(c.steps, c.getMsg)
+	tokens := map[string]FormatFunc{
+		"level":    c.getLevel,
+		"time":     c.getTime(DefaultTimeFormat),
+		"fileline": c.getFileLine,
+		"logger":   c.getLogger,
+		"tag":      c.getTag,
+		"goid":     c.getGoroutine,
+		"msg":      c.getMsg,
+	}
+	var (
+		steps   []FormatFunc
+		literal strings.Builder
+	)
+	flush := func() {
+		if literal.Len() > 0 {
+			steps = append(steps, write(literal.String()))
+			literal.Reset()
+		}
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == ':':
+			m, j := parseModifier(pattern, i+1)
+			k := j
+			for k < len(pattern) && isLetter(pattern[k]) {
+				k++
+			}
+			if k == j {
+				literal.WriteByte(':')
+				continue
+			}
+			name := pattern[j:k]
+			fn, ok := tokens[name]
+			if !ok {
+				return fmt.Errorf("unknown pattern token '%s'", pattern[i:k])
+			}
+			if name == "time" && k < len(pattern) && pattern[k] == '{' {
+				end := strings.IndexByte(pattern[k+1:], '}')
+				if end < 0 {
+					return fmt.Errorf("unclosed ':time{' in pattern '%s'", pattern)
+				}
+				fn = c.getTime(pattern[k+1 : k+1+end])
+				k += end + 2
+			}
+			if name == "goid" {
+				c.goroutine = true
+			}
+			fn = m.apply(fn)
+			if name == "level" && c.ColorStyle != ColorStyleNone {
+				fn = c.colorLevel(fn)
+			}
+			flush()
+			steps = append(steps, fn)
+			i = k - 1
+		case pattern[i] == '%' && i+1 < len(pattern) && pattern[i+1] == '%':
+			literal.WriteByte('%')
+			i++
+		case pattern[i] == '%' && i+1 < len(pattern) && pattern[i+1] == 'X':
+			flush()
+			if i+2 < len(pattern) && pattern[i+2] == '{' {
+				end := strings.IndexByte(pattern[i+3:], '}')
+				if end < 0 {
+					return fmt.Errorf("unclosed '%%X{' in pattern '%s'", pattern)
+				}
+				steps = append(steps, c.getContextField(pattern[i+3:i+3+end]))
+				i += 3 + end
+				continue
+			}
+			steps = append(steps, c.getContextFields)
+			i++
+		default:
+			literal.WriteByte(pattern[i])
+		}
+	}
+	flush()
+	c.steps = steps
 	return nil
 }
 
+// modifier pads and truncates the output of a token.
+type modifier struct {
+	leftAlign bool
+	min       int
+	max       int
+}
+
+// parseModifier parses the modifier starting at pattern[i], returns the
+// modifier and the index after it.
+func parseModifier(pattern string, i int) (m modifier, j int) {
+	j = i
+	if j < len(pattern) && pattern[j] == '-' {
+		m.leftAlign = true
+		j++
+	}
+	m.min, j = parseDigits(pattern, j)
+	if j < len(pattern) && pattern[j] == '.' {
+		m.max, j = parseDigits(pattern, j+1)
+	}
+	return m, j
+}
+
+func parseDigits(s string, i int) (n int, j int) {
+	for j = i; j < len(s) && s[j] >= '0' && s[j] <= '9'; j++ {
+		n = n*10 + int(s[j]-'0')
+	}
+	return n, j
+}
+
+func (m modifier) apply(fn FormatFunc) FormatFunc {
+	if m.min == 0 && m.max == 0 {
+		return fn
+	}
+	return func(e *Event) string {
+		s := fn(e)
+		n := utf8.RuneCountInString(s)
+		if m.max > 0 && n > m.max {
+			r := []rune(s)
+			s = string(r[n-m.max:])
+			n = m.max
+		}
+		if n >= m.min {
+			return s
+		}
+		padding := strings.Repeat(" ", m.min-n)
+		if m.leftAlign {
+			return s + padding
+		}
+		return padding + s
+	}
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+// getContextField returns a FormatFunc which outputs the value of the context
+// field named key, or an empty string if the field is not found.
+func (c *PatternLayout) getContextField(key string) FormatFunc {
+	return func(e *Event) string {
+		f, ok := ContextField(e.Context, key)
+		if !ok {
+			return ""
+		}
+		buf := bytes.NewBuffer(nil)
+		if err := f.Val.Encode(NewFlatEncoder(buf, "||")); err != nil {
+			return err.Error()
+		}
+		return buf.String()
+	}
+}
+
+// getContextFields outputs all context fields in the flat format.
+func (c *PatternLayout) getContextFields(e *Event) string {
+	buf := bytes.NewBuffer(nil)
+	enc := NewFlatEncoder(buf, "||")
+	for _, f := range ContextFields(e.Context) {
+		if err := enc.AppendKey(f.Key); err != nil {
+			return err.Error()
+		}
+		if err := f.Val.Encode(enc); err != nil {
+			return err.Error()
+		}
+	}
+	return buf.String()
+}
+
 func (c *PatternLayout) getMsg(e *Event) string {
 	buf := bytes.NewBuffer(nil)
 	if tag := e.Tag; tag != "" {
@@ -130,30 +319,85 @@ func (c *PatternLayout) getMsg(e *Event) string {
 }
 
 func (c *PatternLayout) getLevel(e *Event) string {
-	strLevel := strings.ToUpper(e.Level.String())
-	switch c.ColorStyle {
-	case ColorStyleNormal:
-		if e.Level >= ErrorLevel {
-			strLevel = color.Red.Sprint(strLevel)
-		} else if e.Level == WarnLevel {
-			strLevel = color.Yellow.Sprint(strLevel)
-		} else if e.Level <= DebugLevel {
-			strLevel = color.Green.Sprint(strLevel)
+	return strings.ToUpper(e.Level.String())
+}
+
+// colorLevel returns a FormatFunc which colors the output of fn according to
+// the level, it is applied after the modifier so that the padding is not
+// affected by the color codes.
+func (c *PatternLayout) colorLevel(fn FormatFunc) FormatFunc {
+	return func(e *Event) string {
+		strLevel := fn(e)
+		switch c.ColorStyle {
+		case ColorStyleNormal:
+			if e.Level >= ErrorLevel {
+				strLevel = color.Red.Sprint(strLevel)
+			} else if e.Level == WarnLevel {
+				strLevel = color.Yellow.Sprint(strLevel)
+			} else if e.Level <= DebugLevel {
+				strLevel = color.Green.Sprint(strLevel)
+			}
 		}
+		return strLevel
+	}
+}
+
+func (c *PatternLayout) getTime(format string) FormatFunc {
+	return func(e *Event) string {
+		return e.Time.Format(format)
 	}
-	return strLevel
 }
 
-func (c *PatternLayout) getTime(e *Event) string {
-	return e.Time.Format("2006-01-02T15:04:05.000")
+func (c *PatternLayout) getLogger(e *Event) string {
+	return e.Logger
+}
+
+func (c *PatternLayout) getTag(e *Event) string {
+	return e.Tag
+}
+
+// getGoroutine outputs the ID of the publishing goroutine, the current
+// goroutine is used if the event doesn't capture it.
+func (c *PatternLayout) getGoroutine(e *Event) string {
+	id := e.Goroutine
+	if id == 0 {
+		id = internal.GoroutineID()
+	}
+	return strconv.FormatInt(id, 10)
 }
 
 func (c *PatternLayout) getFileLine(e *Event) string {
 	return util.Contract(fmt.Sprintf("%s:%d", e.File, e.Line), 48)
 }
 
-// A JSONLayout is a layout configurable with JSON encoding.
-type JSONLayout struct{}
+// A JSONLayout is a layout configurable with JSON encoding. The keys of the
+// built-in fields can be renamed to fit a logging schema, an empty key uses
+// the default name and "-" omits the field, the logger, tag and message are
+// omitted when they are empty. For example, the ECS schema:
+//
+//	<JSONLayout levelKey="log.level" timeKey="@timestamp" loggerKey="log.logger"
+//	            timeFormat="2006-01-02T15:04:05.000Z07:00" messageKey="message"/>
+type JSONLayout struct {
+	LevelKey    string `PluginAttribute:"levelKey,default=level"`
+	TimeKey     string `PluginAttribute:"timeKey,default=time"`
+	TimeFormat  string `PluginAttribute:"timeFormat,default=2006-01-02T15:04:05.000"`
+	FileLineKey string `PluginAttribute:"fileLineKey,default=fileLine"`
+	LoggerKey   string `PluginAttribute:"loggerKey,default=logger"`
+	TagKey      string `PluginAttribute:"tagKey,default=tag"`
+	MessageKey  string `PluginAttribute:"messageKey,default=msg"`
+}
+
+// appendField appends a built-in field named key, or the default name if key
+// is empty, the field is omitted if key is "-".
+func appendField(fields []Field, key, defaultKey, value string) []Field {
+	if key == "-" {
+		return fields
+	}
+	if key == "" {
+		key = defaultKey
+	}
+	return append(fields, String(key, value))
+}
 
 // ToBytes lays out an Event in []byte format.
 func (c *JSONLayout) ToBytes(e *Event) ([]byte, error) {
@@ -163,11 +407,20 @@ func (c *JSONLayout) ToBytes(e *Event) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	fields := []Field{
-		String("level", strings.ToUpper(e.Level.String())),
-		String("time", e.Time.Format("2006-01-02T15:04:05.000")),
-		String("fileLine", fmt.Sprintf("%s:%d", e.File, e.Line)),
+	var fields []Field
+	fields = appendField(fields, c.LevelKey, "level", strings.ToUpper(e.Level.String()))
+	fields = appendField(fields, c.TimeKey, "time", e.Time.Format(timeFormat(c.TimeFormat)))
+	fields = appendField(fields, c.FileLineKey, "fileLine", fmt.Sprintf("%s:%d", e.File, e.Line))
+	if e.Logger != "" {
+		fields = appendField(fields, c.LoggerKey, "logger", e.Logger)
+	}
+	if e.Tag != "" {
+		fields = appendField(fields, c.TagKey, "tag", e.Tag)
 	}
+	if e.Message != "" {
+		fields = appendField(fields, c.MessageKey, "msg", e.Message)
+	}
+	fields = append(fields, ContextFields(e.Context)...)
 	fields = append(fields, e.Fields...)
 	for _, f := range fields {
 		err = enc.AppendKey(f.Key)
@@ -186,3 +439,90 @@ func (c *JSONLayout) ToBytes(e *Event) ([]byte, error) {
 	buf.WriteByte('\n')
 	return buf.Bytes(), nil
 }
+
+// A LogfmtLayout lays out an Event in the logfmt format, i.e. a line of
+// key=value pairs. The values containing spaces, quotes, '=' or control
+// characters are quoted, the values of objects and arrays are encoded in
+// JSON and quoted.
+//
+//	time=2022-09-30T08:00:00.000 level=info fileLine=main.go:10 msg="hello world" user=abc
+type LogfmtLayout struct {
+	TimeFormat string `PluginAttribute:"timeFormat,default=2006-01-02T15:04:05.000"`
+}
+
+// ToBytes lays out an Event in []byte format.
+func (c *LogfmtLayout) ToBytes(e *Event) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	fields := []Field{
+		String("time", e.Time.Format(timeFormat(c.TimeFormat))),
+		String("level", strings.ToLower(e.Level.String())),
+		String("fileLine", fmt.Sprintf("%s:%d", e.File, e.Line)),
+	}
+	if e.Logger != "" {
+		fields = append(fields, String("logger", e.Logger))
+	}
+	if e.Tag != "" {
+		fields = append(fields, String("tag", e.Tag))
+	}
+	fields = append(fields, String("msg", e.Message))
+	fields = append(fields, ContextFields(e.Context)...)
+	fields = append(fields, e.Fields...)
+	for i, f := range fields {
+		s, err := logfmtValue(f.Val)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(s)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// logfmtValue returns the logfmt representation of v.
+func logfmtValue(v Value) (string, error) {
+	var s string
+	switch x := v.(type) {
+	case StringValue:
+		s = string(x)
+	case BoolValue:
+		return strconv.FormatBool(bool(x)), nil
+	case Int64Value:
+		return strconv.FormatInt(int64(x), 10), nil
+	case Uint64Value:
+		return strconv.FormatUint(uint64(x), 10), nil
+	case Float64Value:
+		return strconv.FormatFloat(float64(x), 'f', -1, 64), nil
+	default:
+		buf := bytes.NewBuffer(nil)
+		if err := v.Encode(NewJSONEncoder(buf)); err != nil {
+			return "", err
+		}
+		s = buf.String()
+		if strings.HasPrefix(s, "\"") {
+			if err := json.Unmarshal(buf.Bytes(), &s); err != nil {
+				return "", err
+			}
+		}
+	}
+	if needsQuote(s) {
+		return strconv.Quote(s), nil
+	}
+	return s, nil
+}
+
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -175,3 +175,165 @@ func TestJSONLayout(t *testing.T) {
 	fmt.Print(string(b))
 	//assert.Equal(t, string(b), "{\"field_a\":\"abc\",\"field_b\":5}\n")
 }
+
+func TestPatternLayout_Context(t *testing.T) {
+
+	ctx := log.WithMDC(context.Background(), log.String("traceID", "abc"), log.Int("userID", 5))
+	e := &log.Event{
+		Context: ctx,
+		File:    "log_test.go",
+		Line:    10,
+		Level:   log.InfoLevel,
+		Time:    time.Date(2022, 9, 30, 8, 0, 0, 0, time.UTC),
+		Message: "hello",
+	}
+
+	testcases := []struct {
+		pattern string
+		expect  string
+	}{
+		{"[:level][:time][:fileline] :msg", "[INFO][2022-09-30T08:00:00.000][log_test.go:10] hello\n"},
+		{"[:level][%X{traceID}][%X{userID}][%X{none}] :msg", "[INFO][abc][5][] hello\n"},
+		{"[:level][%X] 100%% :msg", "[INFO][traceID=abc||userID=5] 100% hello\n"},
+	}
+
+	for _, c := range testcases {
+		layout := log.PatternLayout{Pattern: c.pattern}
+		err := layout.Init()
+		assert.Nil(t, err)
+		b, err := layout.ToBytes(e)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), c.expect)
+	}
+
+	layout := log.PatternLayout{Pattern: "[:lvl]"}
+	assert.Error(t, layout.Init(), "unknown pattern token ':lvl'")
+
+	layout = log.PatternLayout{Pattern: "[%X{traceID]"}
+	assert.Error(t, layout.Init(), "unclosed '%X{' in pattern")
+}
+
+func TestJSONLayout_Context(t *testing.T) {
+	ctx := log.WithMDC(context.Background(), log.String("traceID", "abc"))
+	e := &log.Event{
+		Context: ctx,
+		File:    "log_test.go",
+		Line:    10,
+		Level:   log.InfoLevel,
+		Time:    time.Date(2022, 9, 30, 8, 0, 0, 0, time.UTC),
+		Fields:  []log.Field{log.String("field_a", "abc")},
+	}
+	b, err := new(log.JSONLayout).ToBytes(e)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), `{"level":"INFO","time":"2022-09-30T08:00:00.000","fileLine":"log_test.go:10","traceID":"abc","field_a":"abc"}`+"\n")
+}
+
+func TestPatternLayout_Tokens(t *testing.T) {
+
+	e := &log.Event{
+		File:      "log_test.go",
+		Line:      10,
+		Level:     log.WarnLevel,
+		Time:      time.Date(2022, 9, 30, 8, 1, 2, 0, time.UTC),
+		Tag:       "_def",
+		Logger:    "github.com/go-spring/app",
+		Goroutine: 7,
+		Message:   "hello",
+	}
+
+	testcases := []struct {
+		pattern string
+		expect  string
+	}{
+		{"[:logger][:tag][:goid] :msg", "[github.com/go-spring/app][_def][7] _def||hello\n"},
+		{"[:time{15:04:05}] 08:00 :msg", "[08:01:02] 08:00 _def||hello\n"},
+		{"[:-5level][:5level][:.3logger]", "[WARN ][ WARN][app]\n"},
+		{"[:-6.3logger][:2.5time{2006}]", "[app   ][2022]\n"},
+	}
+
+	for _, c := range testcases {
+		layout := log.PatternLayout{Pattern: c.pattern}
+		err := layout.Init()
+		assert.Nil(t, err)
+		b, err := layout.ToBytes(e)
+		assert.Nil(t, err)
+		assert.Equal(t, string(b), c.expect)
+	}
+
+	layout := log.PatternLayout{ColorStyle: log.ColorStyleNormal, Pattern: ":-6level|"}
+	assert.Nil(t, layout.Init())
+	b, err := layout.ToBytes(e)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), "\x1b[33mWARN  \x1b[0m|\n")
+
+	e.Goroutine = 0
+	layout = log.PatternLayout{Pattern: ":goid"}
+	assert.Nil(t, layout.Init())
+	b, err = layout.ToBytes(e)
+	assert.Nil(t, err)
+	assert.NotEqual(t, string(b), "0\n")
+
+	layout = log.PatternLayout{Pattern: ":-5lvl"}
+	assert.Error(t, layout.Init(), "unknown pattern token ':-5lvl'")
+
+	layout = log.PatternLayout{Pattern: ":time{15:04"}
+	assert.Error(t, layout.Init(), "unclosed ':time{' in pattern")
+}
+
+func TestJSONLayout_Keys(t *testing.T) {
+	e := &log.Event{
+		File:    "log_test.go",
+		Line:    10,
+		Level:   log.InfoLevel,
+		Time:    time.Date(2022, 9, 30, 8, 0, 0, 0, time.UTC),
+		Logger:  "app",
+		Tag:     "_def",
+		Fields:  []log.Field{log.String("field_a", "abc")},
+		Message: "hello",
+	}
+
+	b, err := new(log.JSONLayout).ToBytes(e)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), `{"level":"INFO","time":"2022-09-30T08:00:00.000","fileLine":"log_test.go:10","logger":"app","tag":"_def","msg":"hello","field_a":"abc"}`+"\n")
+
+	layout := &log.JSONLayout{
+		LevelKey:    "log.level",
+		TimeKey:     "@timestamp",
+		TimeFormat:  time.RFC3339,
+		FileLineKey: "-",
+		LoggerKey:   "log.logger",
+		TagKey:      "-",
+		MessageKey:  "message",
+	}
+	b, err = layout.ToBytes(e)
+	assert.Nil(t, err)
+	assert.Equal(t, string(b), `{"log.level":"INFO","@timestamp":"2022-09-30T08:00:00Z","log.logger":"app","message":"hello","field_a":"abc"}`+"\n")
+}
+
+func TestLogfmtLayout(t *testing.T) {
+	ctx := log.WithMDC(context.Background(), log.String("traceID", "abc"))
+	e := &log.Event{
+		Context: ctx,
+		File:    "log_test.go",
+		Line:    10,
+		Level:   log.InfoLevel,
+		Time:    time.Date(2022, 9, 30, 8, 0, 0, 0, time.UTC),
+		Logger:  "app",
+		Fields: []log.Field{
+			log.String("a", "x=y"),
+			log.String("b", ""),
+			log.Int("c", 5),
+			log.Bool("d", true),
+			log.Float64("e", 1.5),
+			log.Ints("f", []int{1, 2}),
+			log.Object("g", log.String("h", "i")),
+			log.Reflect("j", "k l"),
+		},
+		Message: "hello world",
+	}
+	b, err := new(log.LogfmtLayout).ToBytes(e)
+	assert.Nil(t, err)
+	expect := `time=2022-09-30T08:00:00.000 level=info fileLine=log_test.go:10 logger=app msg="hello world" traceID=abc ` +
+		`a="x=y" b="" c=5 d=true e=1.5 f=[1,2] g="{\"h\":\"i\"}" j="k l"` + "\n"
+	assert.Equal(t, string(b), expect)
+}
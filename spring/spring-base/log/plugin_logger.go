@@ -17,6 +17,10 @@
 package log
 
 import (
+	"context"
+
+	"github.com/go-spring/spring-base/atomic"
+	"github.com/go-spring/spring-base/log/internal"
 	"github.com/go-spring/spring-base/log/queue"
 )
 
@@ -35,6 +39,7 @@ type privateConfig interface {
 	getName() string
 	getLevel() Level
 	getAppenders() []*AppenderRef
+	getBase() *baseLoggerConfig
 }
 
 // AppenderRef is a reference to an Appender.
@@ -49,8 +54,14 @@ func (r *AppenderRef) Append(e *Event) {
 	if r.Level != NoneLevel && e.Level < r.Level {
 		return
 	}
-	if r.Filter != nil && ResultDeny == r.Filter.Filter(e) {
-		return
+	if r.Filter != nil {
+		// 过滤器可能修改事件 (例如 RedactFilter)，事件被多个 AppenderRef 共享，
+		// 所以在副本上过滤，修改只对当前的 Appender 生效。
+		c := *e
+		if ResultDeny == r.Filter.Filter(&c) {
+			return
+		}
+		e = &c
 	}
 	r.appender.Append(e)
 }
@@ -58,8 +69,10 @@ func (r *AppenderRef) Append(e *Event) {
 // baseLoggerConfig is the base of loggerConfig and asyncLoggerConfig.
 type baseLoggerConfig struct {
 	root         privateConfig
+	override     atomic.Int32   // the level set at runtime, NoneLevel means not set.
 	Name         string         `PluginAttribute:"name"`
 	AppenderRefs []*AppenderRef `PluginElement:"AppenderRef"`
+	Filter       Filter         `PluginElement:"Filter"`
 	Level        Level          `PluginAttribute:"level,default=info"`
 	Additivity   bool           `PluginAttribute:"additivity,default=true"`
 }
@@ -68,7 +81,11 @@ func (c *baseLoggerConfig) getName() string {
 	return c.Name
 }
 
+// getLevel returns the level set at runtime if exists, or the configured level.
 func (c *baseLoggerConfig) getLevel() Level {
+	if level := Level(c.override.Load()); level != NoneLevel {
+		return level
+	}
 	return c.Level
 }
 
@@ -78,11 +95,18 @@ func (c *baseLoggerConfig) getAppenders() []*AppenderRef {
 
 // filter returns whether the event should be logged.
 func (c *baseLoggerConfig) enableLevel(level Level) bool {
-	return level >= c.Level
+	return level >= c.getLevel()
+}
+
+func (c *baseLoggerConfig) getBase() *baseLoggerConfig {
+	return c
 }
 
 // callAppenders calls all the appenders inherited from the hierarchy circumventing.
 func (c *baseLoggerConfig) callAppenders(e *Event) {
+	if c.Filter != nil && ResultDeny == c.Filter.Filter(e) {
+		return
+	}
 	for _, r := range c.AppenderRefs {
 		r.Append(e)
 	}
@@ -108,9 +132,64 @@ func (c *loggerConfig) publish(e *Event) {
 	c.callAppenders(e)
 }
 
-// asyncLoggerConfig publishes events synchronously.
+// asyncLoggerConfig publishes events asynchronously, every async logger has its
+// own bounded queue, the policy decides what to do when the queue is full.
 type asyncLoggerConfig struct {
 	baseLoggerConfig
+	BufferSize int          `PluginAttribute:"bufferSize,default=10000"`
+	Policy     queue.Policy `PluginAttribute:"policy,default=DropNewest"`
+	queue      *queue.Queue
+	goroutine  bool // whether the goroutine ID should be captured
+}
+
+// Start starts the queue of the async logger.
+func (c *asyncLoggerConfig) Start() error {
+	c.queue = queue.New(c.BufferSize, c.Policy)
+	c.goroutine = layoutGoroutine(c)
+	return nil
+}
+
+// layoutGoroutine returns whether the layout of any appender that the logger
+// or its root sends events to contains :goid, the goroutine ID is costly to
+// get so that it is captured only when it is laid out.
+func layoutGoroutine(c privateConfig) bool {
+	for c != nil {
+		base := c.getBase()
+		for _, r := range base.AppenderRefs {
+			if r.appender == nil {
+				continue
+			}
+			if l, ok := r.appender.GetLayout().(*PatternLayout); ok && l.goroutine {
+				return true
+			}
+		}
+		if !base.Additivity {
+			break
+		}
+		c = base.root
+	}
+	return false
+}
+
+// Stop handles the events in the queue before ctx is done, and then stops
+// the queue, the events published after stopped are handled synchronously.
+func (c *asyncLoggerConfig) Stop(ctx context.Context) {
+	_ = c.stop(ctx)
+}
+
+func (c *asyncLoggerConfig) stop(ctx context.Context) error {
+	if c.queue == nil {
+		return nil
+	}
+	return c.queue.Stop(ctx)
+}
+
+// dropped returns the count of events discarded by the queue.
+func (c *asyncLoggerConfig) dropped() int64 {
+	if c.queue == nil {
+		return 0
+	}
+	return c.queue.Dropped()
 }
 
 type eventWrapper struct {
@@ -125,5 +204,13 @@ func (w *eventWrapper) OnEvent() {
 // publish pushes events into the queue and these events will consumed by other
 // goroutine, so the current goroutine will not be blocked.
 func (c *asyncLoggerConfig) publish(e *Event) {
-	queue.Publish(&eventWrapper{c: c, e: e})
+	if c.goroutine && e.Goroutine == 0 {
+		e.Goroutine = internal.GoroutineID()
+	}
+	w := &eventWrapper{c: c, e: e}
+	if c.queue == nil {
+		w.OnEvent()
+		return
+	}
+	c.queue.Publish(w)
 }
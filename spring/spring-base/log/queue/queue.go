@@ -16,14 +16,21 @@
 
 package queue
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 const (
 	MaxEventCount = 10000
 )
 
 var (
-	inst *queue
+	inst *Queue
 	once sync.Once
 )
 
@@ -31,39 +38,210 @@ type Event interface {
 	OnEvent()
 }
 
-type queue struct {
-	ring chan Event
+// Policy decides what to do when the queue is full.
+type Policy int
+
+const (
+	DropNewest = Policy(iota) // discards the event being published
+	DropOldest                // discards the oldest event in the queue
+	Block                     // waits until the queue has free space
+	Sync                      // handles the event in the caller goroutine
+)
+
+// ParsePolicy parses `s` to a Policy value.
+func ParsePolicy(s string) (Policy, error) {
+	switch strings.ToLower(s) {
+	case "dropnewest":
+		return DropNewest, nil
+	case "dropoldest":
+		return DropOldest, nil
+	case "block":
+		return Block, nil
+	case "sync":
+		return Sync, nil
+	default:
+		return -1, fmt.Errorf("invalid queue policy '%s'", s)
+	}
+}
+
+func (p Policy) String() string {
+	switch p {
+	case DropNewest:
+		return "DropNewest"
+	case DropOldest:
+		return "DropOldest"
+	case Block:
+		return "Block"
+	case Sync:
+		return "Sync"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// Queue is a bounded queue whose events are consumed by one goroutine.
+type Queue struct {
+	ring    chan Event
+	policy  Policy
+	pending int64 // events published but not handled
+	dropped int64
+	stopped int32
+	writers int32 // publishers which passed the stopped check
+	done    chan struct{}
+	exited  chan struct{}
 }
 
-func get() *queue {
+// New returns a started *Queue which holds `size` events at most.
+func New(size int, policy Policy) *Queue {
+	if size <= 0 {
+		size = MaxEventCount
+	}
+	q := &Queue{
+		ring:   make(chan Event, size),
+		policy: policy,
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	go q.consume()
+	return q
+}
+
+func get() *Queue {
 	once.Do(func() {
-		inst = &queue{
-			ring: make(chan Event, MaxEventCount),
-		}
-		inst.consume()
+		inst = New(MaxEventCount, DropNewest)
 	})
 	return inst
 }
 
+// Publish publishes the event to the default queue.
 func Publish(e Event) bool {
-	return get().publish(e)
+	return get().Publish(e)
 }
 
-func (q *queue) publish(e Event) bool {
+// Publish publishes the event according to the policy, returns false when the
+// event is discarded. After the queue stopped, events are handled in the
+// caller goroutine.
+func (q *Queue) Publish(e Event) bool {
+	atomic.AddInt32(&q.writers, 1)
+	if atomic.LoadInt32(&q.stopped) == 1 {
+		atomic.AddInt32(&q.writers, -1)
+		e.OnEvent()
+		return true
+	}
+	defer atomic.AddInt32(&q.writers, -1)
+	atomic.AddInt64(&q.pending, 1)
 	select {
 	case q.ring <- e:
 		return true
 	default:
+	}
+	switch q.policy {
+	case DropOldest:
+		for {
+			select {
+			case q.ring <- e:
+				return true
+			default:
+			}
+			select {
+			case <-q.ring:
+				atomic.AddInt64(&q.pending, -1)
+				atomic.AddInt64(&q.dropped, 1)
+			default:
+			}
+		}
+	case Block:
+		select {
+		case q.ring <- e:
+			return true
+		case <-q.done:
+			atomic.AddInt64(&q.pending, -1)
+			e.OnEvent()
+			return true
+		}
+	case Sync:
+		atomic.AddInt64(&q.pending, -1)
+		e.OnEvent()
+		return true
+	default:
+		atomic.AddInt64(&q.pending, -1)
+		atomic.AddInt64(&q.dropped, 1)
 		return false
 	}
 }
 
-func (q *queue) consume() {
-	go func() {
-		for {
-			if e := <-q.ring; e != nil {
-				e.OnEvent()
-			}
+// Dropped returns the count of discarded events.
+func (q *Queue) Dropped() int64 {
+	return atomic.LoadInt64(&q.dropped)
+}
+
+// Len returns the count of events waiting to be handled.
+func (q *Queue) Len() int {
+	return int(atomic.LoadInt64(&q.pending))
+}
+
+func (q *Queue) consume() {
+	defer close(q.exited)
+	for {
+		select {
+		case <-q.done:
+			return
+		default:
+		}
+		select {
+		case e := <-q.ring:
+			q.handle(e)
+		case <-q.done:
+			return
+		}
+	}
+}
+
+func (q *Queue) handle(e Event) {
+	defer atomic.AddInt64(&q.pending, -1)
+	if e != nil {
+		e.OnEvent()
+	}
+}
+
+// Flush waits until all events published before are handled, or returns the
+// error of ctx when ctx is done.
+func (q *Queue) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	for atomic.LoadInt64(&q.pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-q.exited:
+			return nil
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// Stop flushes the queue and then stops the consumer goroutine. The events
+// which are not handled before ctx is done will be discarded.
+func (q *Queue) Stop(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&q.stopped, 0, 1) {
+		return nil
+	}
+	err := q.Flush(ctx)
+	close(q.done)
+	<-q.exited
+	// the publishers which passed the stopped check before it was set may
+	// still be adding events, wait for them so that no event is left behind.
+	for atomic.LoadInt32(&q.writers) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for {
+		select {
+		case <-q.ring:
+			atomic.AddInt64(&q.pending, -1)
+			atomic.AddInt64(&q.dropped, 1)
+		default:
+			return err
 		}
-	}()
+	}
 }
@@ -0,0 +1,184 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package queue_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log/queue"
+)
+
+type event struct {
+	mutex  *sync.Mutex
+	wait   chan struct{}
+	values *[]int
+	value  int
+}
+
+func (e *event) OnEvent() {
+	if e.wait != nil {
+		<-e.wait
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	*e.values = append(*e.values, e.value)
+}
+
+type recorder struct {
+	mutex  sync.Mutex
+	values []int
+}
+
+func (r *recorder) event(value int, wait chan struct{}) *event {
+	return &event{mutex: &r.mutex, wait: wait, values: &r.values, value: value}
+}
+
+func (r *recorder) get() []int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]int(nil), r.values...)
+}
+
+func TestParsePolicy(t *testing.T) {
+	for _, p := range []queue.Policy{queue.DropNewest, queue.DropOldest, queue.Block, queue.Sync} {
+		v, err := queue.ParsePolicy(p.String())
+		assert.Nil(t, err)
+		assert.Equal(t, v, p)
+	}
+	_, err := queue.ParsePolicy("abc")
+	assert.Error(t, err, "invalid queue policy 'abc'")
+}
+
+func TestQueue(t *testing.T) {
+
+	testcases := []struct {
+		policy  queue.Policy
+		values  []int
+		dropped int64
+	}{
+		{queue.DropNewest, []int{0, 1, 2}, 2},
+		{queue.DropOldest, []int{0, 3, 4}, 2},
+		{queue.Sync, []int{3, 4, 0, 1, 2}, 0},
+	}
+
+	for _, c := range testcases {
+		r := new(recorder)
+		wait := make(chan struct{})
+		q := queue.New(2, c.policy)
+		q.Publish(r.event(0, wait))
+		time.Sleep(10 * time.Millisecond) // 等待消费者阻塞在第一个事件上
+		for i := 1; i < 5; i++ {
+			q.Publish(r.event(i, nil))
+		}
+		close(wait)
+		err := q.Flush(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, r.get(), c.values)
+		assert.Equal(t, q.Dropped(), c.dropped)
+		assert.Nil(t, q.Stop(context.Background()))
+	}
+}
+
+func TestQueue_Block(t *testing.T) {
+	r := new(recorder)
+	wait := make(chan struct{})
+	q := queue.New(1, queue.Block)
+	q.Publish(r.event(0, wait))
+	time.Sleep(10 * time.Millisecond)
+	q.Publish(r.event(1, nil))
+	published := make(chan struct{})
+	go func() {
+		q.Publish(r.event(2, nil))
+		close(published)
+	}()
+	select {
+	case <-published:
+		t.Fatal("publish should be blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(wait)
+	<-published
+	assert.Nil(t, q.Stop(context.Background()))
+	assert.Equal(t, r.get(), []int{0, 1, 2})
+	assert.Equal(t, q.Dropped(), int64(0))
+}
+
+type counter struct {
+	count *int64
+}
+
+func (c counter) OnEvent() {
+	atomic.AddInt64(c.count, 1)
+}
+
+func TestQueue_StopConcurrent(t *testing.T) {
+	for _, policy := range []queue.Policy{queue.DropNewest, queue.DropOldest, queue.Block, queue.Sync} {
+		var handled int64
+		q := queue.New(8, policy)
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					q.Publish(counter{&handled})
+				}
+			}()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		assert.Nil(t, ignoreDeadline(q.Stop(ctx)))
+		cancel()
+		wg.Wait()
+		// 每个事件要么被处理，要么被计为丢弃
+		assert.Equal(t, atomic.LoadInt64(&handled)+q.Dropped(), int64(4000))
+		assert.Equal(t, q.Len(), 0)
+	}
+}
+
+func ignoreDeadline(err error) error {
+	if err == context.DeadlineExceeded {
+		return nil
+	}
+	return err
+}
+
+func TestQueue_Stop(t *testing.T) {
+	r := new(recorder)
+	wait := make(chan struct{})
+	q := queue.New(10, queue.DropNewest)
+	q.Publish(r.event(0, wait))
+	q.Publish(r.event(1, nil))
+	q.Publish(r.event(2, nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	time.AfterFunc(30*time.Millisecond, func() { close(wait) })
+	err := q.Stop(ctx)
+	assert.Equal(t, err, context.DeadlineExceeded)
+	assert.Equal(t, q.Len(), 0)
+	assert.Equal(t, q.Dropped(), int64(2))
+	assert.Equal(t, r.get(), []int{0})
+
+	// 停止之后同步处理事件
+	q.Publish(r.event(3, nil))
+	assert.Equal(t, r.get(), []int{0, 3})
+}
@@ -100,7 +100,7 @@ func TestDynamic(t *testing.T) {
 		err := p.Refresh(conf.Map(map[string]interface{}{
 			"int": 9,
 		}))
-		assert.Error(t, err, "validate failed on \"\\$<6\" for value 9")
+		assert.Error(t, err, "validate failed on \"\\$<6\"")
 		err = p.Refresh(conf.Map(map[string]interface{}{
 			"int": "abc.123",
 		}))
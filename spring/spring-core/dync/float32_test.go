@@ -46,7 +46,7 @@ func TestFloat32(t *testing.T) {
 
 	param.Validate = `expr:"$>5"`
 	err = u.OnRefresh(p, param)
-	assert.Error(t, err, "validate failed on \"\\$\\>5\" for value 3")
+	assert.Error(t, err, "validate failed on \"\\$\\>5\"")
 
 	param.Validate = ""
 	err = u.OnRefresh(p, param)
@@ -9,6 +9,8 @@ require (
 	github.com/google/uuid v1.3.0
 	github.com/magiconair/properties v1.8.5
 	github.com/pelletier/go-toml v1.9.4
+	github.com/vmihailenco/msgpack/v5 v5.3.5
+	google.golang.org/protobuf v1.28.1
 	gopkg.in/yaml.v2 v2.4.0
 )
 
@@ -28,6 +28,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-base/util"
@@ -41,6 +42,12 @@ import (
 // SpringBannerVisible 是否显示 banner。
 const SpringBannerVisible = "spring.banner.visible"
 
+// DefaultLoggingKey 日志配置在应用配置中默认的 key 。
+const DefaultLoggingKey = "logging"
+
+// LogStopTimeout 应用退出时等待异步日志输出完成的最长时间。
+var LogStopTimeout = 3 * time.Second
+
 // AppRunner 命令行启动器接口
 type AppRunner interface {
 	Run(ctx Context)
@@ -57,6 +64,7 @@ type tempApp struct {
 	consumers   *Consumers
 	grpcServers *GrpcServers
 	banner      string
+	logging     string
 }
 
 // App 应用
@@ -112,6 +120,7 @@ func NewApp() *App {
 			grpcServers: &GrpcServers{
 				servers: map[string]*grpc.Server{},
 			},
+			logging: DefaultLoggingKey,
 		},
 		exitChan: make(chan struct{}),
 	}
@@ -122,6 +131,12 @@ func (app *App) Banner(banner string) {
 	app.banner = banner
 }
 
+// Logging 设置日志配置在应用配置中的 key ，默认为 logging ，设置为空字符串时
+// 不从应用配置中加载日志配置。
+func (app *App) Logging(key string) {
+	app.logging = key
+}
+
 func (app *App) Run() error {
 
 	config := `
@@ -167,7 +182,11 @@ func (app *App) Run() error {
 
 	app.c.Close()
 	app.logger.Info("application exited")
-	return nil
+
+	// 处理完异步日志队列中的事件再退出。
+	ctx, cancel := context.WithTimeout(context.Background(), LogStopTimeout)
+	defer cancel()
+	return log.Stop(ctx)
 }
 
 func (app *App) clear() {
@@ -209,6 +228,14 @@ func (app *App) start() error {
 		app.c.initProperties.Set(k, e.p.Get(k))
 	}
 
+	if err := app.refreshLogging(); err != nil {
+		return err
+	}
+
+	if err := app.c.p.Refresh(app.c.initProperties); err != nil {
+		return err
+	}
+
 	if err := app.c.refresh(false); err != nil {
 		return err
 	}
@@ -331,6 +358,29 @@ func (app *App) loadProperties(e *configuration) error {
 	return nil
 }
 
+// refreshLogging 使用应用配置中日志配置所在的 key 下面的属性刷新日志配置，
+// 属性的格式参见 log.NewNodeFromProperties 函数。
+func (app *App) refreshLogging() error {
+	if app.logging == "" {
+		return nil
+	}
+	prefix := app.logging + "."
+	m := make(map[string]string)
+	for _, key := range app.c.initProperties.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			m[strings.TrimPrefix(key, prefix)] = app.c.initProperties.Get(key)
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	node, err := log.NewNodeFromProperties(m)
+	if err != nil {
+		return err
+	}
+	return log.RefreshNode(node)
+}
+
 func (app *App) loadResource(e *configuration, filename string) ([]Resource, error) {
 
 	var locators []ResourceLocator
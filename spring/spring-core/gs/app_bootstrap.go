@@ -80,6 +80,10 @@ func (b *bootstrap) start(e *configuration) error {
 		b.c.initProperties.Set(k, e.p.Get(k))
 	}
 
+	if err := b.c.p.Refresh(b.c.initProperties); err != nil {
+		return err
+	}
+
 	return b.c.Refresh()
 }
 
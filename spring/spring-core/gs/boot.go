@@ -65,6 +65,11 @@ func Banner(banner string) {
 	app.Banner(banner)
 }
 
+// Logging 参考 App.Logging 的解释。
+func Logging(key string) {
+	app.Logging(key)
+}
+
 // Bootstrap 参考 App.Bootstrap 的解释。
 func Bootstrap() *bootstrap {
 	return app.Bootstrap()
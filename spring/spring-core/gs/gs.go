@@ -89,6 +89,7 @@ type ContextAware struct {
 }
 
 type tempContainer struct {
+	initProperties  *conf.Properties
 	beans           []*BeanDefinition
 	beansByName     map[string][]*BeanDefinition
 	beansByType     map[reflect.Type][]*BeanDefinition
@@ -123,6 +124,7 @@ func New() Container {
 		cancel: cancel,
 		p:      dync.New(),
 		tempContainer: &tempContainer{
+			initProperties:  conf.New(),
 			beansByName:     make(map[string][]*BeanDefinition),
 			beansByType:     make(map[reflect.Type][]*BeanDefinition),
 			mapOfOnProperty: make(map[string]interface{}),
@@ -150,6 +152,16 @@ func validOnProperty(fn interface{}) error {
 	return nil
 }
 
+// Property 设置 key 对应的属性值，如果 key 对应的属性值已经存在则 Set 方法会
+// 覆盖旧值。Set 方法除了支持 string 类型的属性值，还支持 int、uint、bool 等
+// 其他基础数据类型的属性值。特殊情况下，Set 方法也支持 slice 、map 与基础数据
+// 类型组合构成的属性值，其处理方式是将组合结构层层展开，可以将组合结构看成一棵树，
+// 那么叶子结点的路径就是属性的 key ，叶子结点的值就是属性的值。
+func (c *container) Property(key string, value interface{}) {
+	err := c.initProperties.Set(key, value)
+	util.Panic(err).When(err != nil)
+}
+
 // OnProperty 当 key 对应的属性值准备好后发送一个通知。
 func (c *container) OnProperty(key string, fn interface{}) {
 	err := validOnProperty(fn)
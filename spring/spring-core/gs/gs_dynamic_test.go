@@ -105,7 +105,7 @@ func TestDynamic(t *testing.T) {
 		p.Set("wrapper.slice[0]", 2)
 		p.Set("wrapper.slice[1]", 1)
 		err = c.Properties().Refresh(p)
-		assert.Error(t, err, "validate failed on \"\\$<6\" for value 6")
+		assert.Error(t, err, "validate failed on \"\\$<6\"")
 	}
 
 	{
@@ -130,7 +130,7 @@ func TestDynamic(t *testing.T) {
 		p.Set("wrapper.slice[0]", 2)
 		p.Set("wrapper.slice[1]", 1)
 		err = c.Properties().Refresh(p)
-		assert.Error(t, err, "validate failed on \\\"\\$<6\\\" for value 9")
+		assert.Error(t, err, "validate failed on \\\"\\$<6\\\"")
 	}
 
 	{
@@ -0,0 +1,206 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mq
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBrokerClosed 向已关闭的 MemoryBroker 发送消息时返回的错误。
+var ErrBrokerClosed = errors.New("mq: broker is closed")
+
+// MemoryOption MemoryBroker 的配置项。
+type MemoryOption func(*MemoryBroker)
+
+// Async 设置异步投递消息，concurrency 为同时投递消息的协程数量，为 1 时按照发送顺序投递，
+// queue 已满时除外，参见 SendMessage 。
+func Async(concurrency int) MemoryOption {
+	return func(b *MemoryBroker) {
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		b.concurrency = concurrency
+	}
+}
+
+// MemoryBroker 进程内的消息队列，按照主题将消息投递给订阅的消费者，适用于单元测试和本地开发。
+// 默认同步投递消息，SendMessage 在所有消费者消费完成后返回第一个消费错误，异步投递时消费错误
+// 通过 Errors 获取。
+type MemoryBroker struct {
+	concurrency int
+
+	mutex     sync.Mutex
+	consumers map[string][]Consumer
+	messages  []Message
+	errs      []error
+
+	// closeLock 保证设置 closed 之后没有正在发送的消息，从而可以安全地关闭 queue 。
+	closeLock sync.RWMutex
+	closed    bool
+
+	queue   chan Message
+	pending sync.WaitGroup // 尚未消费完成的消息
+	workers sync.WaitGroup
+}
+
+// NewMemoryBroker 创建进程内的消息队列。
+func NewMemoryBroker(opts ...MemoryOption) *MemoryBroker {
+	b := &MemoryBroker{consumers: make(map[string][]Consumer)}
+	for _, opt := range opts {
+		opt(b)
+	}
+	if b.concurrency > 0 {
+		b.queue = make(chan Message, 1024)
+		for i := 0; i < b.concurrency; i++ {
+			b.workers.Add(1)
+			go b.work()
+		}
+	}
+	return b
+}
+
+// Subscribe 在消费者的所有主题上订阅消息。
+func (b *MemoryBroker) Subscribe(c Consumer) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, topic := range c.Topics() {
+		b.consumers[topic] = append(b.consumers[topic], c)
+	}
+}
+
+// SendMessage 记录消息并且投递给订阅该主题的消费者。异步投递时如果 queue 已满，
+// 消息在调用方协程中投递，避免消费者发送消息时所有协程都阻塞在 queue 上，此时
+// 消息的投递顺序和发送顺序可能不同。
+func (b *MemoryBroker) SendMessage(ctx context.Context, msg Message) error {
+	queued, err := b.enqueue(msg)
+	if err != nil || queued {
+		return err
+	}
+	err = b.dispatch(ctx, msg)
+	if b.queue != nil {
+		b.addError(err)
+		return nil
+	}
+	return err
+}
+
+// enqueue 记录消息并且放入 queue ，返回 false 表示消息需要在调用方协程中投递。
+// 持有 closeLock 时不会阻塞，因此不会阻塞 Close 。
+func (b *MemoryBroker) enqueue(msg Message) (bool, error) {
+	b.closeLock.RLock()
+	defer b.closeLock.RUnlock()
+	if b.closed {
+		return false, ErrBrokerClosed
+	}
+	b.mutex.Lock()
+	b.messages = append(b.messages, msg)
+	b.mutex.Unlock()
+	if b.queue == nil {
+		return false, nil
+	}
+	b.pending.Add(1)
+	select {
+	case b.queue <- msg:
+		return true, nil
+	default:
+		b.pending.Done()
+		return false, nil
+	}
+}
+
+func (b *MemoryBroker) work() {
+	defer b.workers.Done()
+	for msg := range b.queue {
+		b.addError(b.dispatch(context.Background(), msg))
+		b.pending.Done()
+	}
+}
+
+// addError 记录异步投递时的消费错误。
+func (b *MemoryBroker) addError(err error) {
+	if err == nil {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.errs = append(b.errs, err)
+}
+
+// dispatch 将消息投递给订阅该主题的所有消费者，返回第一个消费错误。
+func (b *MemoryBroker) dispatch(ctx context.Context, msg Message) error {
+	b.mutex.Lock()
+	consumers := b.consumers[msg.Topic()]
+	b.mutex.Unlock()
+	var ret error
+	for _, c := range consumers {
+		if err := c.Consume(ctx, msg); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	return ret
+}
+
+// Wait 等待已经发送的消息全部消费完成，同步投递时立即返回。
+func (b *MemoryBroker) Wait() {
+	b.pending.Wait()
+}
+
+// Messages 返回发送到 topic 的消息，topic 为空时返回所有消息，按照发送顺序排列。
+func (b *MemoryBroker) Messages(topic string) []Message {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var ret []Message
+	for _, msg := range b.messages {
+		if topic == "" || msg.Topic() == topic {
+			ret = append(ret, msg)
+		}
+	}
+	return ret
+}
+
+// Errors 返回异步投递时的消费错误。
+func (b *MemoryBroker) Errors() []error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return append([]error(nil), b.errs...)
+}
+
+// Reset 清空记录的消息和消费错误，订阅关系保持不变。
+func (b *MemoryBroker) Reset() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.messages = nil
+	b.errs = nil
+}
+
+// Close 关闭消息队列并等待已经发送的消息消费完成，之后发送消息返回 ErrBrokerClosed ，
+// 包括消费者在关闭过程中发送的消息。等待时不持有 closeLock ，因此消费者发送消息不会阻塞关闭。
+func (b *MemoryBroker) Close() {
+	b.closeLock.Lock()
+	closed := b.closed
+	b.closed = true
+	b.closeLock.Unlock()
+	if closed {
+		return
+	}
+	if b.queue != nil {
+		close(b.queue)
+		b.workers.Wait()
+	}
+}
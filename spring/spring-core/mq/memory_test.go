@@ -0,0 +1,179 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mq_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/mq"
+)
+
+type event struct {
+	Name string `json:"name"`
+}
+
+func TestMemoryBroker(t *testing.T) {
+
+	var names []string
+	b := mq.NewMemoryBroker()
+	b.Subscribe(mq.Bind(func(ctx context.Context, e *event) error {
+		if e.Name == "" {
+			return errors.New("name is empty")
+		}
+		names = append(names, e.Name)
+		return nil
+	}, "user", "admin"))
+
+	ctx := context.Background()
+	err := b.SendMessage(ctx, mq.NewMessage().WithTopic("user").WithBody([]byte(`{"name":"jim"}`)))
+	assert.Nil(t, err)
+	err = b.SendMessage(ctx, mq.NewMessage().WithTopic("admin").WithBody([]byte(`{"name":"tom"}`)))
+	assert.Nil(t, err)
+	err = b.SendMessage(ctx, mq.NewMessage().WithTopic("user").WithBody([]byte(`{}`)))
+	assert.Error(t, err, "name is empty")
+	err = b.SendMessage(ctx, mq.NewMessage().WithTopic("order").WithBody([]byte(`{}`)))
+	assert.Nil(t, err)
+
+	assert.Equal(t, names, []string{"jim", "tom"})
+	assert.Equal(t, len(b.Messages("")), 4)
+	var bodies []string
+	for _, msg := range b.Messages("user") {
+		bodies = append(bodies, string(msg.Body()))
+	}
+	assert.Equal(t, bodies, []string{`{"name":"jim"}`, `{}`})
+
+	b.Reset()
+	assert.Nil(t, b.Messages(""))
+
+	b.Close()
+	err = b.SendMessage(ctx, mq.NewMessage().WithTopic("user"))
+	assert.Equal(t, err, mq.ErrBrokerClosed)
+}
+
+func TestMemoryBroker_Async(t *testing.T) {
+
+	var (
+		mutex sync.Mutex
+		names []string
+	)
+	b := mq.NewMemoryBroker(mq.Async(1))
+	b.Subscribe(mq.Bind(func(ctx context.Context, e *event) error {
+		if e.Name == "" {
+			return errors.New("name is empty")
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		names = append(names, e.Name)
+		return nil
+	}, "user"))
+
+	ctx := context.Background()
+	for _, s := range []string{`{"name":"a"}`, `{}`, `{"name":"b"}`, `{"name":"c"}`} {
+		err := b.SendMessage(ctx, mq.NewMessage().WithTopic("user").WithBody([]byte(s)))
+		assert.Nil(t, err)
+	}
+
+	b.Wait()
+	assert.Equal(t, names, []string{"a", "b", "c"})
+	assert.Equal(t, b.Errors(), []error{errors.New("name is empty")})
+
+	b.Close()
+	b.Close()
+}
+
+func TestMemoryBroker_CloseRepublish(t *testing.T) {
+
+	var errs []error
+	b := mq.NewMemoryBroker(mq.Async(1))
+	started := make(chan struct{})
+	b.Subscribe(mq.Bind(func(ctx context.Context, e *event) error {
+		close(started)
+		// 消费者在关闭过程中重新发送消息，直到消息队列关闭。
+		for {
+			msg := mq.NewMessage().WithTopic("audit").WithBody([]byte(`{}`))
+			if err := b.SendMessage(ctx, msg); err != nil {
+				errs = append(errs, err)
+				return nil
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}, "user"))
+
+	ctx := context.Background()
+	err := b.SendMessage(ctx, mq.NewMessage().WithTopic("user").WithBody([]byte(`{"name":"a"}`)))
+	assert.Nil(t, err)
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close timeout")
+	}
+	assert.Equal(t, errs, []error{mq.ErrBrokerClosed})
+}
+
+func TestMemoryBroker_QueueFull(t *testing.T) {
+
+	var count int64
+	b := mq.NewMemoryBroker(mq.Async(1))
+	b.Subscribe(mq.Bind(func(ctx context.Context, e *event) error {
+		// 消费者发送的消息超过 queue 的容量，超出的消息在消费者协程中投递。
+		for i := 0; i < 2000; i++ {
+			msg := mq.NewMessage().WithTopic("audit").WithBody([]byte(`{}`))
+			if err := b.SendMessage(ctx, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, "user"))
+	b.Subscribe(mq.Bind(func(ctx context.Context, e *event) error {
+		atomic.AddInt64(&count, 1)
+		return nil
+	}, "audit"))
+
+	ctx := context.Background()
+	err := b.SendMessage(ctx, mq.NewMessage().WithTopic("user").WithBody([]byte(`{"name":"a"}`)))
+	assert.Nil(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.Wait()
+		b.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("wait timeout")
+	}
+	assert.Equal(t, atomic.LoadInt64(&count), int64(2000))
+	assert.Equal(t, len(b.Messages("audit")), 2000)
+	assert.Nil(t, b.Errors())
+}
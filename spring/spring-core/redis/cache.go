@@ -0,0 +1,329 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-base/cache"
+	"github.com/go-spring/spring-base/log"
+)
+
+// DefaultCacheTTL is the default ttl of the values stored in Redis when the
+// ExpireAfterWrite of the loading is 0.
+const DefaultCacheTTL = time.Hour
+
+const (
+	// cacheLeasePrefix is the prefix of the leases set on the missing keys.
+	cacheLeasePrefix = "lease:"
+
+	// cacheLeaseTTL is the ttl of the leases, a value loaded after its lease
+	// expires isn't stored.
+	cacheLeaseTTL = 10 * time.Second
+
+	// cacheResubscribeMin and cacheResubscribeMax bound the backoff between
+	// the retries of subscribing the invalidation channel.
+	cacheResubscribeMin = 100 * time.Millisecond
+	cacheResubscribeMax = 10 * time.Second
+
+	// storeScript sets the value only when the key is still the lease.
+	storeScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3]) return 1 else return 0 end`
+)
+
+type CacheOption func(*CacheDriver)
+
+// CachePrefix sets the prefix of the keys stored in Redis, default is "cache:".
+func CachePrefix(prefix string) CacheOption {
+	return func(d *CacheDriver) {
+		d.prefix = prefix
+	}
+}
+
+// CacheChannel sets the pub/sub channel of the invalidations, default is
+// "cache:invalidate".
+func CacheChannel(channel string) CacheOption {
+	return func(d *CacheDriver) {
+		d.channel = channel
+	}
+}
+
+// CacheTTL sets the ttl of the values stored in Redis when the ExpireAfterWrite
+// of the loading is 0, default is DefaultCacheTTL. The values in Redis always
+// expire, so a value missing an invalidation doesn't live forever.
+func CacheTTL(ttl time.Duration) CacheOption {
+	return func(d *CacheDriver) {
+		d.ttl = ttl
+	}
+}
+
+// CacheLocal sets the local driver and the storage it loads values from, the
+// default is cache.Engine and cache.Cache.
+func CacheLocal(local cache.Driver, storage cache.Shardable) CacheOption {
+	return func(d *CacheDriver) {
+		d.local = local
+		d.storage = storage
+	}
+}
+
+// CacheRunner sets the GoRunner starting the invalidation listener.
+func CacheRunner(r GoRunner) CacheOption {
+	return func(d *CacheDriver) {
+		d.runner = r
+	}
+}
+
+// CacheDriver is a two-level cache.Driver, it loads values from the local
+// driver first, then from Redis, and then from the loader. The values are
+// stored in Redis as the JSON of cache.Result, and the invalidations are
+// broadcast over Redis pub/sub so other instances evict their local copies.
+// The invalidation channel is subscribed again when the subscription is
+// broken, the invalidations published in the meantime are lost, and the local
+// copies they concern live until their own ttl.
+type CacheDriver struct {
+	client  *Client
+	prefix  string
+	channel string
+	ttl     time.Duration
+	local   cache.Driver
+	storage cache.Shardable
+	runner  GoRunner
+	id      string
+	mutex   sync.Mutex
+	sub     Subscription
+	done    chan struct{}
+	closed  sync.Once
+}
+
+// NewCacheDriver returns a new *CacheDriver, and subscribes the invalidation
+// channel, so the driver of client must implement Subscriber.
+func NewCacheDriver(ctx context.Context, client *Client, opts ...CacheOption) (*CacheDriver, error) {
+	d := &CacheDriver{
+		client:  client,
+		prefix:  "cache:",
+		channel: "cache:invalidate",
+		ttl:     DefaultCacheTTL,
+		local:   cache.Engine,
+		storage: cache.Cache,
+		runner:  defaultRunner{},
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	id, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	d.id = id
+	sub, err := client.Subscribe(ctx, d.channel)
+	if err != nil {
+		return nil, err
+	}
+	d.sub = sub
+	d.runner.Go(d.listen)
+	return d, nil
+}
+
+// Load loads value from the local driver, if there is no cached value, loads
+// it from Redis, and then from the loader. Before calling the loader a lease
+// is set on the missing key, the value loaded is stored into Redis with the
+// ttl of ExpireAfterWrite (or the ttl of the driver if it's 0) only when the
+// lease is still there, so a value loaded before Invalidate isn't stored.
+func (d *CacheDriver) Load(ctx context.Context, m *cache.Shard, key string, loader cache.ResultLoader, arg cache.OptionArg) (cache.LoadType, cache.Result, error) {
+	remote := false
+	l := func(ctx context.Context, key string) (cache.Result, error) {
+		s, err := d.client.Get(ctx, d.prefix+key)
+		if err == nil && !strings.HasPrefix(s, cacheLeasePrefix) {
+			remote = true
+			return cache.NewJSONResult(s), nil
+		}
+		var lease string
+		switch {
+		case err == nil:
+			// the key is leased by another loader, so the value loaded here
+			// isn't stored.
+		case IsErrNil(err):
+			if lease, err = d.acquire(ctx, key); err != nil {
+				d.logger(ctx).Errorf("lease cache %q error: %v", key, err)
+			}
+		default:
+			d.logger(ctx).Errorf("get cache %q error: %v", key, err)
+		}
+		r, err := loader(ctx, key)
+		if err != nil {
+			if lease != "" {
+				_, _ = d.client.Int(ctx, "EVAL", unlockScript, 1, d.prefix+key, lease)
+			}
+			return nil, err
+		}
+		if lease != "" {
+			if err = d.store(ctx, key, lease, r, arg); err != nil {
+				d.logger(ctx).Errorf("set cache %q error: %v", key, err)
+			}
+		}
+		return r, nil
+	}
+	loadType, result, err := d.local.Load(ctx, m, key, l, arg)
+	if loadType == cache.LoadSource && remote {
+		loadType = cache.LoadRemote
+	}
+	return loadType, result, err
+}
+
+// acquire sets a lease on the missing key, returns "" if the key has been set
+// by others.
+func (d *CacheDriver) acquire(ctx context.Context, key string) (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	lease := cacheLeasePrefix + token
+	_, err = d.client.Set(ctx, d.prefix+key, lease, "PX", cacheLeaseTTL.Milliseconds(), "NX")
+	if err != nil {
+		if IsErrNil(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return lease, nil
+}
+
+// store replaces the lease of the key with the value.
+func (d *CacheDriver) store(ctx context.Context, key string, lease string, r cache.Result, arg cache.OptionArg) error {
+	s, err := r.JSON()
+	if err != nil {
+		return err
+	}
+	ttl := arg.ExpireAfterWrite
+	if ttl <= 0 {
+		ttl = d.ttl
+	}
+	_, err = d.client.Int(ctx, "EVAL", storeScript, 1, d.prefix+key, lease, s, ttl.Milliseconds())
+	return err
+}
+
+// Invalidate deletes the key (or the lease on it) from Redis and the local
+// storage, and then broadcasts the invalidation to other instances.
+func (d *CacheDriver) Invalidate(ctx context.Context, key string) error {
+	if _, err := d.client.Del(ctx, d.prefix+key); err != nil {
+		return err
+	}
+	d.evict(key)
+	_, err := d.client.Publish(ctx, d.channel, d.id+":"+key)
+	return err
+}
+
+// Close stops listening to the invalidations.
+func (d *CacheDriver) Close() error {
+	var err error
+	d.closed.Do(func() {
+		close(d.done)
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		err = d.sub.Close()
+	})
+	return err
+}
+
+func (d *CacheDriver) evict(key string) {
+	d.storage.Sharding(key).Delete(key)
+}
+
+// listen evicts the local copies invalidated by other instances until ctx is
+// done or the driver is closed, and subscribes again when the subscription
+// is broken.
+func (d *CacheDriver) listen(ctx context.Context) {
+	defer d.Close()
+	for {
+		d.mutex.Lock()
+		sub := d.sub
+		d.mutex.Unlock()
+		if !d.receive(ctx, sub) {
+			return
+		}
+		d.logger(ctx).Errorf("subscription of %q is broken", d.channel)
+		if !d.resubscribe(ctx) {
+			return
+		}
+	}
+}
+
+// receive handles the invalidations until the subscription is closed, returns
+// false if ctx is done or the driver is closed.
+func (d *CacheDriver) receive(ctx context.Context, sub Subscription) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-d.done:
+			return false
+		case msg, ok := <-sub.Channel():
+			if !ok {
+				select {
+				case <-d.done:
+					return false
+				default:
+					return true
+				}
+			}
+			ss := strings.SplitN(msg.Payload, ":", 2)
+			if len(ss) != 2 || ss[0] == d.id {
+				continue
+			}
+			d.evict(ss[1])
+		}
+	}
+}
+
+// resubscribe subscribes the invalidation channel with exponential backoff
+// until it succeeds, returns false if ctx is done or the driver is closed.
+func (d *CacheDriver) resubscribe(ctx context.Context) bool {
+	delay := cacheResubscribeMin
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-d.done:
+			return false
+		case <-time.After(delay):
+		}
+		sub, err := d.client.Subscribe(ctx, d.channel)
+		if err == nil {
+			d.mutex.Lock()
+			defer d.mutex.Unlock()
+			select {
+			case <-d.done:
+				_ = sub.Close()
+				return false
+			default:
+			}
+			d.sub = sub
+			return true
+		}
+		d.logger(ctx).Errorf("subscribe %q error: %v", d.channel, err)
+		if delay *= 2; delay > cacheResubscribeMax {
+			delay = cacheResubscribeMax
+		}
+	}
+}
+
+func (d *CacheDriver) logger(ctx context.Context) *log.Entry {
+	return log.GetLogger("redis.CacheDriver").WithContext(ctx)
+}
@@ -0,0 +1,266 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/cache"
+	"github.com/go-spring/spring-core/redis"
+)
+
+type memSubscription struct {
+	d  *pubsubDriver
+	ch chan *redis.Message
+}
+
+func (s *memSubscription) Channel() <-chan *redis.Message {
+	return s.ch
+}
+
+func (s *memSubscription) Close() error {
+	s.d.mutex.Lock()
+	defer s.d.mutex.Unlock()
+	if _, ok := s.d.subs[s]; ok {
+		delete(s.d.subs, s)
+		close(s.ch)
+	}
+	return nil
+}
+
+// pubsubDriver is an in-memory redis.Driver supporting the commands used by
+// CacheDriver, and it implements redis.Subscriber.
+type pubsubDriver struct {
+	mutex      sync.Mutex
+	data       map[string]string
+	ttl        map[string]int64
+	subs       map[*memSubscription]string
+	subscribed int
+}
+
+func newPubSubDriver() *pubsubDriver {
+	return &pubsubDriver{
+		data: make(map[string]string),
+		ttl:  make(map[string]int64),
+		subs: make(map[*memSubscription]string),
+	}
+}
+
+func (d *pubsubDriver) Subscribe(ctx context.Context, channels ...string) (redis.Subscription, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	s := &memSubscription{d: d, ch: make(chan *redis.Message, 16)}
+	d.subs[s] = channels[0]
+	d.subscribed++
+	return s, nil
+}
+
+// Break closes all subscriptions as if the connections are broken.
+func (d *pubsubDriver) Break() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for s := range d.subs {
+		delete(d.subs, s)
+		close(s.ch)
+	}
+}
+
+func (d *pubsubDriver) Subscribed() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.subscribed
+}
+
+func (d *pubsubDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	switch args[0] {
+	case "GET":
+		v, ok := d.data[args[1].(string)]
+		if !ok {
+			return nil, redis.ErrNil()
+		}
+		return v, nil
+	case "SET":
+		key := args[1].(string)
+		if _, ok := d.data[key]; ok && args[len(args)-1] == "NX" {
+			return nil, redis.ErrNil()
+		}
+		d.data[key] = args[2].(string)
+		return "OK", nil
+	case "EVAL":
+		// the scripts of CacheDriver: EVAL script 1 key lease [value ttl]
+		key := args[3].(string)
+		if d.data[key] != args[4] {
+			return int64(0), nil
+		}
+		if len(args) == 5 {
+			delete(d.data, key)
+		} else {
+			d.data[key] = args[5].(string)
+			d.ttl[key] = args[6].(int64)
+		}
+		return int64(1), nil
+	case "DEL":
+		delete(d.data, args[1].(string))
+		return int64(1), nil
+	case "PUBLISH":
+		var n int64
+		for s, channel := range d.subs {
+			if channel == args[1] {
+				s.ch <- &redis.Message{Channel: channel, Payload: args[2].(string)}
+				n++
+			}
+		}
+		return n, nil
+	}
+	return nil, fmt.Errorf("unsupported command %v", args[0])
+}
+
+func (d *pubsubDriver) TTL(key string) int64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.ttl[key]
+}
+
+func (d *pubsubDriver) Has(key string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	_, ok := d.data[key]
+	return ok
+}
+
+func TestCacheDriver(t *testing.T) {
+
+	ctx := context.Background()
+
+	_, err := redis.NewCacheDriver(ctx, redis.NewClient(newMemDriver()))
+	assert.Error(t, err, "redis: driver doesn't support pub/sub")
+
+	d := newPubSubDriver()
+	c := redis.NewClient(d)
+
+	s1 := cache.NewStorage(1, cache.SimpleHash)
+	d1, err := redis.NewCacheDriver(ctx, c, redis.CacheLocal(cache.Engine, s1))
+	assert.Nil(t, err)
+	defer d1.Close()
+
+	s2 := cache.NewStorage(1, cache.SimpleHash)
+	d2, err := redis.NewCacheDriver(ctx, c, redis.CacheLocal(cache.Engine, s2))
+	assert.Nil(t, err)
+	defer d2.Close()
+
+	count := 0
+	loader := func(ctx context.Context, key string) (cache.Result, error) {
+		count++
+		return cache.NewValueResult(map[string]string{"name": key}), nil
+	}
+
+	load := func(d *redis.CacheDriver, s cache.Shardable) cache.LoadType {
+		arg := cache.OptionArg{ExpireAfterWrite: time.Minute}
+		loadType, result, err := d.Load(ctx, s.Sharding("a"), "a", loader, arg)
+		assert.Nil(t, err)
+		var v map[string]string
+		assert.Nil(t, cache.NewJSONResult(mustJSON(t, result)).Load(&v))
+		assert.Equal(t, v, map[string]string{"name": "a"})
+		return loadType
+	}
+
+	assert.Equal(t, load(d1, s1), cache.LoadSource)
+	assert.True(t, d.Has("cache:a"))
+	assert.Equal(t, load(d2, s2), cache.LoadRemote)
+	assert.Equal(t, load(d2, s2), cache.LoadCache)
+	assert.Equal(t, count, 1)
+
+	err = d1.Invalidate(ctx, "a")
+	assert.Nil(t, err)
+	assert.False(t, d.Has("cache:a"))
+	waitFor(t, func() bool {
+		_, ok := s2.Sharding("a").Load("a")
+		return !ok
+	})
+
+	assert.Equal(t, load(d2, s2), cache.LoadSource)
+	assert.Equal(t, load(d1, s1), cache.LoadRemote)
+	assert.Equal(t, count, 2)
+	assert.Equal(t, d.TTL("cache:a"), time.Minute.Milliseconds())
+
+	// the subscription is broken, the invalidations are received again after
+	// the driver subscribes the channel again.
+	d.Break()
+	waitFor(t, func() bool {
+		return d.Subscribed() == 4
+	})
+	err = d1.Invalidate(ctx, "a")
+	assert.Nil(t, err)
+	waitFor(t, func() bool {
+		_, ok := s2.Sharding("a").Load("a")
+		return !ok
+	})
+}
+
+func TestCacheDriver_Lease(t *testing.T) {
+
+	ctx := context.Background()
+	d := newPubSubDriver()
+	c := redis.NewClient(d)
+
+	s := cache.NewStorage(1, cache.SimpleHash)
+	cd, err := redis.NewCacheDriver(ctx, c, redis.CacheLocal(cache.Engine, s), redis.CacheTTL(time.Second))
+	assert.Nil(t, err)
+	defer cd.Close()
+
+	// the value is invalidated while loading, so the stale value isn't stored.
+	loader := func(ctx context.Context, key string) (cache.Result, error) {
+		assert.True(t, d.Has("cache:"+key))
+		err := cd.Invalidate(ctx, key)
+		assert.Nil(t, err)
+		return cache.NewValueResult("stale"), nil
+	}
+	_, _, err = cd.Load(ctx, s.Sharding("a"), "a", loader, cache.OptionArg{})
+	assert.Nil(t, err)
+	assert.False(t, d.Has("cache:a"))
+
+	// the ttl of the driver is used when ExpireAfterWrite is 0.
+	loader = func(ctx context.Context, key string) (cache.Result, error) {
+		return cache.NewValueResult("fresh"), nil
+	}
+	_, _, err = cd.Load(ctx, s.Sharding("b"), "b", loader, cache.OptionArg{})
+	assert.Nil(t, err)
+	assert.True(t, d.Has("cache:b"))
+	assert.Equal(t, d.TTL("cache:b"), time.Second.Milliseconds())
+
+	// the lease is released when the loader fails.
+	loader = func(ctx context.Context, key string) (cache.Result, error) {
+		return nil, errors.New("load failed")
+	}
+	_, _, err = cd.Load(ctx, s.Sharding("c"), "c", loader, cache.OptionArg{})
+	assert.Error(t, err, "load failed")
+	assert.False(t, d.Has("cache:c"))
+}
+
+func mustJSON(t *testing.T, r cache.Result) string {
+	s, err := r.JSON()
+	assert.Nil(t, err)
+	return s
+}
@@ -208,7 +208,7 @@ func (c *Cases) BitOpXor() *Case {
 			{
 			  "Protocol": "REDIS",
 			  "Request": "GET dest",
-			  "Response": "\a\r\f\x06\x04\x14"
+			  "Response": "\"\\a\\r\\f\\x06\\x04\\x14\""
 			}
 		  ]
 		}`,
@@ -247,7 +247,7 @@ func (c *Cases) BitOpNot() *Case {
 			{
 			  "Protocol": "REDIS",
 			  "Request": "GET dest",
-			  "Response": "\x99\x90\x90\x9d\x9e\x8d"
+			  "Response": "\"\\x99\\x90\\x90\\x9d\\x9e\\x8d\""
 			}
 		  ]
 		}`,
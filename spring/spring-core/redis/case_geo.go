@@ -0,0 +1,298 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+)
+
+func assertGeoPos(t *testing.T, pos *GeoPos, longitude, latitude float64) {
+	assert.NotNil(t, pos)
+	if pos == nil {
+		return
+	}
+	assert.True(t, math.Abs(pos.Longitude-longitude) < 1e-5)
+	assert.True(t, math.Abs(pos.Latitude-latitude) < 1e-5)
+}
+
+func (c *Cases) GeoAdd() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.GeoAdd(ctx, "Sicily", 13.361389, 38.115556, "Palermo", 15.087269, 37.502669, "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			r2, err := c.GeoAdd(ctx, "Sicily", "NX", 13.361389, 38.115556, "Palermo")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, int64(0))
+
+			r3, err := c.GeoSearch(ctx, "Sicily", "FROMLONLAT", 15, 37, "BYRADIUS", 200, "km", "ASC")
+			assert.Nil(t, err)
+			assert.Equal(t, r3, []string{"Catania", "Palermo"})
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 13.361389 38.115556 Palermo 15.087269 37.502669 Catania",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily NX 13.361389 38.115556 Palermo",
+				"Response": "\"0\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOSEARCH Sicily FROMLONLAT 15 37 BYRADIUS 200 km ASC",
+				"Response": "\"Catania\",\"Palermo\""
+			}]
+		}`,
+	}
+}
+
+func (c *Cases) GeoDist() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.GeoAdd(ctx, "Sicily", 13.361389, 38.115556, "Palermo", 15.087269, 37.502669, "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			r2, err := c.GeoDist(ctx, "Sicily", "Palermo", "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, 166274.1516)
+
+			r3, err := c.GeoDist(ctx, "Sicily", "Palermo", "Catania", "km")
+			assert.Nil(t, err)
+			assert.Equal(t, r3, 166.2742)
+
+			r4, err := c.GeoDist(ctx, "Sicily", "Palermo", "Catania", "mi")
+			assert.Nil(t, err)
+			assert.Equal(t, r4, 103.3182)
+
+			_, err = c.GeoDist(ctx, "Sicily", "Foo", "Bar")
+			assert.True(t, IsErrNil(err))
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 13.361389 38.115556 Palermo 15.087269 37.502669 Catania",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEODIST Sicily Palermo Catania",
+				"Response": "\"166274.1516\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEODIST Sicily Palermo Catania km",
+				"Response": "\"166.2742\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEODIST Sicily Palermo Catania mi",
+				"Response": "\"103.3182\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEODIST Sicily Foo Bar",
+				"Response": "NULL"
+			}]
+		}`,
+	}
+}
+
+func (c *Cases) GeoHash() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.GeoAdd(ctx, "Sicily", 13.361389, 38.115556, "Palermo", 15.087269, 37.502669, "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			r2, err := c.GeoHash(ctx, "Sicily", "Palermo", "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, []string{"sqc8b49rny0", "sqdtr74hyu0"})
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 13.361389 38.115556 Palermo 15.087269 37.502669 Catania",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOHASH Sicily Palermo Catania",
+				"Response": "\"sqc8b49rny0\",\"sqdtr74hyu0\""
+			}]
+		}`,
+	}
+}
+
+func (c *Cases) GeoPos() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.GeoAdd(ctx, "Sicily", 13.361389, 38.115556, "Palermo", 15.087269, 37.502669, "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			r2, err := c.GeoPos(ctx, "Sicily", "Palermo", "NonExisting", "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, len(r2), 3)
+			if len(r2) != 3 {
+				return
+			}
+			assertGeoPos(t, r2[0], 13.361389, 38.115556)
+			assert.Nil(t, r2[1])
+			assertGeoPos(t, r2[2], 15.087269, 37.502669)
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 13.361389 38.115556 Palermo 15.087269 37.502669 Catania",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOPOS Sicily Palermo NonExisting Catania",
+				"Response": "\"13.36138933897018433\",\"38.11555639549629859\",NULL,\"15.08726745843887329\",\"37.50266842333162032\""
+			}]
+		}`,
+	}
+}
+
+func (c *Cases) GeoSearch() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.GeoAdd(ctx, "Sicily", 13.361389, 38.115556, "Palermo", 15.087269, 37.502669, "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			r2, err := c.GeoAdd(ctx, "Sicily", 12.758489, 38.788135, "edge1", 17.241510, 38.788135, "edge2")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, int64(2))
+
+			r3, err := c.GeoSearch(ctx, "Sicily", "FROMLONLAT", 15, 37, "BYRADIUS", 200, "km", "ASC")
+			assert.Nil(t, err)
+			assert.Equal(t, r3, []string{"Catania", "Palermo"})
+
+			r4, err := c.GeoSearch(ctx, "Sicily", "FROMMEMBER", "Palermo", "BYRADIUS", 100, "km", "ASC")
+			assert.Nil(t, err)
+			assert.Equal(t, r4, []string{"Palermo", "edge1"})
+
+			r5, err := c.GeoSearchLocation(ctx, "Sicily", "FROMLONLAT", 15, 37, "BYBOX", 400, 400, "km", "ASC", "COUNT", 2)
+			assert.Nil(t, err)
+			assert.Equal(t, len(r5), 2)
+			if len(r5) != 2 {
+				return
+			}
+			assert.Equal(t, r5[0].Member, "Catania")
+			assert.Equal(t, r5[0].Dist, 56.4413)
+			assert.Equal(t, r5[0].Hash, int64(3479447370796909))
+			assertGeoPos(t, &GeoPos{r5[0].Longitude, r5[0].Latitude}, 15.087269, 37.502669)
+			assert.Equal(t, r5[1].Member, "Palermo")
+			assert.Equal(t, r5[1].Dist, 190.4424)
+			assert.Equal(t, r5[1].Hash, int64(3479099956230698))
+			assertGeoPos(t, &GeoPos{r5[1].Longitude, r5[1].Latitude}, 13.361389, 38.115556)
+
+			r6, err := c.GeoSearchLocation(ctx, "nonexisting", "FROMLONLAT", 15, 37, "BYRADIUS", 200, "km")
+			assert.Nil(t, err)
+			assert.Equal(t, len(r6), 0)
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 13.361389 38.115556 Palermo 15.087269 37.502669 Catania",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 12.758489 38.788135 edge1 17.24151 38.788135 edge2",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOSEARCH Sicily FROMLONLAT 15 37 BYRADIUS 200 km ASC",
+				"Response": "\"Catania\",\"Palermo\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOSEARCH Sicily FROMMEMBER Palermo BYRADIUS 100 km ASC",
+				"Response": "\"Palermo\",\"edge1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOSEARCH Sicily FROMLONLAT 15 37 BYBOX 400 400 km ASC COUNT 2 WITHCOORD WITHDIST WITHHASH",
+				"Response": "\"Catania\",\"56.4413\",\"3479447370796909\",\"15.08726745843887329\",\"37.50266842333162032\",\"Palermo\",\"190.4424\",\"3479099956230698\",\"13.36138933897018433\",\"38.11555639549629859\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOSEARCH nonexisting FROMLONLAT 15 37 BYRADIUS 200 km WITHCOORD WITHDIST WITHHASH",
+				"Response": ""
+			}]
+		}`,
+	}
+}
+
+func (c *Cases) GeoSearchStore() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.GeoAdd(ctx, "Sicily", 13.361389, 38.115556, "Palermo", 15.087269, 37.502669, "Catania")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			r2, err := c.GeoAdd(ctx, "Sicily", 12.758489, 38.788135, "edge1", 17.241510, 38.788135, "edge2")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, int64(2))
+
+			r3, err := c.GeoSearchStore(ctx, "key1", "Sicily", "FROMLONLAT", 15, 37, "BYBOX", 400, 400, "km", "ASC", "COUNT", 3)
+			assert.Nil(t, err)
+			assert.Equal(t, r3, int64(3))
+
+			r4, err := c.GeoSearch(ctx, "key1", "FROMLONLAT", 15, 37, "BYBOX", 400, 400, "km", "ASC")
+			assert.Nil(t, err)
+			assert.Equal(t, r4, []string{"Catania", "Palermo", "edge2"})
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 13.361389 38.115556 Palermo 15.087269 37.502669 Catania",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOADD Sicily 12.758489 38.788135 edge1 17.24151 38.788135 edge2",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOSEARCHSTORE key1 Sicily FROMLONLAT 15 37 BYBOX 400 400 km ASC COUNT 3",
+				"Response": "\"3\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "GEOSEARCH key1 FROMLONLAT 15 37 BYBOX 400 400 km ASC",
+				"Response": "\"Catania\",\"Palermo\",\"edge2\""
+			}]
+		}`,
+	}
+}
@@ -373,6 +373,49 @@ func (c *Cases) HMGet() *Case {
 	}
 }
 
+func (c *Cases) HScan() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.HSet(ctx, "myhash", "field1", "Hello", "field2", "World")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			cursor, r2, err := c.HScan(ctx, "myhash", 0)
+			assert.Nil(t, err)
+			assert.Equal(t, cursor, uint64(0))
+			assert.Equal(t, r2, []string{"field1", "Hello", "field2", "World"})
+
+			r3 := make(map[string]string)
+			it := c.HScanIter("myhash", "MATCH", "field*")
+			for it.Next(ctx) {
+				field := it.Val()
+				assert.True(t, it.Next(ctx))
+				r3[field] = it.Val()
+			}
+			assert.Nil(t, it.Err())
+			assert.Equal(t, r3, map[string]string{"field1": "Hello", "field2": "World"})
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "HSET myhash field1 Hello field2 World",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "HSCAN myhash 0",
+				"Response": "\"0\",\"field1\",\"Hello\",\"field2\",\"World\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "HSCAN myhash 0 MATCH field*",
+				"Response": "\"0\",\"field1\",\"Hello\",\"field2\",\"World\""
+			}]
+		}`,
+	}
+}
+
 func (c *Cases) HSet() *Case {
 	return &Case{
 		Func: func(t *testing.T, ctx context.Context, c *Client) {
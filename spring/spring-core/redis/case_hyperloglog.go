@@ -0,0 +1,156 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+)
+
+func (c *Cases) PFAdd() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.PFAdd(ctx, "hll", "a", "b", "c", "d", "e", "f", "g")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(1))
+
+			r2, err := c.PFCount(ctx, "hll")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, int64(7))
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "PFADD hll a b c d e f g",
+				"Response": "\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFCOUNT hll",
+				"Response": "\"7\""
+			}]
+		}`,
+	}
+}
+
+func (c *Cases) PFCount() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.PFAdd(ctx, "hll", "foo", "bar", "zap")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(1))
+
+			r2, err := c.PFAdd(ctx, "hll", "zap", "zap", "zap")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, int64(0))
+
+			r3, err := c.PFAdd(ctx, "hll", "foo", "bar")
+			assert.Nil(t, err)
+			assert.Equal(t, r3, int64(0))
+
+			r4, err := c.PFCount(ctx, "hll")
+			assert.Nil(t, err)
+			assert.Equal(t, r4, int64(3))
+
+			r5, err := c.PFAdd(ctx, "some-other-hll", 1, 2, 3)
+			assert.Nil(t, err)
+			assert.Equal(t, r5, int64(1))
+
+			r6, err := c.PFCount(ctx, "hll", "some-other-hll")
+			assert.Nil(t, err)
+			assert.Equal(t, r6, int64(6))
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "PFADD hll foo bar zap",
+				"Response": "\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFADD hll zap zap zap",
+				"Response": "\"0\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFADD hll foo bar",
+				"Response": "\"0\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFCOUNT hll",
+				"Response": "\"3\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFADD some-other-hll 1 2 3",
+				"Response": "\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFCOUNT hll some-other-hll",
+				"Response": "\"6\""
+			}]
+		}`,
+	}
+}
+
+func (c *Cases) PFMerge() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.PFAdd(ctx, "hll1", "foo", "bar", "zap", "a")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(1))
+
+			r2, err := c.PFAdd(ctx, "hll2", "a", "b", "c", "foo")
+			assert.Nil(t, err)
+			assert.Equal(t, r2, int64(1))
+
+			r3, err := c.PFMerge(ctx, "hll3", "hll1", "hll2")
+			assert.Nil(t, err)
+			assert.True(t, IsOK(r3))
+
+			r4, err := c.PFCount(ctx, "hll3")
+			assert.Nil(t, err)
+			assert.Equal(t, r4, int64(6))
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "PFADD hll1 foo bar zap a",
+				"Response": "\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFADD hll2 a b c foo",
+				"Response": "\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFMERGE hll3 hll1 hll2",
+				"Response": "\"OK\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "PFCOUNT hll3",
+				"Response": "\"6\""
+			}]
+		}`,
+	}
+}
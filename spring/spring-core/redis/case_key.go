@@ -560,6 +560,49 @@ func (c *Cases) RenameNX() *Case {
 	}
 }
 
+func (c *Cases) Scan() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.MSet(ctx, "firstname", "Jack", "lastname", "Stuntman", "age", 35)
+			assert.Nil(t, err)
+			assert.True(t, IsOK(r1))
+
+			cursor, r2, err := c.Scan(ctx, 0, "MATCH", "*name*")
+			assert.Nil(t, err)
+			assert.Equal(t, cursor, uint64(0))
+			sort.Strings(r2)
+			assert.Equal(t, r2, []string{"firstname", "lastname"})
+
+			var r3 []string
+			it := c.ScanIter("MATCH", "*name*")
+			for it.Next(ctx) {
+				r3 = append(r3, it.Val())
+			}
+			assert.Nil(t, it.Err())
+			sort.Strings(r3)
+			assert.Equal(t, r3, []string{"firstname", "lastname"})
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "MSET firstname Jack lastname Stuntman age 35",
+				"Response": "\"OK\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "SCAN 0 MATCH *name*",
+				"Response": "\"0\",\"lastname\",\"firstname\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "SCAN 0 MATCH *name*",
+				"Response": "\"0\",\"lastname\",\"firstname\""
+			}]
+		}`,
+	}
+}
+
 func (c *Cases) Touch() *Case {
 	return &Case{
 		Func: func(t *testing.T, ctx context.Context, c *Client) {
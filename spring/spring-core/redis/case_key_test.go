@@ -74,6 +74,10 @@ func TestRenameNX(t *testing.T) {
 	runCase(t, new(redis.Cases).RenameNX())
 }
 
+func TestScan(t *testing.T) {
+	runCase(t, new(redis.Cases).Scan())
+}
+
 func TestTouch(t *testing.T) {
 	runCase(t, new(redis.Cases).Touch())
 }
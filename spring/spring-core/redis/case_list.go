@@ -156,7 +156,7 @@ func (c *Cases) LInsertAfter() *Case {
 			}, {
 				"Protocol": "REDIS",
 				"Request": "LRANGE mylist 0 -1",
-				"Response": "\"Hello\",\"World\"",\"There\"
+				"Response": "\"Hello\",\"World\",\"There\""
 			}]
 		}`,
 	}
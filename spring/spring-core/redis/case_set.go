@@ -413,7 +413,23 @@ func (c *Cases) SIsMember() *Case {
 			assert.Nil(t, err)
 			assert.Equal(t, r3, int64(0))
 		},
-		Data: "",
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "SADD myset one",
+				"Response": "\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "SISMEMBER myset one",
+				"Response": "\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "SISMEMBER myset two",
+				"Response": "\"0\""
+			}]
+		}`,
 	}
 }
 
@@ -765,6 +781,49 @@ func (c *Cases) SRem() *Case {
 	}
 }
 
+func (c *Cases) SScan() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.SAdd(ctx, "myset", 1, 2, 3, "foo", "foobar", "feelsgood")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(6))
+
+			cursor, r2, err := c.SScan(ctx, "myset", 0, "MATCH", "f*")
+			assert.Nil(t, err)
+			assert.Equal(t, cursor, uint64(0))
+			sort.Strings(r2)
+			assert.Equal(t, r2, []string{"feelsgood", "foo", "foobar"})
+
+			var r3 []string
+			it := c.SScanIter("myset")
+			for it.Next(ctx) {
+				r3 = append(r3, it.Val())
+			}
+			assert.Nil(t, it.Err())
+			sort.Strings(r3)
+			assert.Equal(t, r3, []string{"1", "2", "3", "feelsgood", "foo", "foobar"})
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "SADD myset 1 2 3 foo foobar feelsgood",
+				"Response": "\"6\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "SSCAN myset 0 MATCH f*",
+				"Response": "\"0\",\"foo\",\"feelsgood\",\"foobar\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "SSCAN myset 0",
+				"Response": "\"0\",\"1\",\"foo\",\"2\",\"3\",\"feelsgood\",\"foobar\""
+			}]
+		}`,
+	}
+}
+
 func (c *Cases) SUnion() *Case {
 	return &Case{
 		Func: func(t *testing.T, ctx context.Context, c *Client) {
@@ -78,6 +78,10 @@ func TestSRem(t *testing.T) {
 	runCase(t, new(redis.Cases).SRem())
 }
 
+func TestSScan(t *testing.T) {
+	runCase(t, new(redis.Cases).SScan())
+}
+
 func TestSUnion(t *testing.T) {
 	runCase(t, new(redis.Cases).SUnion())
 }
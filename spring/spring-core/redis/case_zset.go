@@ -505,6 +505,10 @@ func (c *Cases) ZPopMaxN() *Case {
 				"Protocol": "REDIS",
 				"Request": "ZPOPMAX myzset 1",
 				"Response": "\"three\",\"3\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "ZPOPMAX nonexisting 1",
+				"Response": ""
 			}]
 		}`,
 	}
@@ -600,6 +604,10 @@ func (c *Cases) ZPopMinN() *Case {
 				"Protocol": "REDIS",
 				"Request": "ZPOPMIN myzset 1",
 				"Response": "\"one\",\"1\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "ZPOPMIN nonexisting 1",
+				"Response": ""
 			}]
 		}`,
 	}
@@ -692,6 +700,10 @@ func (c *Cases) ZRandMemberN() *Case {
 				"Protocol": "REDIS",
 				"Request": "ZRANDMEMBER dadi -5 WITHSCORES",
 				"Response": "\"uno\",\"1\",\"uno\",\"1\",\"cinque\",\"5\",\"sei\",\"6\",\"due\",\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "ZRANDMEMBER nonexisting -5 WITHSCORES",
+				"Response": ""
 			}]
 		}`,
 	}
@@ -1243,16 +1255,16 @@ func (c *Cases) ZRevRangeWithScores() *Case {
 				"Response": "\"1\""
 			}, {
 				"Protocol": "REDIS",
-				"Request": "ZREVRANGE myzset 0 -1",
-				"Response": "\"three\",\"two\",\"one\""
+				"Request": "ZREVRANGE myzset 0 -1 WITHSCORES",
+				"Response": "\"three\",\"3\",\"two\",\"2\",\"one\",\"1\""
 			}, {
 				"Protocol": "REDIS",
-				"Request": "ZREVRANGE myzset 2 3",
-				"Response": "\"one\""
+				"Request": "ZREVRANGE myzset 2 3 WITHSCORES",
+				"Response": "\"one\",\"1\""
 			}, {
 				"Protocol": "REDIS",
-				"Request": "ZREVRANGE myzset -2 -1",
-				"Response": "\"two\",\"one\""
+				"Request": "ZREVRANGE myzset -2 -1 WITHSCORES",
+				"Response": "\"two\",\"2\",\"one\",\"1\""
 			}]
 		}`,
 	}
@@ -1420,6 +1432,47 @@ func (c *Cases) ZRevRank() *Case {
 		}`,
 	}
 }
+func (c *Cases) ZScan() *Case {
+	return &Case{
+		Func: func(t *testing.T, ctx context.Context, c *Client) {
+
+			r1, err := c.ZAdd(ctx, "myzset", 1, "one", 2, "two")
+			assert.Nil(t, err)
+			assert.Equal(t, r1, int64(2))
+
+			cursor, r2, err := c.ZScan(ctx, "myzset", 0)
+			assert.Nil(t, err)
+			assert.Equal(t, cursor, uint64(0))
+			assert.Equal(t, r2, []string{"one", "1", "two", "2"})
+
+			var r3 []string
+			it := c.ZScanIter("myzset", "MATCH", "t*")
+			for it.Next(ctx) {
+				r3 = append(r3, it.Val())
+			}
+			assert.Nil(t, it.Err())
+			assert.Equal(t, r3, []string{"two", "2"})
+		},
+		Data: `
+		{
+			"Session": "df3b64266ebe4e63a464e135000a07cd",
+			"Actions": [{
+				"Protocol": "REDIS",
+				"Request": "ZADD myzset 1 one 2 two",
+				"Response": "\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "ZSCAN myzset 0",
+				"Response": "\"0\",\"one\",\"1\",\"two\",\"2\""
+			}, {
+				"Protocol": "REDIS",
+				"Request": "ZSCAN myzset 0 MATCH t*",
+				"Response": "\"0\",\"two\",\"2\""
+			}]
+		}`,
+	}
+}
+
 func (c *Cases) ZScore() *Case {
 	return &Case{
 		Func: func(t *testing.T, ctx context.Context, c *Client) {
@@ -130,6 +130,10 @@ func TestZRevRank(t *testing.T) {
 	runCase(t, new(redis.Cases).ZRevRank())
 }
 
+func TestZScan(t *testing.T) {
+	runCase(t, new(redis.Cases).ZScan())
+}
+
 func TestZScore(t *testing.T) {
 	runCase(t, new(redis.Cases).ZScore())
 }
@@ -0,0 +1,117 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SlotCount is the number of hash slots of redis cluster.
+const SlotCount = 16384
+
+// HashSlot returns the hash slot of the key, only the substring between the
+// first '{' and the next '}' is hashed when it is not empty.
+func HashSlot(key string) int {
+	if s := strings.IndexByte(key, '{'); s >= 0 {
+		if e := strings.IndexByte(key[s+1:], '}'); e > 0 {
+			key = key[s+1 : s+1+e]
+		}
+	}
+	return int(crc16(key) % SlotCount)
+}
+
+// crc16 implements the CRC16-CCITT (XMODEM) checksum used by redis cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// CommandKey returns the first key of the command, commands without keys,
+// such as PING and FLUSHALL, return false.
+func CommandKey(args []interface{}) (string, bool) {
+	if len(args) < 2 {
+		return "", false
+	}
+	cmd := strings.ToUpper(fmt.Sprint(args[0]))
+	if _, ok := keylessCommands[cmd]; ok {
+		return "", false
+	}
+	pos := 1
+	switch cmd {
+	case "BITOP", "ZUNION", "ZINTER", "ZDIFF":
+		pos = 2
+	case "EVAL", "EVALSHA":
+		pos = 3
+	case "XGROUP":
+		pos = 2
+	case "XREAD", "XREADGROUP":
+		pos = len(args)
+		for i := 1; i < len(args); i++ {
+			if strings.ToUpper(fmt.Sprint(args[i])) == "STREAMS" {
+				pos = i + 1
+				break
+			}
+		}
+	}
+	if len(args) <= pos {
+		return "", false
+	}
+	return fmt.Sprint(args[pos]), true
+}
+
+// IsReadOnlyCommand returns whether the command doesn't modify data, so it
+// can be sent to replicas.
+func IsReadOnlyCommand(args []interface{}) bool {
+	if len(args) == 0 {
+		return false
+	}
+	_, ok := readOnlyCommands[strings.ToUpper(fmt.Sprint(args[0]))]
+	return ok
+}
+
+var keylessCommands = map[string]struct{}{
+	"PING": {}, "ECHO": {}, "INFO": {}, "TIME": {}, "DBSIZE": {},
+	"FLUSHALL": {}, "FLUSHDB": {}, "RANDOMKEY": {}, "KEYS": {}, "SCAN": {},
+	"CONFIG": {}, "CLUSTER": {}, "CLIENT": {}, "COMMAND": {}, "SCRIPT": {},
+}
+
+var readOnlyCommands = map[string]struct{}{
+	"BITCOUNT": {}, "BITPOS": {}, "GETBIT": {}, "DUMP": {}, "EXISTS": {},
+	"PTTL": {}, "TTL": {}, "TYPE": {}, "TOUCH": {}, "GET": {}, "GETRANGE": {},
+	"MGET": {}, "STRLEN": {}, "HEXISTS": {}, "HGET": {}, "HGETALL": {},
+	"HKEYS": {}, "HLEN": {}, "HMGET": {}, "HRANDFIELD": {}, "HSTRLEN": {},
+	"HVALS": {}, "LINDEX": {}, "LLEN": {}, "LPOS": {}, "LRANGE": {},
+	"SCARD": {}, "SDIFF": {}, "SINTER": {}, "SISMEMBER": {}, "SMISMEMBER": {},
+	"SMEMBERS": {}, "SRANDMEMBER": {}, "SUNION": {}, "ZCARD": {}, "ZCOUNT": {},
+	"ZDIFF": {}, "ZINTER": {}, "ZLEXCOUNT": {}, "ZMSCORE": {}, "ZRANDMEMBER": {},
+	"ZRANGE": {}, "ZRANGEBYLEX": {}, "ZRANGEBYSCORE": {}, "ZRANK": {},
+	"ZREVRANGE": {}, "ZREVRANGEBYLEX": {}, "ZREVRANGEBYSCORE": {}, "ZREVRANK": {},
+	"ZSCORE": {}, "ZUNION": {}, "HSCAN": {}, "SSCAN": {}, "ZSCAN": {},
+	"GEODIST": {}, "GEOHASH": {}, "GEOPOS": {}, "GEOSEARCH": {}, "PFCOUNT": {},
+	"XLEN": {}, "XRANGE": {}, "XREVRANGE": {}, "XREAD": {},
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/conf"
+	"github.com/go-spring/spring-core/redis"
+)
+
+func TestHashSlot(t *testing.T) {
+	assert.Equal(t, redis.HashSlot(""), 0)
+	assert.Equal(t, redis.HashSlot("123456789"), 0x31C3)
+	assert.Equal(t, redis.HashSlot("foo"), 12182)
+	assert.Equal(t, redis.HashSlot("{user1000}.following"), redis.HashSlot("user1000"))
+	assert.Equal(t, redis.HashSlot("{user1000}.followers"), redis.HashSlot("user1000"))
+	assert.Equal(t, redis.HashSlot("foo{}{bar}"), redis.HashSlot("foo{}{bar}"))
+	assert.NotEqual(t, redis.HashSlot("foo{}{bar}"), redis.HashSlot("bar"))
+}
+
+func TestCommandKey(t *testing.T) {
+	testcases := []struct {
+		args []interface{}
+		key  string
+		ok   bool
+	}{
+		{[]interface{}{"PING"}, "", false},
+		{[]interface{}{"FLUSHALL", "ASYNC"}, "", false},
+		{[]interface{}{"GET", "mykey"}, "mykey", true},
+		{[]interface{}{"BITOP", "AND", "dest", "key1"}, "dest", true},
+		{[]interface{}{"ZUNION", 2, "zset1", "zset2"}, "zset1", true},
+		{[]interface{}{"EVAL", "return 1", 1, "mykey"}, "mykey", true},
+		{[]interface{}{"XGROUP", "CREATE", "mystream", "group", "$"}, "mystream", true},
+		{[]interface{}{"XREADGROUP", "GROUP", "g", "c", "COUNT", 1, "STREAMS", "mystream", ">"}, "mystream", true},
+		{[]interface{}{"XREADGROUP", "GROUP", "g", "c"}, "", false},
+	}
+	for _, c := range testcases {
+		key, ok := redis.CommandKey(c.args)
+		assert.Equal(t, key, c.key)
+		assert.Equal(t, ok, c.ok)
+	}
+	assert.True(t, redis.IsReadOnlyCommand([]interface{}{"get", "mykey"}))
+	assert.False(t, redis.IsReadOnlyCommand([]interface{}{"SET", "mykey", "1"}))
+}
+
+func TestConfig(t *testing.T) {
+
+	p := conf.New()
+	_ = p.Set("redis.mode", "cluster")
+	_ = p.Set("redis.cluster.addrs", "127.0.0.1:7000,127.0.0.1:7001")
+	_ = p.Set("redis.cluster.read-from", "replica")
+	var c redis.Config
+	err := p.Bind(&c, conf.Key("redis"))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Mode, redis.ModeCluster)
+	assert.Equal(t, c.Cluster.Addrs, []string{"127.0.0.1:7000", "127.0.0.1:7001"})
+	assert.Equal(t, c.Cluster.ReadFrom, redis.ReadFromReplica)
+	assert.Equal(t, c.Cluster.MaxRedirects, 3)
+	assert.Nil(t, c.Validate())
+
+	c.Cluster.ReadFrom = "nearest"
+	assert.Error(t, c.Validate(), "redis: unknown read policy nearest")
+
+	p = conf.New()
+	_ = p.Set("redis.mode", "sentinel")
+	_ = p.Set("redis.sentinel.master-name", "mymaster")
+	c = redis.Config{}
+	err = p.Bind(&c, conf.Key("redis"))
+	assert.Nil(t, err)
+	assert.Equal(t, c.Host, "127.0.0.1")
+	assert.Error(t, c.Validate(), "redis: sentinel addrs is empty")
+
+	c = redis.Config{Mode: "proxy"}
+	assert.Error(t, c.Validate(), "redis: unknown mode proxy")
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Elector elects a leader among the instances campaigning for the same key,
+// the instance holding the Lock of the key is the leader.
+type Elector struct {
+	lock      *Lock
+	onElected func(ctx context.Context)
+	onRevoked func()
+	leader    int32
+}
+
+// NewElector returns a new *Elector. onElected is called in a new goroutine
+// when the leadership is gained, its ctx is done when the leadership is lost.
+// onRevoked is called after the leadership is lost. Both can be nil.
+func NewElector(client *Client, key string, onElected func(ctx context.Context), onRevoked func(), opts ...LockOption) *Elector {
+	return &Elector{
+		lock:      NewLock(client, key, opts...),
+		onElected: onElected,
+		onRevoked: onRevoked,
+	}
+}
+
+// IsLeader returns whether the instance is the leader now.
+func (e *Elector) IsLeader() bool {
+	return atomic.LoadInt32(&e.leader) == 1
+}
+
+// Run campaigns for the leadership until ctx is done, the leadership is
+// released when ctx is done, and the instance campaigns again after the
+// leadership is lost or the campaign fails.
+func (e *Elector) Run(ctx context.Context) {
+	for {
+		if err := e.lock.Lock(ctx); err == nil {
+			e.lead(ctx)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.lock.retryInterval):
+		}
+	}
+}
+
+// lead keeps the leadership until the lock is lost or ctx is done.
+func (e *Elector) lead(ctx context.Context) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	atomic.StoreInt32(&e.leader, 1)
+	if e.onElected != nil {
+		go e.onElected(leaderCtx)
+	}
+	select {
+	case <-e.lock.Lost():
+	case <-ctx.Done():
+		_ = e.lock.Unlock(context.Background())
+	}
+	atomic.StoreInt32(&e.leader, 0)
+	cancel()
+	if e.onRevoked != nil {
+		e.onRevoked()
+	}
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrLockHeld    = errors.New("redis: lock is already held")
+	ErrLockNotHeld = errors.New("redis: lock is not held")
+)
+
+const (
+	// unlockScript deletes the key only when its value is the token.
+	unlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+	// renewScript resets the ttl of the key only when its value is the token.
+	renewScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`
+)
+
+// GoRunner starts goroutines, whose ctx is done when the runner is closed,
+// gs.Context is a GoRunner.
+type GoRunner interface {
+	Go(fn func(ctx context.Context))
+}
+
+type defaultRunner struct{}
+
+func (defaultRunner) Go(fn func(ctx context.Context)) {
+	go fn(context.Background())
+}
+
+type LockOption func(*Lock)
+
+// LockTTL sets the ttl of the key, the watchdog renews the key every ttl/3.
+func LockTTL(ttl time.Duration) LockOption {
+	return func(l *Lock) {
+		l.ttl = ttl
+	}
+}
+
+// LockRetryInterval sets the interval between two attempts of Lock.
+func LockRetryInterval(d time.Duration) LockOption {
+	return func(l *Lock) {
+		l.retryInterval = d
+	}
+}
+
+// LockRunner sets the GoRunner starting the watchdog goroutine.
+func LockRunner(r GoRunner) LockOption {
+	return func(l *Lock) {
+		l.runner = r
+	}
+}
+
+// Lock is a distributed lock based on `SET key token NX PX ttl`, the token is
+// random for every acquisition, and the key is released by compare-and-delete
+// so a lock never releases the key acquired by others. After acquired, the
+// watchdog goroutine renews the key until it's released or the renewal fails.
+type Lock struct {
+	client        *Client
+	key           string
+	ttl           time.Duration
+	retryInterval time.Duration
+	runner        GoRunner
+
+	mutex sync.Mutex
+	token string
+	stop  chan struct{}
+	lost  chan struct{}
+}
+
+// NewLock returns a new *Lock, the default ttl is 30s and the default retry
+// interval is 100ms.
+func NewLock(client *Client, key string, opts ...LockOption) *Lock {
+	l := &Lock{
+		client:        client,
+		key:           key,
+		ttl:           30 * time.Second,
+		retryInterval: 100 * time.Millisecond,
+		runner:        defaultRunner{},
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Key returns the key of the lock.
+func (l *Lock) Key() string {
+	return l.key
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// TryLock tries to acquire the lock once, returns false if the lock is held
+// by others.
+func (l *Lock) TryLock(ctx context.Context) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.token != "" {
+		return false, ErrLockHeld
+	}
+	token, err := newToken()
+	if err != nil {
+		return false, err
+	}
+	r, err := l.client.Set(ctx, l.key, token, "PX", l.ttl.Milliseconds(), "NX")
+	if err != nil {
+		if IsErrNil(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !IsOK(r) {
+		return false, nil
+	}
+	l.token = token
+	l.stop = make(chan struct{})
+	l.lost = make(chan struct{})
+	stop, lost := l.stop, l.lost
+	l.runner.Go(func(ctx context.Context) {
+		l.watchdog(ctx, token, stop, lost)
+	})
+	return true, nil
+}
+
+// Lock acquires the lock, it retries until the lock is acquired or ctx is done.
+func (l *Lock) Lock(ctx context.Context) error {
+	for {
+		ok, err := l.TryLock(ctx)
+		if err != nil || ok {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(l.retryInterval):
+		}
+	}
+}
+
+// Unlock stops the watchdog and releases the lock, returns ErrLockNotHeld if
+// the lock isn't acquired or has been lost.
+func (l *Lock) Unlock(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.token == "" {
+		return ErrLockNotHeld
+	}
+	token := l.token
+	l.token = ""
+	close(l.stop)
+	n, err := l.client.Int(ctx, "EVAL", unlockScript, 1, l.key, token)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// Lost returns a channel which is closed when the lock is lost because the
+// watchdog fails to renew it, returns nil if the lock isn't acquired.
+func (l *Lock) Lost() <-chan struct{} {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.lost
+}
+
+// watchdog renews the key every ttl/3 until stop is closed, it closes lost
+// when the key is held by others or isn't renewed before it expires.
+func (l *Lock) watchdog(ctx context.Context, token string, stop, lost chan struct{}) {
+	defer close(lost)
+	interval := l.ttl / 3
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := time.Now().Add(l.ttl)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ctx.Done():
+			l.release(token)
+			return
+		case <-ticker.C:
+		}
+		n, err := l.client.Int(ctx, "EVAL", renewScript, 1, l.key, token, l.ttl.Milliseconds())
+		if err == nil && n == 0 {
+			l.release(token)
+			return
+		}
+		if err == nil {
+			deadline = time.Now().Add(l.ttl)
+		} else if time.Now().Add(interval).After(deadline) {
+			l.release(token)
+			return
+		}
+	}
+}
+
+// release forgets the token if it's still the current one.
+func (l *Lock) release(token string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.token == token {
+		l.token = ""
+	}
+}
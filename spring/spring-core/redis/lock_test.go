@@ -0,0 +1,218 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/redis"
+)
+
+type memEntry struct {
+	value    string
+	expireAt time.Time
+}
+
+// memDriver is an in-memory redis.Driver supporting the commands used by Lock.
+type memDriver struct {
+	mutex sync.Mutex
+	data  map[string]memEntry
+}
+
+func newMemDriver() *memDriver {
+	return &memDriver{data: make(map[string]memEntry)}
+}
+
+func (d *memDriver) get(key string) (string, bool) {
+	e, ok := d.data[key]
+	if !ok || time.Now().After(e.expireAt) {
+		delete(d.data, key)
+		return "", false
+	}
+	return e.value, true
+}
+
+func (d *memDriver) Delete(key string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.data, key)
+}
+
+func (d *memDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	switch args[0] {
+	case "SET": // SET key value PX ms NX
+		key := args[1].(string)
+		if _, ok := d.get(key); ok {
+			return nil, redis.ErrNil()
+		}
+		ttl := time.Duration(args[4].(int64)) * time.Millisecond
+		d.data[key] = memEntry{value: args[2].(string), expireAt: time.Now().Add(ttl)}
+		return "OK", nil
+	case "EVAL": // EVAL script 1 key token [ttl]
+		key, token := args[3].(string), args[4].(string)
+		if v, ok := d.get(key); !ok || v != token {
+			return int64(0), nil
+		}
+		if strings.Contains(args[1].(string), "PEXPIRE") {
+			ttl := time.Duration(args[5].(int64)) * time.Millisecond
+			d.data[key] = memEntry{value: token, expireAt: time.Now().Add(ttl)}
+		} else {
+			delete(d.data, key)
+		}
+		return int64(1), nil
+	}
+	return nil, fmt.Errorf("unsupported command %v", args[0])
+}
+
+func waitFor(t *testing.T, f func() bool) {
+	for i := 0; i < 100; i++ {
+		if f() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("wait timeout")
+}
+
+type countRunner struct {
+	count int
+}
+
+func (r *countRunner) Go(fn func(ctx context.Context)) {
+	r.count++
+	go fn(context.Background())
+}
+
+func TestLock(t *testing.T) {
+
+	ctx := context.Background()
+	d := newMemDriver()
+	c := redis.NewClient(d)
+	runner := &countRunner{}
+
+	l1 := redis.NewLock(c, "lock", redis.LockTTL(60*time.Millisecond), redis.LockRunner(runner))
+	l2 := redis.NewLock(c, "lock", redis.LockTTL(60*time.Millisecond), redis.LockRetryInterval(5*time.Millisecond))
+	assert.Nil(t, l1.Lost())
+
+	ok, err := l1.TryLock(ctx)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, runner.count, 1)
+
+	ok, err = l1.TryLock(ctx)
+	assert.Equal(t, err, redis.ErrLockHeld)
+	assert.False(t, ok)
+
+	ok, err = l2.TryLock(ctx)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// the watchdog keeps the key alive longer than the ttl.
+	time.Sleep(200 * time.Millisecond)
+	ok, err = l2.TryLock(ctx)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+	defer cancel()
+	err = l2.Lock(timeoutCtx)
+	assert.Equal(t, err, context.DeadlineExceeded)
+
+	assert.Nil(t, l1.Unlock(ctx))
+	assert.Equal(t, l1.Unlock(ctx), redis.ErrLockNotHeld)
+
+	err = l2.Lock(ctx)
+	assert.Nil(t, err)
+
+	// the lock is lost when the key is taken away.
+	d.Delete("lock")
+	ok, err = l1.TryLock(ctx)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	select {
+	case <-l2.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("lock should be lost")
+	}
+	assert.Equal(t, l2.Unlock(ctx), redis.ErrLockNotHeld)
+	assert.Nil(t, l1.Unlock(ctx))
+}
+
+func TestElector(t *testing.T) {
+
+	c := redis.NewClient(newMemDriver())
+
+	var (
+		mutex  sync.Mutex
+		events []string
+	)
+	record := func(s string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		events = append(events, s)
+	}
+	newElector := func(name string) *redis.Elector {
+		return redis.NewElector(c, "leader", func(ctx context.Context) {
+			record(name + " elected")
+			<-ctx.Done()
+			record(name + " stopped")
+		}, func() {
+			record(name + " revoked")
+		}, redis.LockTTL(60*time.Millisecond), redis.LockRetryInterval(5*time.Millisecond))
+	}
+
+	e1 := newElector("e1")
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	done1 := make(chan struct{})
+	go func() {
+		e1.Run(ctx1)
+		close(done1)
+	}()
+	waitFor(t, e1.IsLeader)
+
+	e2 := newElector("e2")
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	go e2.Run(ctx2)
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, e2.IsLeader())
+
+	cancel1()
+	<-done1
+	assert.False(t, e1.IsLeader())
+	waitFor(t, e2.IsLeader)
+
+	waitFor(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return len(events) == 4
+	})
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, events[0], "e1 elected")
+	assert.InSlice(t, "e1 stopped", events)
+	assert.InSlice(t, "e1 revoked", events)
+	assert.InSlice(t, "e2 elected", events)
+}
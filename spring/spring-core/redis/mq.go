@@ -0,0 +1,448 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-core/mq"
+)
+
+const (
+	// StreamFieldID is the stream field storing Message.ID().
+	StreamFieldID = "id"
+
+	// StreamFieldBody is the stream field storing Message.Body().
+	StreamFieldBody = "body"
+)
+
+type StreamOption func(*streamOptions)
+
+type streamOptions struct {
+	prefix      string
+	groupStart  string
+	maxLen      int64
+	count       int64
+	block       time.Duration
+	minIdleTime time.Duration
+	claimEvery  time.Duration
+	maxDeliver  int64
+	deadSuffix  string
+	idleTime    time.Duration
+}
+
+func newStreamOptions(opts []StreamOption) streamOptions {
+	o := streamOptions{
+		groupStart:  "0",
+		count:       10,
+		block:       time.Second,
+		minIdleTime: 30 * time.Second,
+		claimEvery:  10 * time.Second,
+		maxDeliver:  10,
+		deadSuffix:  ":dead",
+		idleTime:    time.Hour,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// StreamPrefix sets the prefix of the stream keys, the key of a topic is the
+// prefix followed by the topic.
+func StreamPrefix(prefix string) StreamOption {
+	return func(o *streamOptions) {
+		o.prefix = prefix
+	}
+}
+
+// StreamGroupStart sets the ID from which a new consumer group starts, the
+// default is "0" so the messages sent before the group is created are also
+// consumed, use "$" to consume only the new messages.
+func StreamGroupStart(id string) StreamOption {
+	return func(o *streamOptions) {
+		o.groupStart = id
+	}
+}
+
+// StreamMaxLen trims the streams to about n entries when messages are sent,
+// the streams aren't trimmed if n <= 0.
+func StreamMaxLen(n int64) StreamOption {
+	return func(o *streamOptions) {
+		o.maxLen = n
+	}
+}
+
+// StreamCount sets the max number of entries read from a stream at once,
+// default is 10.
+func StreamCount(n int64) StreamOption {
+	return func(o *streamOptions) {
+		o.count = n
+	}
+}
+
+// StreamBlock sets how long XREADGROUP blocks when there is no entry, it
+// should be less than the read timeout of the client, default is 1s.
+func StreamBlock(d time.Duration) StreamOption {
+	return func(o *streamOptions) {
+		o.block = d
+	}
+}
+
+// StreamClaim sets how long an entry is pending before it's reclaimed from
+// the consumer that failed to ack it, and the interval between two reclaims,
+// default is 30s and 10s.
+func StreamClaim(minIdleTime, every time.Duration) StreamOption {
+	return func(o *streamOptions) {
+		o.minIdleTime = minIdleTime
+		o.claimEvery = every
+	}
+}
+
+// StreamDeadLetter sets how many times an entry is delivered at most, the
+// entry delivered more times is moved to the stream of the key followed by
+// suffix and acked, or just acked if suffix is empty. The entries are always
+// retried if n <= 0. Default is 10 and ":dead".
+func StreamDeadLetter(n int64, suffix string) StreamOption {
+	return func(o *streamOptions) {
+		o.maxDeliver = n
+		o.deadSuffix = suffix
+	}
+}
+
+// StreamConsumerIdle sets how long a consumer without pending entries is idle
+// before it's deleted from the group, which cleans up the consumers of the
+// stopped instances, they are never deleted if d <= 0. Default is 1h.
+func StreamConsumerIdle(d time.Duration) StreamOption {
+	return func(o *streamOptions) {
+		o.idleTime = d
+	}
+}
+
+// StreamProducer is a mq.Producer sending messages to Redis Streams, the
+// topic is the stream key, and Message.Extra() is mapped to the fields of the
+// entry, along with the StreamFieldID and StreamFieldBody fields.
+type StreamProducer struct {
+	client *Client
+	opts   streamOptions
+}
+
+// NewStreamProducer returns a new *StreamProducer.
+func NewStreamProducer(client *Client, opts ...StreamOption) *StreamProducer {
+	return &StreamProducer{client: client, opts: newStreamOptions(opts)}
+}
+
+// SendMessage adds the message to the stream of its topic.
+func (p *StreamProducer) SendMessage(ctx context.Context, msg mq.Message) error {
+	var args []interface{}
+	if p.opts.maxLen > 0 {
+		args = append(args, "MAXLEN", "~", p.opts.maxLen)
+	}
+	args = append(args, "*")
+	for k, v := range msg.Extra() {
+		if k == StreamFieldID || k == StreamFieldBody {
+			return fmt.Errorf("redis: extra key %s is reserved", k)
+		}
+		args = append(args, k, v)
+	}
+	if msg.ID() != "" {
+		args = append(args, StreamFieldID, msg.ID())
+	}
+	args = append(args, StreamFieldBody, string(msg.Body()))
+	_, err := p.client.XAdd(ctx, p.opts.prefix+msg.Topic(), args...)
+	return err
+}
+
+// toMessage converts an entry to a mq.Message, the ID of the message is the
+// StreamFieldID field if present, otherwise the ID of the entry.
+func toMessage(topic string, m XMessage) mq.Message {
+	msg := mq.NewMessage().WithTopic(topic).WithID(m.ID)
+	for k, v := range m.Values {
+		switch k {
+		case StreamFieldID:
+			msg.WithID(v)
+		case StreamFieldBody:
+			msg.WithBody([]byte(v))
+		default:
+			msg.WithExtra(k, v)
+		}
+	}
+	return msg
+}
+
+// StreamConsumer reads messages from Redis Streams by a consumer group, and
+// dispatches them to the mq.Consumers of their topics. An entry is acked
+// after all of its consumers succeed, otherwise it stays pending and it's
+// reclaimed by XAUTOCLAIM (Redis >= 6.2) after the min idle time, so the
+// messages are consumed at least once. The consumers that succeeded are
+// recorded in the hash of the key followed by ":{group}:progress" and they
+// are skipped when the entry is retried, and the entry delivered too many
+// times is moved to the dead letter stream. XREADGROUP blocks a connection,
+// so the client should be backed by a connection pool.
+type StreamConsumer struct {
+	client    *Client
+	group     string
+	name      string
+	opts      streamOptions
+	consumers map[string][]mq.Consumer
+	topics    []string
+}
+
+// NewStreamConsumer returns a new *StreamConsumer, name is the consumer name
+// in the group, which should be unique and stable for every instance.
+func NewStreamConsumer(client *Client, group, name string, opts ...StreamOption) *StreamConsumer {
+	return &StreamConsumer{
+		client:    client,
+		group:     group,
+		name:      name,
+		opts:      newStreamOptions(opts),
+		consumers: make(map[string][]mq.Consumer),
+	}
+}
+
+// Subscribe adds the consumer to its topics, it must be called before Run.
+func (c *StreamConsumer) Subscribe(consumer mq.Consumer) {
+	for _, topic := range consumer.Topics() {
+		if _, ok := c.consumers[topic]; !ok {
+			c.topics = append(c.topics, topic)
+		}
+		c.consumers[topic] = append(c.consumers[topic], consumer)
+	}
+}
+
+// Topics returns the subscribed topics in order.
+func (c *StreamConsumer) Topics() []string {
+	return c.topics
+}
+
+// Run creates the consumer groups, and consumes messages until ctx is done.
+func (c *StreamConsumer) Run(ctx context.Context) error {
+	if len(c.topics) == 0 {
+		return nil
+	}
+	for _, topic := range c.topics {
+		_, err := c.client.XGroupCreate(ctx, c.opts.prefix+topic, c.group, c.opts.groupStart, "MKSTREAM")
+		if err != nil && !IsBusyGroup(err) {
+			return err
+		}
+	}
+	var lastClaim time.Time
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= c.opts.claimEvery {
+			lastClaim = time.Now()
+			for _, topic := range c.topics {
+				c.claim(ctx, topic)
+				c.cleanup(ctx, topic)
+			}
+		}
+		if err := c.read(ctx); err != nil && ctx.Err() == nil {
+			c.logger(ctx).Errorf("read streams error: %v", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(c.opts.block):
+			}
+		}
+	}
+	return nil
+}
+
+// read reads the new entries of all topics and consumes them.
+func (c *StreamConsumer) read(ctx context.Context) error {
+	args := []interface{}{"COUNT", c.opts.count, "BLOCK", c.opts.block.Milliseconds(), "STREAMS"}
+	for _, topic := range c.topics {
+		args = append(args, c.opts.prefix+topic)
+	}
+	for range c.topics {
+		args = append(args, ">")
+	}
+	streams, err := c.client.XReadGroup(ctx, c.group, c.name, args...)
+	if err != nil {
+		if IsErrNil(err) {
+			return nil
+		}
+		return err
+	}
+	for _, s := range streams {
+		topic := s.Stream[len(c.opts.prefix):]
+		for _, m := range s.Messages {
+			c.consume(ctx, topic, m, false)
+		}
+	}
+	return nil
+}
+
+// claim takes over the entries pending longer than the min idle time, which
+// are failed to be consumed or held by a crashed consumer, and consumes them.
+func (c *StreamConsumer) claim(ctx context.Context, topic string) {
+	key := c.opts.prefix + topic
+	minIdleTime := c.opts.minIdleTime.Milliseconds()
+	start := "0-0"
+	for {
+		next, messages, err := c.client.XAutoClaim(ctx, key, c.group, c.name, minIdleTime, start, "COUNT", c.opts.count)
+		if err != nil {
+			c.logger(ctx).Errorf("claim stream %s error: %v", key, err)
+			return
+		}
+		deliveries := c.deliveries(ctx, key, messages)
+		for _, m := range messages {
+			if c.opts.maxDeliver > 0 && deliveries[m.ID] > c.opts.maxDeliver {
+				c.deadLetter(ctx, topic, m)
+				continue
+			}
+			c.consume(ctx, topic, m, true)
+		}
+		if next == "0-0" || ctx.Err() != nil {
+			return
+		}
+		start = next
+	}
+}
+
+// deliveries returns the delivery counts of the claimed entries, the counts
+// are missing if XPENDING fails, then the entries are consumed anyway.
+func (c *StreamConsumer) deliveries(ctx context.Context, key string, messages []XMessage) map[string]int64 {
+	if c.opts.maxDeliver <= 0 || len(messages) == 0 {
+		return nil
+	}
+	start, end := messages[0].ID, messages[len(messages)-1].ID
+	count := int64(len(messages))
+	entries, err := c.client.XPending(ctx, key, c.group, start, end, count, c.name)
+	if err != nil {
+		c.logger(ctx).Errorf("get pending entries of %s error: %v", key, err)
+		return nil
+	}
+	m := make(map[string]int64, len(entries))
+	for _, e := range entries {
+		m[e.ID] = e.Deliveries
+	}
+	return m
+}
+
+// deadLetter moves the entry delivered too many times to the dead letter
+// stream, and acks it.
+func (c *StreamConsumer) deadLetter(ctx context.Context, topic string, m XMessage) {
+	key := c.opts.prefix + topic
+	if c.opts.deadSuffix != "" && m.Values != nil {
+		args := []interface{}{"*"}
+		for k, v := range m.Values {
+			args = append(args, k, v)
+		}
+		if _, err := c.client.XAdd(ctx, key+c.opts.deadSuffix, args...); err != nil {
+			c.logger(ctx).Errorf("add message %s of %s to dead letter error: %v", m.ID, key, err)
+			return
+		}
+	}
+	c.logger(ctx).Warnf("message %s of %s is delivered more than %d times", m.ID, key, c.opts.maxDeliver)
+	c.ack(ctx, key, m.ID, true)
+}
+
+// progressKey returns the key of the hash storing the number of consumers
+// that succeeded for the entries of the stream.
+func (c *StreamConsumer) progressKey(key string) string {
+	return key + ":" + c.group + ":progress"
+}
+
+// consume dispatches the entry to the consumers of the topic, and acks it if
+// all consumers succeed. The consumers run in order and the dispatch stops at
+// the first failure, so the succeeded consumers are a prefix of them, whose
+// length is recorded so that a claimed entry starts from the failed one. The
+// deleted entries are acked directly.
+func (c *StreamConsumer) consume(ctx context.Context, topic string, m XMessage, claimed bool) {
+	key := c.opts.prefix + topic
+	done := 0
+	if claimed {
+		s, err := c.client.HGet(ctx, c.progressKey(key), m.ID)
+		if err != nil && !IsErrNil(err) {
+			c.logger(ctx).Errorf("get progress of message %s of %s error: %v", m.ID, key, err)
+			return
+		}
+		if s != "" {
+			if done, err = strconv.Atoi(s); err != nil {
+				c.logger(ctx).Errorf("get progress of message %s of %s error: %v", m.ID, key, err)
+				return
+			}
+		}
+	}
+	if m.Values != nil {
+		msg := toMessage(topic, m)
+		consumers := c.consumers[topic]
+		for i := done; i < len(consumers); i++ {
+			if err := consumers[i].Consume(ctx, msg); err != nil {
+				c.logger(ctx).Errorf("consume message %s of %s error: %v", m.ID, key, err)
+				if i > done {
+					c.progress(ctx, key, m.ID, i)
+				}
+				return
+			}
+		}
+	}
+	c.ack(ctx, key, m.ID, claimed && done > 0)
+}
+
+// progress records the number of consumers that succeeded for the entry.
+func (c *StreamConsumer) progress(ctx context.Context, key, id string, n int) {
+	if _, err := c.client.HSet(ctx, c.progressKey(key), id, n); err != nil {
+		c.logger(ctx).Errorf("set progress of message %s of %s error: %v", id, key, err)
+	}
+}
+
+// ack acks the entry, and removes its progress if it may have one.
+func (c *StreamConsumer) ack(ctx context.Context, key, id string, progress bool) {
+	if _, err := c.client.XAck(ctx, key, c.group, id); err != nil {
+		c.logger(ctx).Errorf("ack message %s of %s error: %v", id, key, err)
+		return
+	}
+	if !progress {
+		return
+	}
+	if _, err := c.client.HDel(ctx, c.progressKey(key), id); err != nil {
+		c.logger(ctx).Errorf("delete progress of message %s of %s error: %v", id, key, err)
+	}
+}
+
+// cleanup deletes the consumers of the group idle longer than the idle time
+// and without pending entries, which are left by the stopped instances.
+func (c *StreamConsumer) cleanup(ctx context.Context, topic string) {
+	if c.opts.idleTime <= 0 {
+		return
+	}
+	key := c.opts.prefix + topic
+	consumers, err := c.client.XInfoConsumers(ctx, key, c.group)
+	if err != nil {
+		c.logger(ctx).Errorf("get consumers of %s error: %v", key, err)
+		return
+	}
+	for _, consumer := range consumers {
+		if consumer.Name == c.name || consumer.Pending > 0 {
+			continue
+		}
+		if consumer.Idle < c.opts.idleTime.Milliseconds() {
+			continue
+		}
+		if _, err = c.client.XGroupDelConsumer(ctx, key, c.group, consumer.Name); err != nil {
+			c.logger(ctx).Errorf("delete consumer %s of %s error: %v", consumer.Name, key, err)
+		}
+	}
+}
+
+func (c *StreamConsumer) logger(ctx context.Context) *log.Entry {
+	return log.GetLogger("redis.StreamConsumer").WithContext(ctx)
+}
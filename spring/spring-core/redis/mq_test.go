@@ -0,0 +1,415 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/mq"
+	"github.com/go-spring/spring-core/redis"
+)
+
+func init() {
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Console name="Console"/>
+			</Appenders>
+			<Loggers>
+				<Root level="info">
+					<AppenderRef ref="Console"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	util.Panic(err).When(err != nil)
+}
+
+type streamEntry struct {
+	id     string
+	fields []interface{}
+}
+
+type streamPending struct {
+	time       time.Time // last delivery time.
+	deliveries int64
+}
+
+type streamGroup struct {
+	next      int                       // index of the next entry to be delivered.
+	pending   map[string]*streamPending // the pending entries.
+	consumers map[string]time.Time      // last seen time of the consumers.
+}
+
+// streamDriver is an in-memory redis.Driver supporting the commands used by
+// StreamProducer and StreamConsumer, the pending entries don't record their
+// consumers, so it works with only one running consumer per group.
+type streamDriver struct {
+	mutex   sync.Mutex
+	seq     int
+	streams map[string][]streamEntry
+	groups  map[string]*streamGroup // key is stream/group.
+	hashes  map[string]map[string]string
+	acked   []string
+}
+
+func newStreamDriver() *streamDriver {
+	return &streamDriver{
+		streams: make(map[string][]streamEntry),
+		groups:  make(map[string]*streamGroup),
+		hashes:  make(map[string]map[string]string),
+	}
+}
+
+func (d *streamDriver) entry(key, id string) interface{} {
+	for _, e := range d.streams[key] {
+		if e.id == id {
+			return []interface{}{e.id, e.fields}
+		}
+	}
+	return []interface{}{id, nil}
+}
+
+func (d *streamDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	switch args[0] {
+	case "XGROUP": // XGROUP CREATE key group id MKSTREAM
+		name := args[2].(string) + "/" + args[3].(string)
+		if args[1] == "DELCONSUMER" { // XGROUP DELCONSUMER key group consumer
+			delete(d.groups[name].consumers, args[4].(string))
+			return int64(0), nil
+		}
+		if _, ok := d.groups[name]; ok {
+			return nil, errors.New("BUSYGROUP Consumer Group name already exists")
+		}
+		next := len(d.streams[args[2].(string)])
+		if args[4] == "0" {
+			next = 0
+		}
+		d.groups[name] = &streamGroup{
+			next:      next,
+			pending:   make(map[string]*streamPending),
+			consumers: make(map[string]time.Time),
+		}
+		return "OK", nil
+	case "XADD": // XADD key [MAXLEN ~ n] * field value ...
+		key := args[1].(string)
+		i := 2
+		for args[i] != "*" {
+			i++
+		}
+		var fields []interface{}
+		for _, arg := range args[i+1:] {
+			fields = append(fields, fmt.Sprint(arg))
+		}
+		d.seq++
+		id := strconv.Itoa(d.seq) + "-0"
+		d.streams[key] = append(d.streams[key], streamEntry{id: id, fields: fields})
+		return id, nil
+	case "XREADGROUP": // XREADGROUP GROUP group consumer COUNT n BLOCK ms STREAMS key... >...
+		keys := args[9 : 9+(len(args)-9)/2]
+		var ret []interface{}
+		for _, k := range keys {
+			key := k.(string)
+			g := d.groups[key+"/"+args[2].(string)]
+			g.consumers[args[3].(string)] = time.Now()
+			var entries []interface{}
+			for ; g.next < len(d.streams[key]); g.next++ {
+				e := d.streams[key][g.next]
+				g.pending[e.id] = &streamPending{time: time.Now(), deliveries: 1}
+				entries = append(entries, []interface{}{e.id, e.fields})
+			}
+			if len(entries) > 0 {
+				ret = append(ret, []interface{}{key, entries})
+			}
+		}
+		if len(ret) == 0 {
+			d.mutex.Unlock()
+			time.Sleep(time.Millisecond)
+			d.mutex.Lock()
+			return nil, redis.ErrNil()
+		}
+		return ret, nil
+	case "XACK": // XACK key group id
+		g := d.groups[args[1].(string)+"/"+args[2].(string)]
+		delete(g.pending, args[3].(string))
+		d.acked = append(d.acked, args[3].(string))
+		return int64(1), nil
+	case "XAUTOCLAIM": // XAUTOCLAIM key group consumer min-idle 0-0 COUNT n
+		key := args[1].(string)
+		g := d.groups[key+"/"+args[2].(string)]
+		minIdle := time.Duration(args[4].(int64)) * time.Millisecond
+		entries := []interface{}{}
+		for id, p := range g.pending {
+			if time.Since(p.time) >= minIdle {
+				p.time = time.Now()
+				p.deliveries++
+				entries = append(entries, d.entry(key, id))
+			}
+		}
+		return []interface{}{"0-0", entries, []interface{}{}}, nil
+	case "XPENDING": // XPENDING key group start end count consumer
+		g := d.groups[args[1].(string)+"/"+args[2].(string)]
+		var entries []interface{}
+		for id, p := range g.pending {
+			idle := time.Since(p.time).Milliseconds()
+			entries = append(entries, []interface{}{id, args[6], idle, p.deliveries})
+		}
+		return entries, nil
+	case "XINFO": // XINFO CONSUMERS key group
+		g := d.groups[args[2].(string)+"/"+args[3].(string)]
+		var consumers []interface{}
+		for name, t := range g.consumers {
+			idle := time.Since(t).Milliseconds()
+			consumers = append(consumers, []interface{}{"name", name, "pending", int64(0), "idle", idle})
+		}
+		return consumers, nil
+	case "HGET": // HGET key field
+		if v, ok := d.hashes[args[1].(string)][args[2].(string)]; ok {
+			return v, nil
+		}
+		return nil, redis.ErrNil()
+	case "HSET": // HSET key field value
+		h, ok := d.hashes[args[1].(string)]
+		if !ok {
+			h = make(map[string]string)
+			d.hashes[args[1].(string)] = h
+		}
+		h[args[2].(string)] = fmt.Sprint(args[3])
+		return int64(1), nil
+	case "HDEL": // HDEL key field
+		delete(d.hashes[args[1].(string)], args[2].(string))
+		return int64(1), nil
+	}
+	return nil, fmt.Errorf("unsupported command %v", args[0])
+}
+
+func (d *streamDriver) Acked() []string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]string(nil), d.acked...)
+}
+
+type funcConsumer struct {
+	topics []string
+	fn     func(ctx context.Context, msg mq.Message) error
+}
+
+func (c *funcConsumer) Topics() []string {
+	return c.topics
+}
+
+func (c *funcConsumer) Consume(ctx context.Context, msg mq.Message) error {
+	return c.fn(ctx, msg)
+}
+
+func TestStream(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := newStreamDriver()
+	c := redis.NewClient(d)
+	opts := []redis.StreamOption{
+		redis.StreamPrefix("mq:"),
+		redis.StreamMaxLen(100),
+		redis.StreamClaim(20*time.Millisecond, 10*time.Millisecond),
+	}
+
+	p := redis.NewStreamProducer(c, opts...)
+	err := p.SendMessage(ctx, mq.NewMessage().WithTopic("order").WithExtra("id", "1"))
+	assert.Error(t, err, "redis: extra key id is reserved")
+
+	type event struct {
+		Name string `json:"name"`
+	}
+
+	var (
+		mutex    sync.Mutex
+		messages []mq.Message
+		events   []string
+		failed   bool
+	)
+
+	consumer := redis.NewStreamConsumer(c, "group", "consumer-1", opts...)
+	consumer.Subscribe(&funcConsumer{
+		topics: []string{"order"},
+		fn: func(ctx context.Context, msg mq.Message) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if msg.ID() == "2" && !failed {
+				failed = true
+				return errors.New("this is an error")
+			}
+			messages = append(messages, msg)
+			return nil
+		},
+	})
+	consumer.Subscribe(mq.Bind(func(ctx context.Context, e *event) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		events = append(events, e.Name)
+		return nil
+	}, "user"))
+	assert.Equal(t, consumer.Topics(), []string{"order", "user"})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.Nil(t, consumer.Run(ctx))
+	}()
+
+	waitFor(t, func() bool {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		return len(d.groups) == 2
+	})
+
+	for i := 1; i <= 2; i++ {
+		msg := mq.NewMessage().
+			WithTopic("order").
+			WithID(strconv.Itoa(i)).
+			WithBody([]byte("order-"+strconv.Itoa(i))).
+			WithExtra("trace", "t"+strconv.Itoa(i))
+		assert.Nil(t, p.SendMessage(ctx, msg))
+	}
+	msg := mq.NewMessage().WithTopic("user").WithBody([]byte(`{"name":"jim"}`))
+	assert.Nil(t, p.SendMessage(ctx, msg))
+
+	// the message 2 fails at the first time, and it's reclaimed later.
+	waitFor(t, func() bool {
+		return len(d.Acked()) == 3
+	})
+	cancel()
+	<-done
+
+	assert.Equal(t, d.Acked(), []string{"1-0", "3-0", "2-0"})
+	assert.Equal(t, events, []string{"jim"})
+	assert.Equal(t, len(messages), 2)
+	for i, m := range messages {
+		n := strconv.Itoa(i + 1)
+		assert.Equal(t, m.Topic(), "order")
+		assert.Equal(t, m.ID(), n)
+		assert.Equal(t, string(m.Body()), "order-"+n)
+		assert.Equal(t, m.Extra(), map[string]string{"trace": "t" + n})
+	}
+}
+
+func TestStream_Retry(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	d := newStreamDriver()
+	c := redis.NewClient(d)
+	opts := []redis.StreamOption{
+		redis.StreamClaim(20*time.Millisecond, 10*time.Millisecond),
+		redis.StreamDeadLetter(3, ":dead"),
+		redis.StreamConsumerIdle(time.Minute),
+	}
+
+	var (
+		mutex  sync.Mutex
+		first  []string
+		second []string
+	)
+
+	consumer := redis.NewStreamConsumer(c, "group", "consumer-1", opts...)
+	consumer.Subscribe(&funcConsumer{
+		topics: []string{"order"},
+		fn: func(ctx context.Context, msg mq.Message) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			first = append(first, msg.ID())
+			return nil
+		},
+	})
+	consumer.Subscribe(&funcConsumer{
+		topics: []string{"order"},
+		fn: func(ctx context.Context, msg mq.Message) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			second = append(second, msg.ID())
+			if msg.ID() == "bad" || len(second) == 1 {
+				return errors.New("this is an error")
+			}
+			return nil
+		},
+	})
+
+	// an idle consumer left by a stopped instance.
+	_, err := c.XGroupCreate(ctx, "order", "group", "0", "MKSTREAM")
+	assert.Nil(t, err)
+	d.groups["order/group"].consumers["consumer-0"] = time.Now().Add(-time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.Nil(t, consumer.Run(ctx))
+	}()
+
+	p := redis.NewStreamProducer(c, opts...)
+	assert.Nil(t, p.SendMessage(ctx, mq.NewMessage().WithTopic("order").WithID("retry")))
+	waitFor(t, func() bool {
+		return len(d.Acked()) == 1
+	})
+	msg := mq.NewMessage().WithTopic("order").WithID("bad").WithBody([]byte("body"))
+	assert.Nil(t, p.SendMessage(ctx, msg))
+	waitFor(t, func() bool {
+		return len(d.Acked()) == 2
+	})
+	cancel()
+	<-done
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	// the succeeded consumer isn't called again when the message is retried.
+	assert.Equal(t, first, []string{"retry", "bad"})
+	assert.Equal(t, second, []string{"retry", "retry", "bad", "bad", "bad"})
+	assert.Equal(t, d.Acked(), []string{"1-0", "2-0"})
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	// the message delivered more than 3 times is moved to the dead letter stream.
+	dead := d.streams["order:dead"]
+	assert.Equal(t, len(dead), 1)
+	fields := make(map[interface{}]interface{})
+	for i := 0; i < len(dead[0].fields); i += 2 {
+		fields[dead[0].fields[i]] = dead[0].fields[i+1]
+	}
+	assert.Equal(t, fields, map[interface{}]interface{}{"id": "bad", "body": "body"})
+	assert.Equal(t, d.hashes["order:group:progress"], map[string]string{})
+
+	_, ok := d.groups["order/group"].consumers["consumer-0"]
+	assert.False(t, ok)
+	_, ok = d.groups["order/group"].consumers["consumer-1"]
+	assert.True(t, ok)
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+)
+
+// GeoPos is the position of a member of a geospatial index.
+type GeoPos struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// GeoLocation is a member found by GEOSEARCH, with its distance from the
+// center, its geohash-encoded score and its position.
+type GeoLocation struct {
+	Member    string
+	Dist      float64
+	Hash      int64
+	Longitude float64
+	Latitude  float64
+}
+
+// GeoAdd https://redis.io/commands/geoadd
+// Command: GEOADD key [NX|XX] [CH] longitude latitude member [longitude latitude member ...]
+// Integer reply: the number of elements added to the sorted set,
+// not including elements already existing for which the score was updated.
+func (c *Client) GeoAdd(ctx context.Context, key string, args ...interface{}) (int64, error) {
+	args = append([]interface{}{"GEOADD", key}, args...)
+	return c.Int(ctx, args...)
+}
+
+// GeoDist https://redis.io/commands/geodist
+// Command: GEODIST key member1 member2 [m|km|ft|mi]
+// Bulk string reply: the distance as a double, or ErrNil if one or both
+// the elements are missing.
+func (c *Client) GeoDist(ctx context.Context, key string, member1, member2 string, args ...interface{}) (float64, error) {
+	args = append([]interface{}{"GEODIST", key, member1, member2}, args...)
+	return c.Float(ctx, args...)
+}
+
+// GeoHash https://redis.io/commands/geohash
+// Command: GEOHASH key member [member ...]
+// Array reply: the Geohash strings of the members, NULL if the member
+// doesn't exist.
+func (c *Client) GeoHash(ctx context.Context, key string, members ...string) ([]string, error) {
+	args := []interface{}{"GEOHASH", key}
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return c.StringSlice(ctx, args...)
+}
+
+// GeoPos https://redis.io/commands/geopos
+// Command: GEOPOS key member [member ...]
+// Array reply: the positions of the members, nil if the member doesn't exist.
+func (c *Client) GeoPos(ctx context.Context, key string, members ...string) ([]*GeoPos, error) {
+	args := []interface{}{"GEOPOS", key}
+	for _, member := range members {
+		args = append(args, member)
+	}
+	return c.GeoPosSlice(ctx, args...)
+}
+
+// GeoSearch https://redis.io/commands/geosearch
+// Command: GEOSEARCH key FROMMEMBER member|FROMLONLAT longitude latitude BYRADIUS radius m|km|ft|mi|BYBOX width height m|km|ft|mi [ASC|DESC] [COUNT count [ANY]]
+// Array reply: the members found.
+func (c *Client) GeoSearch(ctx context.Context, key string, args ...interface{}) ([]string, error) {
+	args = append([]interface{}{"GEOSEARCH", key}, args...)
+	return c.StringSlice(ctx, args...)
+}
+
+// GeoSearchLocation https://redis.io/commands/geosearch
+// Command: GEOSEARCH key FROMMEMBER member|FROMLONLAT longitude latitude BYRADIUS radius m|km|ft|mi|BYBOX width height m|km|ft|mi [ASC|DESC] [COUNT count [ANY]] WITHCOORD WITHDIST WITHHASH
+// Array reply: the members found, with their distances, scores and positions.
+func (c *Client) GeoSearchLocation(ctx context.Context, key string, args ...interface{}) ([]GeoLocation, error) {
+	args = append([]interface{}{"GEOSEARCH", key}, args...)
+	args = append(args, "WITHCOORD", "WITHDIST", "WITHHASH")
+	return c.GeoLocationSlice(ctx, args...)
+}
+
+// GeoSearchStore https://redis.io/commands/geosearchstore
+// Command: GEOSEARCHSTORE destination source FROMMEMBER member|FROMLONLAT longitude latitude BYRADIUS radius m|km|ft|mi|BYBOX width height m|km|ft|mi [ASC|DESC] [COUNT count [ANY]] [STOREDIST]
+// Integer reply: the number of elements in the resulting set.
+func (c *Client) GeoSearchStore(ctx context.Context, destination, source string, args ...interface{}) (int64, error) {
+	args = append([]interface{}{"GEOSEARCHSTORE", destination, source}, args...)
+	return c.Int(ctx, args...)
+}
@@ -103,6 +103,15 @@ func (c *Client) HMGet(ctx context.Context, key string, fields ...string) ([]int
 	return c.Slice(ctx, args...)
 }
 
+// HScan https://redis.io/commands/hscan
+// Command: HSCAN key cursor [MATCH pattern] [COUNT count]
+// Array reply: the next cursor and the fields and values of this iteration,
+// the iteration is finished when the next cursor is 0.
+func (c *Client) HScan(ctx context.Context, key string, cursor uint64, args ...interface{}) (uint64, []string, error) {
+	args = append([]interface{}{"HSCAN", key, cursor}, args...)
+	return c.ScanResult(ctx, args...)
+}
+
 // HSet https://redis.io/commands/hset
 // Command: HSET key field value [field value ...]
 // Integer reply: The number of fields that were added.
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+)
+
+// PFAdd https://redis.io/commands/pfadd
+// Command: PFADD key [element [element ...]]
+// Integer reply: 1 if at least 1 HyperLogLog internal register was altered. 0 otherwise.
+func (c *Client) PFAdd(ctx context.Context, key string, elements ...interface{}) (int64, error) {
+	args := []interface{}{"PFADD", key}
+	args = append(args, elements...)
+	return c.Int(ctx, args...)
+}
+
+// PFCount https://redis.io/commands/pfcount
+// Command: PFCOUNT key [key ...]
+// Integer reply: the approximated number of unique elements observed via PFADD.
+func (c *Client) PFCount(ctx context.Context, keys ...string) (int64, error) {
+	args := []interface{}{"PFCOUNT"}
+	for _, key := range keys {
+		args = append(args, key)
+	}
+	return c.Int(ctx, args...)
+}
+
+// PFMerge https://redis.io/commands/pfmerge
+// Command: PFMERGE destkey sourcekey [sourcekey ...]
+// Simple string reply: The command just returns OK.
+func (c *Client) PFMerge(ctx context.Context, destKey string, sourceKeys ...string) (string, error) {
+	args := []interface{}{"PFMERGE", destKey}
+	for _, key := range sourceKeys {
+		args = append(args, key)
+	}
+	return c.String(ctx, args...)
+}
@@ -134,6 +134,15 @@ func (c *Client) RenameNX(ctx context.Context, key, newKey string) (int64, error
 	return c.Int(ctx, args...)
 }
 
+// Scan https://redis.io/commands/scan
+// Command: SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+// Array reply: the next cursor and the keys of this iteration,
+// the iteration is finished when the next cursor is 0.
+func (c *Client) Scan(ctx context.Context, cursor uint64, args ...interface{}) (uint64, []string, error) {
+	args = append([]interface{}{"SCAN", cursor}, args...)
+	return c.ScanResult(ctx, args...)
+}
+
 // Touch https://redis.io/commands/touch
 // Command: TOUCH key [key ...]
 // Integer reply: The number of keys that were touched.
@@ -0,0 +1,60 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"errors"
+)
+
+// Message is a message received from a channel.
+type Message struct {
+	Channel string
+	Payload string
+}
+
+// Subscription receives the messages of the subscribed channels.
+type Subscription interface {
+	// Channel returns the channel of the messages, it's closed after the
+	// subscription is closed.
+	Channel() <-chan *Message
+	Close() error
+}
+
+// Subscriber is implemented by the drivers supporting pub/sub, the messages
+// are received by a dedicated connection.
+type Subscriber interface {
+	Subscribe(ctx context.Context, channels ...string) (Subscription, error)
+}
+
+// Publish https://redis.io/commands/publish
+// Command: PUBLISH channel message
+// Integer reply: the number of clients that received the message.
+func (c *Client) Publish(ctx context.Context, channel string, message interface{}) (int64, error) {
+	args := []interface{}{"PUBLISH", channel, message}
+	return c.Int(ctx, args...)
+}
+
+// Subscribe https://redis.io/commands/subscribe
+// Command: SUBSCRIBE channel [channel ...]
+// It returns an error if the driver doesn't implement Subscriber.
+func (c *Client) Subscribe(ctx context.Context, channels ...string) (Subscription, error) {
+	if c.subscriber == nil {
+		return nil, errors.New("redis: driver doesn't support pub/sub")
+	}
+	return c.subscriber.Subscribe(ctx, channels...)
+}
@@ -166,6 +166,15 @@ func (c *Client) SRem(ctx context.Context, key string, members ...interface{}) (
 	return c.Int(ctx, args...)
 }
 
+// SScan https://redis.io/commands/sscan
+// Command: SSCAN key cursor [MATCH pattern] [COUNT count]
+// Array reply: the next cursor and the members of this iteration,
+// the iteration is finished when the next cursor is 0.
+func (c *Client) SScan(ctx context.Context, key string, cursor uint64, args ...interface{}) (uint64, []string, error) {
+	args = append([]interface{}{"SSCAN", key, cursor}, args...)
+	return c.ScanResult(ctx, args...)
+}
+
 // SUnion https://redis.io/commands/sunion
 // Command: SUNION key [key ...]
 // Array reply: list with members of the resulting set.
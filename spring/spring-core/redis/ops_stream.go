@@ -0,0 +1,149 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"strings"
+)
+
+// XMessage is an entry of a stream.
+type XMessage struct {
+	ID     string
+	Values map[string]string
+}
+
+// XStream is the entries read from a stream.
+type XStream struct {
+	Stream   string
+	Messages []XMessage
+}
+
+// XPendingEntry is a pending entry of a consumer group.
+type XPendingEntry struct {
+	ID         string
+	Consumer   string
+	Idle       int64 // milliseconds since the entry was delivered last time.
+	Deliveries int64 // times the entry has been delivered.
+}
+
+// XConsumer is a consumer of a consumer group.
+type XConsumer struct {
+	Name    string
+	Pending int64 // number of the pending entries of the consumer.
+	Idle    int64 // milliseconds since the consumer interacted last time.
+}
+
+// XAck https://redis.io/commands/xack
+// Command: XACK key group ID [ID ...]
+// Integer reply: The number of messages successfully acknowledged.
+func (c *Client) XAck(ctx context.Context, key, group string, ids ...string) (int64, error) {
+	args := []interface{}{"XACK", key, group}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return c.Int(ctx, args...)
+}
+
+// XAdd https://redis.io/commands/xadd
+// Command: XADD key [NOMKSTREAM] [MAXLEN|MINID [=|~] threshold [LIMIT count]] *|ID field value [field value ...]
+// Bulk string reply: The ID of the added entry.
+func (c *Client) XAdd(ctx context.Context, key string, args ...interface{}) (string, error) {
+	args = append([]interface{}{"XADD", key}, args...)
+	return c.String(ctx, args...)
+}
+
+// XAutoClaim https://redis.io/commands/xautoclaim
+// Command: XAUTOCLAIM key group consumer min-idle-time start [COUNT count] [JUSTID]
+// Array reply: the cursor for the next call and the claimed entries.
+func (c *Client) XAutoClaim(ctx context.Context, key, group, consumer string, minIdleTime int64, start string, args ...interface{}) (string, []XMessage, error) {
+	args = append([]interface{}{"XAUTOCLAIM", key, group, consumer, minIdleTime, start}, args...)
+	return c.XAutoClaimResult(ctx, args...)
+}
+
+// XDel https://redis.io/commands/xdel
+// Command: XDEL key ID [ID ...]
+// Integer reply: the number of entries actually deleted.
+func (c *Client) XDel(ctx context.Context, key string, ids ...string) (int64, error) {
+	args := []interface{}{"XDEL", key}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	return c.Int(ctx, args...)
+}
+
+// XGroupCreate https://redis.io/commands/xgroup-create
+// Command: XGROUP CREATE key groupname id|$ [MKSTREAM]
+// Simple string reply: OK on success.
+func (c *Client) XGroupCreate(ctx context.Context, key, group, id string, args ...interface{}) (string, error) {
+	args = append([]interface{}{"XGROUP", "CREATE", key, group, id}, args...)
+	return c.String(ctx, args...)
+}
+
+// XGroupDelConsumer https://redis.io/commands/xgroup-delconsumer
+// Command: XGROUP DELCONSUMER key groupname consumername
+// Integer reply: the number of pending entries the consumer had.
+func (c *Client) XGroupDelConsumer(ctx context.Context, key, group, consumer string) (int64, error) {
+	args := []interface{}{"XGROUP", "DELCONSUMER", key, group, consumer}
+	return c.Int(ctx, args...)
+}
+
+// XInfoConsumers https://redis.io/commands/xinfo-consumers
+// Command: XINFO CONSUMERS key groupname
+// Array reply: the consumers of the group.
+func (c *Client) XInfoConsumers(ctx context.Context, key, group string) ([]XConsumer, error) {
+	args := []interface{}{"XINFO", "CONSUMERS", key, group}
+	return c.XConsumerSlice(ctx, args...)
+}
+
+// IsBusyGroup returns whether err is the error returned by XGROUP CREATE when
+// the group already exists.
+func IsBusyGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
+
+// XLen https://redis.io/commands/xlen
+// Command: XLEN key
+// Integer reply: the number of entries of the stream at key.
+func (c *Client) XLen(ctx context.Context, key string) (int64, error) {
+	args := []interface{}{"XLEN", key}
+	return c.Int(ctx, args...)
+}
+
+// XPending https://redis.io/commands/xpending
+// Command: XPENDING key group [[IDLE min-idle-time] start end count [consumer]]
+// Array reply: the pending entries in the range, args are the optional consumer.
+func (c *Client) XPending(ctx context.Context, key, group, start, end string, count int64, args ...interface{}) ([]XPendingEntry, error) {
+	args = append([]interface{}{"XPENDING", key, group, start, end, count}, args...)
+	return c.XPendingSlice(ctx, args...)
+}
+
+// XRange https://redis.io/commands/xrange
+// Command: XRANGE key start end [COUNT count]
+// Array reply: list of the entries with IDs matching the specified range.
+func (c *Client) XRange(ctx context.Context, key, start, end string, args ...interface{}) ([]XMessage, error) {
+	args = append([]interface{}{"XRANGE", key, start, end}, args...)
+	return c.XMessageSlice(ctx, args...)
+}
+
+// XReadGroup https://redis.io/commands/xreadgroup
+// Command: XREADGROUP GROUP group consumer [COUNT count] [BLOCK milliseconds] [NOACK] STREAMS key [key ...] ID [ID ...]
+// Array reply: the entries of every stream, ErrNil is returned if the command timed out.
+func (c *Client) XReadGroup(ctx context.Context, group, consumer string, args ...interface{}) ([]XStream, error) {
+	args = append([]interface{}{"XREADGROUP", "GROUP", group, consumer}, args...)
+	return c.XStreamSlice(ctx, args...)
+}
@@ -294,6 +294,15 @@ func (c *Client) ZRevRank(ctx context.Context, key, member string) (int64, error
 	return c.Int(ctx, args...)
 }
 
+// ZScan https://redis.io/commands/zscan
+// Command: ZSCAN key cursor [MATCH pattern] [COUNT count]
+// Array reply: the next cursor and the members and scores of this iteration,
+// the iteration is finished when the next cursor is 0.
+func (c *Client) ZScan(ctx context.Context, key string, cursor uint64, args ...interface{}) (uint64, []string, error) {
+	args = append([]interface{}{"ZSCAN", key, cursor}, args...)
+	return c.ScanResult(ctx, args...)
+}
+
 // ZScore https://redis.io/commands/zscore
 // Command: ZSCORE key member
 // Bulk string reply: the score of member (a double precision floating point number), represented as string.
@@ -21,6 +21,7 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 )
@@ -30,18 +31,82 @@ func IsOK(s string) bool {
 	return "OK" == s
 }
 
+// Connection modes of redis client.
+const (
+	ModeStandalone = "standalone"
+	ModeCluster    = "cluster"
+	ModeSentinel   = "sentinel"
+)
+
+// Read policies of cluster mode.
+const (
+	ReadFromMaster  = "master"  // all commands are sent to masters.
+	ReadFromReplica = "replica" // read-only commands are sent to replicas.
+	ReadFromRandom  = "random"  // read-only commands are sent to random nodes.
+)
+
 // Config Is the configuration of redis client.
 type Config struct {
-	Host           string `value:"${host:=127.0.0.1}"`
-	Port           int    `value:"${port:=6379}"`
-	Username       string `value:"${username:=}"`
-	Password       string `value:"${password:=}"`
-	Database       int    `value:"${database:=0}"`
-	Ping           bool   `value:"${ping:=true}"`
-	IdleTimeout    int    `value:"${idle-timeout:=0}"`
-	ConnectTimeout int    `value:"${connect-timeout:=0}"`
-	ReadTimeout    int    `value:"${read-timeout:=0}"`
-	WriteTimeout   int    `value:"${write-timeout:=0}"`
+	Mode           string         `value:"${mode:=standalone}"`
+	Host           string         `value:"${host:=127.0.0.1}"`
+	Port           int            `value:"${port:=6379}"`
+	Username       string         `value:"${username:=}"`
+	Password       string         `value:"${password:=}"`
+	Database       int            `value:"${database:=0}"`
+	Ping           bool           `value:"${ping:=true}"`
+	IdleTimeout    int            `value:"${idle-timeout:=0}"`
+	ConnectTimeout int            `value:"${connect-timeout:=0}"`
+	ReadTimeout    int            `value:"${read-timeout:=0}"`
+	WriteTimeout   int            `value:"${write-timeout:=0}"`
+	MaxIdle        int            `value:"${max-idle:=10}"`  // max idle connections of a pool.
+	MaxActive      int            `value:"${max-active:=0}"` // max connections of a pool, 0 means no limit.
+	Cluster        ClusterConfig  `value:"${cluster}"`
+	Sentinel       SentinelConfig `value:"${sentinel}"`
+}
+
+// ClusterConfig is the configuration of cluster mode, Username and Password
+// of Config are used to connect the nodes.
+type ClusterConfig struct {
+	Addrs        []string `value:"${addrs:=}"`           // seed nodes, host:port.
+	ReadFrom     string   `value:"${read-from:=master}"` // master, replica or random.
+	MaxRedirects int      `value:"${max-redirects:=3}"`  // max MOVED/ASK redirects.
+}
+
+// SentinelConfig is the configuration of sentinel mode, Username, Password
+// and Database of Config are used to connect the master.
+type SentinelConfig struct {
+	MasterName string   `value:"${master-name:=}"`
+	Addrs      []string `value:"${addrs:=}"` // sentinel nodes, host:port.
+	Username   string   `value:"${username:=}"`
+	Password   string   `value:"${password:=}"`
+}
+
+// Validate returns an error if the configuration of the mode is incomplete.
+func (c *Config) Validate() error {
+	switch c.Mode {
+	case "", ModeStandalone:
+		return nil
+	case ModeCluster:
+		if len(c.Cluster.Addrs) == 0 {
+			return errors.New("redis: cluster addrs is empty")
+		}
+		switch c.Cluster.ReadFrom {
+		case "", ReadFromMaster, ReadFromReplica, ReadFromRandom:
+		default:
+			return fmt.Errorf("redis: unknown read policy %s", c.Cluster.ReadFrom)
+		}
+		return nil
+	case ModeSentinel:
+		if c.Sentinel.MasterName == "" {
+			return errors.New("redis: sentinel master name is empty")
+		}
+		if len(c.Sentinel.Addrs) == 0 {
+			return errors.New("redis: sentinel addrs is empty")
+		}
+		return nil
+	default:
+		return fmt.Errorf("redis: unknown mode %s", c.Mode)
+	}
 }
 
 type Result struct {
@@ -64,18 +129,20 @@ var (
 
 // Client provides operations for redis commands.
 type Client struct {
-	driver Driver
+	driver     Driver
+	subscriber Subscriber
 }
 
 // NewClient returns a new *Client.
 func NewClient(driver Driver) *Client {
+	subscriber, _ := driver.(Subscriber)
 	if Recorder != nil {
 		driver = Recorder(driver)
 	}
 	if Replayer != nil {
 		driver = Replayer(driver)
 	}
-	return &Client{driver: driver}
+	return &Client{driver: driver, subscriber: subscriber}
 }
 
 func toInt64(v interface{}, err error) (int64, error) {
@@ -325,3 +392,310 @@ func toZItemSlice(v interface{}, err error) ([]ZItem, error) {
 func (c *Client) ZItemSlice(ctx context.Context, args ...interface{}) ([]ZItem, error) {
 	return toZItemSlice(c.driver.Exec(ctx, args))
 }
+
+// toFlatSlice flattens the nested arrays of the reply, so the replies from
+// the drivers and the ones from redis-cli or the replayer are the same.
+func toFlatSlice(v interface{}, err error) ([]interface{}, error) {
+	slice, err := toSlice(v, err)
+	if err != nil {
+		return nil, err
+	}
+	var val []interface{}
+	for _, r := range slice {
+		switch r.(type) {
+		case []interface{}, []string, *Result:
+			var s []interface{}
+			s, err = toFlatSlice(r, nil)
+			if err != nil {
+				return nil, err
+			}
+			val = append(val, s...)
+		default:
+			val = append(val, r)
+		}
+	}
+	return val, nil
+}
+
+func toScanResult(v interface{}, err error) (uint64, []string, error) {
+	slice, err := toFlatSlice(v, err)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(slice) == 0 {
+		return 0, nil, fmt.Errorf("redis: no data")
+	}
+	cursor, err := toString(slice[0], nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	next, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	var val []string
+	for _, r := range slice[1:] {
+		var str string
+		str, err = toString(r, nil)
+		if err != nil {
+			return 0, nil, err
+		}
+		val = append(val, str)
+	}
+	return next, val, nil
+}
+
+// ScanResult executes a command whose reply is a cursor and a `[]string`.
+func (c *Client) ScanResult(ctx context.Context, args ...interface{}) (uint64, []string, error) {
+	return toScanResult(c.driver.Exec(ctx, args))
+}
+
+func toGeoPosSlice(v interface{}, err error) ([]*GeoPos, error) {
+	slice, err := toFlatSlice(v, err)
+	if err != nil {
+		return nil, err
+	}
+	var val []*GeoPos
+	for i := 0; i < len(slice); {
+		if slice[i] == nil {
+			val = append(val, nil)
+			i++
+			continue
+		}
+		if i+1 >= len(slice) {
+			return nil, fmt.Errorf("redis: unexpected slice length %d", len(slice))
+		}
+		pos := new(GeoPos)
+		if pos.Longitude, err = toFloat64(slice[i], nil); err != nil {
+			return nil, err
+		}
+		if pos.Latitude, err = toFloat64(slice[i+1], nil); err != nil {
+			return nil, err
+		}
+		val = append(val, pos)
+		i += 2
+	}
+	return val, nil
+}
+
+// GeoPosSlice executes a command whose reply is a `[]*GeoPos`.
+func (c *Client) GeoPosSlice(ctx context.Context, args ...interface{}) ([]*GeoPos, error) {
+	return toGeoPosSlice(c.driver.Exec(ctx, args))
+}
+
+func toGeoLocationSlice(v interface{}, err error) ([]GeoLocation, error) {
+	slice, err := toFlatSlice(v, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(slice) == 0 {
+		return nil, nil
+	}
+	if len(slice)%5 != 0 {
+		return nil, fmt.Errorf("redis: unexpected slice length %d", len(slice))
+	}
+	val := make([]GeoLocation, len(slice)/5)
+	for i := 0; i < len(val); i++ {
+		s := slice[i*5 : i*5+5]
+		loc := &val[i]
+		if loc.Member, err = toString(s[0], nil); err != nil {
+			return nil, err
+		}
+		if loc.Dist, err = toFloat64(s[1], nil); err != nil {
+			return nil, err
+		}
+		if loc.Hash, err = toInt64(s[2], nil); err != nil {
+			return nil, err
+		}
+		if loc.Longitude, err = toFloat64(s[3], nil); err != nil {
+			return nil, err
+		}
+		if loc.Latitude, err = toFloat64(s[4], nil); err != nil {
+			return nil, err
+		}
+	}
+	return val, nil
+}
+
+// GeoLocationSlice executes a command whose reply is a `[]GeoLocation`, the
+// command must have the WITHDIST, WITHHASH and WITHCOORD options.
+func (c *Client) GeoLocationSlice(ctx context.Context, args ...interface{}) ([]GeoLocation, error) {
+	return toGeoLocationSlice(c.driver.Exec(ctx, args))
+}
+
+// toXMessage converts an entry of a stream, which is the nested array of the
+// ID and the fields, the fields are nil if the entry has been deleted. The
+// flattened replies, such as the replayed ones, aren't supported because the
+// number of fields is unknown.
+func toXMessage(v interface{}) (XMessage, error) {
+	slice, err := toSlice(v, nil)
+	if err != nil {
+		return XMessage{}, err
+	}
+	if len(slice) != 2 {
+		return XMessage{}, fmt.Errorf("redis: unexpected slice length %d", len(slice))
+	}
+	var msg XMessage
+	if msg.ID, err = toString(slice[0], nil); err != nil {
+		return XMessage{}, err
+	}
+	if msg.Values, err = toStringMap(slice[1], nil); err != nil {
+		return XMessage{}, err
+	}
+	return msg, nil
+}
+
+func toXMessageSlice(v interface{}, err error) ([]XMessage, error) {
+	slice, err := toSlice(v, err)
+	if err != nil {
+		return nil, err
+	}
+	var val []XMessage
+	for _, r := range slice {
+		var msg XMessage
+		if msg, err = toXMessage(r); err != nil {
+			return nil, err
+		}
+		val = append(val, msg)
+	}
+	return val, nil
+}
+
+// XMessageSlice executes a command whose reply is a `[]XMessage`.
+func (c *Client) XMessageSlice(ctx context.Context, args ...interface{}) ([]XMessage, error) {
+	return toXMessageSlice(c.driver.Exec(ctx, args))
+}
+
+func toXStreamSlice(v interface{}, err error) ([]XStream, error) {
+	slice, err := toSlice(v, err)
+	if err != nil {
+		return nil, err
+	}
+	var val []XStream
+	for _, r := range slice {
+		var s []interface{}
+		if s, err = toSlice(r, nil); err != nil {
+			return nil, err
+		}
+		if len(s) != 2 {
+			return nil, fmt.Errorf("redis: unexpected slice length %d", len(s))
+		}
+		var stream XStream
+		if stream.Stream, err = toString(s[0], nil); err != nil {
+			return nil, err
+		}
+		if stream.Messages, err = toXMessageSlice(s[1], nil); err != nil {
+			return nil, err
+		}
+		val = append(val, stream)
+	}
+	return val, nil
+}
+
+// XStreamSlice executes a command whose reply is a `[]XStream`.
+func (c *Client) XStreamSlice(ctx context.Context, args ...interface{}) ([]XStream, error) {
+	return toXStreamSlice(c.driver.Exec(ctx, args))
+}
+
+func toXAutoClaimResult(v interface{}, err error) (string, []XMessage, error) {
+	slice, err := toSlice(v, err)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(slice) < 2 {
+		return "", nil, fmt.Errorf("redis: unexpected slice length %d", len(slice))
+	}
+	next, err := toString(slice[0], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	val, err := toXMessageSlice(slice[1], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	return next, val, nil
+}
+
+// XAutoClaimResult executes a command whose reply is a cursor and a `[]XMessage`.
+func (c *Client) XAutoClaimResult(ctx context.Context, args ...interface{}) (string, []XMessage, error) {
+	return toXAutoClaimResult(c.driver.Exec(ctx, args))
+}
+
+func toXPendingSlice(v interface{}, err error) ([]XPendingEntry, error) {
+	slice, err := toSlice(v, err)
+	if err != nil {
+		return nil, err
+	}
+	var val []XPendingEntry
+	for _, r := range slice {
+		var s []interface{}
+		if s, err = toSlice(r, nil); err != nil {
+			return nil, err
+		}
+		if len(s) != 4 {
+			return nil, fmt.Errorf("redis: unexpected slice length %d", len(s))
+		}
+		var e XPendingEntry
+		if e.ID, err = toString(s[0], nil); err != nil {
+			return nil, err
+		}
+		if e.Consumer, err = toString(s[1], nil); err != nil {
+			return nil, err
+		}
+		if e.Idle, err = toInt64(s[2], nil); err != nil {
+			return nil, err
+		}
+		if e.Deliveries, err = toInt64(s[3], nil); err != nil {
+			return nil, err
+		}
+		val = append(val, e)
+	}
+	return val, nil
+}
+
+// XPendingSlice executes a command whose reply is a `[]XPendingEntry`.
+func (c *Client) XPendingSlice(ctx context.Context, args ...interface{}) ([]XPendingEntry, error) {
+	return toXPendingSlice(c.driver.Exec(ctx, args))
+}
+
+func toXConsumerSlice(v interface{}, err error) ([]XConsumer, error) {
+	slice, err := toSlice(v, err)
+	if err != nil {
+		return nil, err
+	}
+	var val []XConsumer
+	for _, r := range slice {
+		var s []interface{}
+		if s, err = toSlice(r, nil); err != nil {
+			return nil, err
+		}
+		if len(s)%2 != 0 {
+			return nil, fmt.Errorf("redis: unexpected slice length %d", len(s))
+		}
+		var c XConsumer
+		for i := 0; i < len(s); i += 2 {
+			var name string
+			if name, err = toString(s[i], nil); err != nil {
+				return nil, err
+			}
+			switch name {
+			case "name":
+				c.Name, err = toString(s[i+1], nil)
+			case "pending":
+				c.Pending, err = toInt64(s[i+1], nil)
+			case "idle":
+				c.Idle, err = toInt64(s[i+1], nil)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		val = append(val, c)
+	}
+	return val, nil
+}
+
+// XConsumerSlice executes a command whose reply is a `[]XConsumer`.
+func (c *Client) XConsumerSlice(ctx context.Context, args ...interface{}) ([]XConsumer, error) {
+	return toXConsumerSlice(c.driver.Exec(ctx, args))
+}
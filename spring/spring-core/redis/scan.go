@@ -0,0 +1,98 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+)
+
+// ScanIterator iterates the elements returned by SCAN, HSCAN, SSCAN or ZSCAN
+// and hides the cursor. For HSCAN the elements are field and value in turn,
+// and for ZSCAN they are member and score in turn.
+//
+//	it := c.ScanIter("MATCH", "user:*")
+//	for it.Next(ctx) {
+//		fmt.Println(it.Val())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ScanIterator struct {
+	scan   func(ctx context.Context, cursor uint64) (uint64, []string, error)
+	cursor uint64
+	page   []string
+	done   bool
+	val    string
+	err    error
+}
+
+// Next advances to the next element, it executes the command with the next
+// cursor when the elements of this iteration are exhausted. It returns false
+// when the iteration is finished or an error occurs.
+func (it *ScanIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		cursor, page, err := it.scan(ctx, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.cursor, it.page, it.done = cursor, page, cursor == 0
+	}
+	it.val, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Val returns the current element.
+func (it *ScanIterator) Val() string {
+	return it.val
+}
+
+// Err returns the error occurred during the iteration.
+func (it *ScanIterator) Err() error {
+	return it.err
+}
+
+// ScanIter returns a *ScanIterator of the keys, args are the options of SCAN.
+func (c *Client) ScanIter(args ...interface{}) *ScanIterator {
+	return &ScanIterator{scan: func(ctx context.Context, cursor uint64) (uint64, []string, error) {
+		return c.Scan(ctx, cursor, args...)
+	}}
+}
+
+// HScanIter returns a *ScanIterator of the fields and values of the hash.
+func (c *Client) HScanIter(key string, args ...interface{}) *ScanIterator {
+	return &ScanIterator{scan: func(ctx context.Context, cursor uint64) (uint64, []string, error) {
+		return c.HScan(ctx, key, cursor, args...)
+	}}
+}
+
+// SScanIter returns a *ScanIterator of the members of the set.
+func (c *Client) SScanIter(key string, args ...interface{}) *ScanIterator {
+	return &ScanIterator{scan: func(ctx context.Context, cursor uint64) (uint64, []string, error) {
+		return c.SScan(ctx, key, cursor, args...)
+	}}
+}
+
+// ZScanIter returns a *ScanIterator of the members and scores of the sorted set.
+func (c *Client) ZScanIter(key string, args ...interface{}) *ScanIterator {
+	return &ScanIterator{scan: func(ctx context.Context, cursor uint64) (uint64, []string, error) {
+		return c.ZScan(ctx, key, cursor, args...)
+	}}
+}
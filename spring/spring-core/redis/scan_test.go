@@ -0,0 +1,101 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/redis"
+)
+
+// funcDriver returns the replies like go-redis and redigo, which keep the
+// nested arrays.
+type funcDriver func(args []interface{}) (interface{}, error)
+
+func (f funcDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	return f(args)
+}
+
+func TestScanIterator(t *testing.T) {
+	ctx := context.Background()
+
+	pages := map[string][]interface{}{
+		"0":  {"17", []interface{}{"a", "b"}},
+		"17": {"5", []interface{}{}},
+		"5":  {"0", []interface{}{"c"}},
+	}
+	var cursors []string
+	c := redis.NewClient(funcDriver(func(args []interface{}) (interface{}, error) {
+		cursor := fmt.Sprint(args[1])
+		cursors = append(cursors, cursor)
+		return pages[cursor], nil
+	}))
+
+	var keys []string
+	it := c.ScanIter("COUNT", 2)
+	for it.Next(ctx) {
+		keys = append(keys, it.Val())
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, keys, []string{"a", "b", "c"})
+	assert.Equal(t, cursors, []string{"0", "17", "5"})
+	assert.False(t, it.Next(ctx))
+
+	c = redis.NewClient(funcDriver(func(args []interface{}) (interface{}, error) {
+		return nil, errors.New("connection refused")
+	}))
+	it = c.HScanIter("myhash")
+	assert.False(t, it.Next(ctx))
+	assert.Error(t, it.Err(), "connection refused")
+}
+
+func TestGeoNested(t *testing.T) {
+	ctx := context.Background()
+
+	c := redis.NewClient(funcDriver(func(args []interface{}) (interface{}, error) {
+		switch args[0] {
+		case "GEOPOS":
+			return []interface{}{
+				[]interface{}{"13.36138933897018433", "38.11555639549629859"},
+				nil,
+			}, nil
+		default:
+			return []interface{}{
+				[]interface{}{"Catania", "56.4413", int64(3479447370796909),
+					[]interface{}{"15.08726745843887329", "37.50266842333162032"}},
+			}, nil
+		}
+	}))
+
+	r1, err := c.GeoPos(ctx, "Sicily", "Palermo", "NonExisting")
+	assert.Nil(t, err)
+	assert.Equal(t, r1, []*redis.GeoPos{{Longitude: 13.36138933897018433, Latitude: 38.11555639549629859}, nil})
+
+	r2, err := c.GeoSearchLocation(ctx, "Sicily", "FROMLONLAT", 15, 37, "BYRADIUS", 200, "km")
+	assert.Nil(t, err)
+	assert.Equal(t, r2, []redis.GeoLocation{{
+		Member:    "Catania",
+		Dist:      56.4413,
+		Hash:      3479447370796909,
+		Longitude: 15.08726745843887329,
+		Latitude:  37.50266842333162032,
+	}})
+}
@@ -19,6 +19,8 @@ package validate
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"github.com/go-spring/spring-core/expr"
 )
@@ -37,6 +39,35 @@ func Register(name string, v Validator) {
 	validators[name] = v
 }
 
+// FieldError describes a field which failed to pass the validation.
+type FieldError struct {
+	Field   string      // path of the field, such as "User.Tags[0]".
+	Rule    string      // rule that failed, such as "expr" or "required".
+	Param   string      // parameter of the rule, such as the expression.
+	Value   interface{} // value of the field.
+	Message string      // default message of the error, without the value.
+}
+
+// Error returns the message of the error prefixed with the field path.
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// Errors collects all field errors of a struct.
+type Errors []*FieldError
+
+// Error returns all field errors joined by "; ".
+func (e Errors) Error() string {
+	var ss []string
+	for _, err := range e {
+		ss = append(ss, err.Error())
+	}
+	return strings.Join(ss, "; ")
+}
+
 // Field validates a single variable.
 func Field(tag reflect.StructTag, i interface{}) error {
 	for name, v := range validators {
@@ -49,6 +80,111 @@ func Field(tag reflect.StructTag, i interface{}) error {
 	return nil
 }
 
+// Struct validates all exported fields of a struct or a struct pointer,
+// nested structs and elements of slices are validated recursively. It
+// doesn't stop at the first failure, but returns all failures as Errors.
+func Struct(i interface{}) error {
+	var errs Errors
+	validateStruct(reflect.ValueOf(i), "", make(map[uintptr]bool), &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateStruct validates a struct or a struct pointer, visiting records
+// the pointers on the current path, so that a pointer referring back to one
+// of its ancestors is not validated again.
+func validateStruct(v reflect.Value, path string, visiting map[uintptr]bool, errs *Errors) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		p := v.Pointer()
+		if visiting[p] {
+			return
+		}
+		visiting[p] = true
+		defer delete(visiting, p)
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	t := v.Type()
+	for j := 0; j < t.NumField(); j++ {
+		ft := t.Field(j)
+		if ft.PkgPath != "" {
+			continue
+		}
+		fieldPath := ft.Name
+		if path != "" {
+			fieldPath = path + "." + ft.Name
+		}
+		fv := v.Field(j)
+		validateField(ft.Tag, fv.Interface(), fieldPath, errs)
+		validateValue(fv, fieldPath, visiting, errs)
+	}
+}
+
+func validateValue(v reflect.Value, path string, visiting map[uintptr]bool, errs *Errors) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Struct:
+		validateStruct(v, path, visiting, errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), visiting, errs)
+		}
+	}
+}
+
+// validateField runs all validators of the field in name order, so that
+// the order of the errors is stable.
+func validateField(tag reflect.StructTag, i interface{}, path string, errs *Errors) {
+	var names []string
+	for name := range validators {
+		if _, ok := tag.Lookup(name); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := tag.Get(name)
+		err := validators[name].Field(s, i)
+		if err == nil {
+			continue
+		}
+		switch e := err.(type) {
+		case *FieldError:
+			*errs = append(*errs, withPath(e, name, s, i, path))
+		case Errors:
+			for _, fe := range e {
+				*errs = append(*errs, withPath(fe, name, s, i, path))
+			}
+		default:
+			*errs = append(*errs, &FieldError{
+				Field:   path,
+				Rule:    name,
+				Param:   s,
+				Value:   i,
+				Message: err.Error(),
+			})
+		}
+	}
+}
+
+func withPath(e *FieldError, rule, param string, i interface{}, path string) *FieldError {
+	r := *e
+	r.Field = path
+	if r.Rule == "" {
+		r.Rule, r.Param = rule, param
+	}
+	if r.Value == nil {
+		r.Value = i
+	}
+	return &r
+}
+
 type exprValidator struct{}
 
 // Field validates a single variable.
@@ -58,7 +194,12 @@ func (d exprValidator) Field(tag string, i interface{}) error {
 		return err
 	}
 	if !ok {
-		return fmt.Errorf("validate failed on %q for value %v", tag, i)
+		return &FieldError{
+			Rule:    "expr",
+			Param:   tag,
+			Value:   i,
+			Message: fmt.Sprintf("validate failed on %q", tag),
+		}
 	}
 	return nil
 }
@@ -53,8 +53,63 @@ func TestField(t *testing.T) {
 	assert.Nil(t, err)
 
 	err = validate.Field("expr:\"$<3\"", i)
-	assert.Error(t, err, "validate failed on \"\\$<3\" for value 6")
+	assert.Error(t, err, "validate failed on \"\\$<3\"")
 
 	err = validate.Field("expr:\"$<3\"", "abc")
 	assert.Error(t, err, "invalid operation\\: string \\< int \\(1:2\\)")
 }
+
+type Address struct {
+	City string `expr:"len($)>0"`
+}
+
+type User struct {
+	Name      string `expr:"len($)>=3"`
+	Age       int    `expr:"$>=18" empty:""`
+	Address   Address
+	Addresses []*Address
+	Backup    *Address
+	internal  int
+}
+
+func TestStruct(t *testing.T) {
+
+	err := validate.Struct(&User{
+		Name:      "jim",
+		Age:       18,
+		Address:   Address{City: "beijing"},
+		Addresses: []*Address{{City: "shanghai"}},
+	})
+	assert.Nil(t, err)
+
+	err = validate.Struct(&User{
+		Name:      "jo",
+		Age:       3,
+		Addresses: []*Address{{City: "shanghai"}, {}},
+		Backup:    &Address{},
+	})
+	errs, ok := err.(validate.Errors)
+	assert.True(t, ok)
+	assert.Equal(t, errs, validate.Errors{
+		{Field: "Name", Rule: "expr", Param: "len($)>=3", Value: "jo", Message: "validate failed on \"len($)>=3\""},
+		{Field: "Age", Rule: "expr", Param: "$>=18", Value: 3, Message: "validate failed on \"$>=18\""},
+		{Field: "Address.City", Rule: "expr", Param: "len($)>0", Value: "", Message: "validate failed on \"len($)>0\""},
+		{Field: "Addresses[1].City", Rule: "expr", Param: "len($)>0", Value: "", Message: "validate failed on \"len($)>0\""},
+		{Field: "Backup.City", Rule: "expr", Param: "len($)>0", Value: "", Message: "validate failed on \"len($)>0\""},
+	})
+	assert.Error(t, err, "Name: validate failed on \"len\\(\\$\\)>=3\"; Age: .*")
+}
+
+type Node struct {
+	Name   string `expr:"len($)>0"`
+	Parent *Node
+	Nodes  []*Node
+}
+
+func TestStructSelfReference(t *testing.T) {
+	n := &Node{Name: "root"}
+	n.Parent = n
+	n.Nodes = []*Node{n, {Parent: n}}
+	err := validate.Struct(n)
+	assert.Error(t, err, "^Nodes\\[1\\].Name: validate failed on .*$")
+}
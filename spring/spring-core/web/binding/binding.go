@@ -18,11 +18,17 @@
 package binding
 
 import (
+	"errors"
 	"fmt"
+	"mime/multipart"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strconv"
+	"time"
 
+	"github.com/go-spring/spring-base/cast"
+	"github.com/go-spring/spring-base/util"
 	"github.com/go-spring/spring-core/validate"
 )
 
@@ -39,9 +45,12 @@ const (
 type Request interface {
 	ContentType() string
 	Header(key string) string
+	Cookie(name string) (*http.Cookie, error)
 	PathParam(name string) string
 	QueryParam(name string) string
+	QueryParams() url.Values
 	FormParams() (url.Values, error)
+	MultipartForm() (*multipart.Form, error)
 	RequestBody() ([]byte, error)
 }
 
@@ -51,6 +60,7 @@ const (
 	BindScopeURI BindScope = iota
 	BindScopeQuery
 	BindScopeHeader
+	BindScopeCookie
 	BindScopeBody
 )
 
@@ -58,12 +68,34 @@ var scopeBinders = map[BindScope][]string{
 	BindScopeURI:    {"uri", "path"},
 	BindScopeQuery:  {"query", "param"},
 	BindScopeHeader: {"header"},
+	BindScopeCookie: {"cookie"},
 }
 
-var scopeGetters = map[BindScope]func(r Request, name string) string{
-	BindScopeURI:    Request.PathParam,
-	BindScopeQuery:  Request.QueryParam,
-	BindScopeHeader: Request.Header,
+// scopeGetters 返回参数的所有取值，参数不存在时返回 nil 。
+var scopeGetters = map[BindScope]func(r Request, name string) []string{
+	BindScopeURI: func(r Request, name string) []string {
+		return nonEmpty(r.PathParam(name))
+	},
+	BindScopeQuery: func(r Request, name string) []string {
+		return r.QueryParams()[name]
+	},
+	BindScopeHeader: func(r Request, name string) []string {
+		return nonEmpty(r.Header(name))
+	},
+	BindScopeCookie: func(r Request, name string) []string {
+		c, err := r.Cookie(name)
+		if err != nil {
+			return nil
+		}
+		return []string{c.Value}
+	},
+}
+
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
 }
 
 type BodyBinder func(i interface{}, r Request) error
@@ -91,6 +123,36 @@ func RegisterBodyBinder(mime string, binder BodyBinder) {
 	bodyBinders[mime] = binder
 }
 
+var converters = map[reflect.Type]util.Converter{}
+
+func init() {
+
+	// converts string into time.Time, RFC3339 is preferred, otherwise
+	// the default time format of cast.ToTimeE is used.
+	RegisterConverter(func(s string) (time.Time, error) {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			return t, nil
+		}
+		return cast.ToTimeE(s)
+	})
+
+	// converts string into time.Duration. The string should have its own
+	// time unit such as "ns", "ms", "s", "m", etc.
+	RegisterConverter(func(s string) (time.Duration, error) {
+		return cast.ToDurationE(s)
+	})
+}
+
+// RegisterConverter registers its converter for non-primitive type such as
+// time.Time, time.Duration, or other user-defined value type.
+func RegisterConverter(fn util.Converter) {
+	t := reflect.TypeOf(fn)
+	if !util.IsConverter(t) {
+		panic(errors.New("converter is func(string)(type,error)"))
+	}
+	converters[t.Out(0)] = fn
+}
+
 func Bind(i interface{}, r Request) error {
 	if err := bindScope(i, r); err != nil {
 		return err
@@ -119,37 +181,165 @@ func bindScope(i interface{}, r Request) error {
 		return nil
 	}
 	ev := reflect.ValueOf(i).Elem()
-	for j := 0; j < ev.NumField(); j++ {
-		fv := ev.Field(j)
-		ft := et.Field(j)
+	path := map[reflect.Type]bool{et: true}
+	_, err := bindScopeStruct(ev, et, r, path)
+	return err
+}
+
+// bindScopeStruct 绑定结构体的字段，没有绑定标签的嵌套结构体会被递归绑定，
+// path 记录正在绑定的结构体类型，返回值表示是否有字段被成功绑定。
+func bindScopeStruct(v reflect.Value, t reflect.Type, r Request, path map[reflect.Type]bool) (bool, error) {
+	bound := false
+	for j := 0; j < t.NumField(); j++ {
+		ft := t.Field(j)
+		fv := v.Field(j)
+		if isNestedStruct(ft.Type) && !hasScopeTag(ft) {
+			ok, err := bindNestedStruct(fv, ft, path, false, func(v reflect.Value, t reflect.Type) (bool, error) {
+				return bindScopeStruct(v, t, r, path)
+			})
+			if err != nil {
+				return false, err
+			}
+			bound = bound || ok
+			continue
+		}
+		if !fv.CanSet() {
+			continue
+		}
 		for scope := BindScopeURI; scope < BindScopeBody; scope++ {
-			err := bindScopeField(scope, fv, ft, r)
+			ok, err := bindScopeField(scope, fv, ft, r)
 			if err != nil {
-				return err
+				return false, err
+			}
+			bound = bound || ok
+		}
+	}
+	return bound, nil
+}
+
+func hasScopeTag(field reflect.StructField) bool {
+	for _, tags := range scopeBinders {
+		for _, tag := range tags {
+			if _, ok := field.Tag.Lookup(tag); ok {
+				return true
 			}
 		}
 	}
-	return nil
+	return false
 }
 
-func bindScopeField(scope BindScope, v reflect.Value, field reflect.StructField, r Request) error {
+// isNestedStruct 返回是否是需要递归绑定的结构体或者结构体指针类型。
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if _, ok := converters[t]; ok {
+		return false
+	}
+	return t != fileHeaderType.Elem()
+}
+
+// bindNestedStruct 递归绑定嵌套结构体，结构体指针只有在有字段被绑定时才会被赋值。
+// 已经在 path 上的结构体类型不再递归绑定，避免自引用的结构体导致栈溢出，除非 cyclic
+// 为 true ，此时由调用方保证递归可以终止，例如参数名前缀变长并且仍有参数匹配该前缀。
+func bindNestedStruct(v reflect.Value, field reflect.StructField, path map[reflect.Type]bool, cyclic bool, fn func(reflect.Value, reflect.Type) (bool, error)) (bool, error) {
+	et := field.Type
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if path[et] && !cyclic {
+		return false, nil
+	}
+	if !path[et] {
+		path[et] = true
+		defer delete(path, et)
+	}
+	if field.Type.Kind() != reflect.Ptr {
+		return fn(v, field.Type)
+	}
+	if !v.CanSet() {
+		return false, nil
+	}
+	ev := reflect.New(field.Type.Elem())
+	if !v.IsNil() {
+		ev = v
+	}
+	ok, err := fn(ev.Elem(), field.Type.Elem())
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		v.Set(ev)
+	}
+	return ok, nil
+}
+
+func bindScopeField(scope BindScope, v reflect.Value, field reflect.StructField, r Request) (bool, error) {
+	bound := false
 	for _, tag := range scopeBinders[scope] {
 		if name, ok := field.Tag.Lookup(tag); ok {
 			if name == "-" {
 				continue
 			}
-			val := scopeGetters[scope](r, name)
-			err := bindData(v, val)
-			if err != nil {
-				return err
+			values := scopeGetters[scope](r, name)
+			if len(values) == 0 {
+				continue
 			}
+			if err := bindValues(v, values); err != nil {
+				return false, err
+			}
+			bound = true
+		}
+	}
+	return bound, nil
+}
+
+// bindValues 绑定一个参数的所有取值，切片和数组按顺序绑定所有取值，其他类
+// 型只绑定第一个取值。
+func bindValues(v reflect.Value, values []string) error {
+	if _, ok := converters[v.Type()]; !ok {
+		switch v.Kind() {
+		case reflect.Slice:
+			slice := reflect.MakeSlice(v.Type(), len(values), len(values))
+			for i, value := range values {
+				if err := bindData(slice.Index(i), value); err != nil {
+					return err
+				}
+			}
+			v.Set(slice)
+			return nil
+		case reflect.Array:
+			for i := 0; i < v.Len() && i < len(values); i++ {
+				if err := bindData(v.Index(i), values[i]); err != nil {
+					return err
+				}
+			}
+			return nil
 		}
 	}
-	return nil
+	return bindData(v, values[0])
 }
 
 func bindData(v reflect.Value, val string) error {
+	if fn, ok := converters[v.Type()]; ok {
+		out := reflect.ValueOf(fn).Call([]reflect.Value{reflect.ValueOf(val)})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return err
+		}
+		v.Set(out[0])
+		return nil
+	}
 	switch v.Kind() {
+	case reflect.Ptr:
+		ev := reflect.New(v.Type().Elem())
+		if err := bindData(ev.Elem(), val); err != nil {
+			return err
+		}
+		v.Set(ev)
+		return nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		u, err := strconv.ParseUint(val, 0, 0)
 		if err != nil {
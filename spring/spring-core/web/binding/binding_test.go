@@ -17,8 +17,12 @@
 package binding_test
 
 import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/go-spring/spring-base/assert"
 	"github.com/go-spring/spring-core/web/binding"
@@ -27,9 +31,11 @@ import (
 type MockRequest struct {
 	contentType string
 	headers     map[string]string
-	queryParams map[string]string
+	cookies     map[string]string
+	queryParams url.Values
 	pathParams  map[string]string
 	formParams  url.Values
+	files       map[string][]*multipart.FileHeader
 	requestBody string
 }
 
@@ -43,8 +49,20 @@ func (r *MockRequest) Header(key string) string {
 	return r.headers[key]
 }
 
+func (r *MockRequest) Cookie(name string) (*http.Cookie, error) {
+	v, ok := r.cookies[name]
+	if !ok {
+		return nil, http.ErrNoCookie
+	}
+	return &http.Cookie{Name: name, Value: v}, nil
+}
+
 func (r *MockRequest) QueryParam(name string) string {
-	return r.queryParams[name]
+	return r.queryParams.Get(name)
+}
+
+func (r *MockRequest) QueryParams() url.Values {
+	return r.queryParams
 }
 
 func (r *MockRequest) PathParam(name string) string {
@@ -55,6 +73,10 @@ func (r *MockRequest) FormParams() (url.Values, error) {
 	return r.formParams, nil
 }
 
+func (r *MockRequest) MultipartForm() (*multipart.Form, error) {
+	return &multipart.Form{Value: r.formParams, File: r.files}, nil
+}
+
 func (r *MockRequest) RequestBody() ([]byte, error) {
 	return []byte(r.requestBody), nil
 }
@@ -73,10 +95,10 @@ func TestScopeBind(t *testing.T) {
 		headers: map[string]string{
 			"e": "6",
 		},
-		queryParams: map[string]string{
-			"c": "3",
-			"d": "4",
-			"e": "5",
+		queryParams: url.Values{
+			"c": {"3"},
+			"d": {"4"},
+			"e": {"5"},
 		},
 		pathParams: map[string]string{
 			"a": "1",
@@ -97,3 +119,122 @@ func TestScopeBind(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, p, expect)
 }
+
+type ScopeBindPage struct {
+	Page int `query:"page"`
+	Size int `query:"size"`
+}
+
+type ScopeBindUser struct {
+	Token string `cookie:"token"`
+}
+
+type ScopeBindExtra struct {
+	Extra string `query:"extra"`
+}
+
+type ScopeBindNestedParam struct {
+	ScopeBindPage
+	User    *ScopeBindUser
+	Missing *ScopeBindExtra
+	IDs     []int         `query:"ids"`
+	Tags    [2]string     `query:"tags"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `header:"X-Timeout"`
+	Limit   *int          `query:"limit"`
+}
+
+func TestScopeBindNested(t *testing.T) {
+
+	ctx := &MockRequest{
+		headers: map[string]string{
+			"X-Timeout": "3s",
+		},
+		cookies: map[string]string{
+			"token": "abc",
+		},
+		queryParams: url.Values{
+			"page":  {"2"},
+			"size":  {"20"},
+			"ids":   {"1", "2", "3"},
+			"tags":  {"a", "b", "c"},
+			"since": {"2022-10-01T08:00:00Z"},
+			"limit": {"5"},
+		},
+	}
+
+	var p ScopeBindNestedParam
+	err := binding.Bind(&p, ctx)
+	assert.Nil(t, err)
+
+	limit := 5
+	expect := ScopeBindNestedParam{
+		ScopeBindPage: ScopeBindPage{Page: 2, Size: 20},
+		User:          &ScopeBindUser{Token: "abc"},
+		IDs:           []int{1, 2, 3},
+		Tags:          [2]string{"a", "b"},
+		Since:         time.Date(2022, 10, 1, 8, 0, 0, 0, time.UTC),
+		Timeout:       3 * time.Second,
+		Limit:         &limit,
+	}
+	assert.Equal(t, p, expect)
+
+	ctx = &MockRequest{
+		queryParams: url.Values{
+			"ids": {"1", "x"},
+		},
+	}
+	err = binding.Bind(&p, ctx)
+	assert.Error(t, err, "strconv.ParseInt: parsing \"x\": invalid syntax")
+}
+
+type Point struct {
+	X, Y int
+}
+
+type ScopeBindConverterParam struct {
+	Point Point `query:"point"`
+}
+
+func TestRegisterConverter(t *testing.T) {
+
+	binding.RegisterConverter(func(s string) (Point, error) {
+		var p Point
+		_, err := fmt.Sscanf(s, "%d,%d", &p.X, &p.Y)
+		return p, err
+	})
+
+	ctx := &MockRequest{
+		queryParams: url.Values{
+			"point": {"1,2"},
+		},
+	}
+
+	var p ScopeBindConverterParam
+	err := binding.Bind(&p, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, p, ScopeBindConverterParam{Point: Point{X: 1, Y: 2}})
+
+	assert.Panic(t, func() {
+		binding.RegisterConverter(func(s string) Point { return Point{} })
+	}, "converter is func\\(string\\)\\(type,error\\)")
+}
+
+type ScopeBindNode struct {
+	Name   string `query:"name"`
+	Parent *ScopeBindNode
+}
+
+func TestScopeBindSelfReference(t *testing.T) {
+
+	ctx := &MockRequest{
+		queryParams: url.Values{
+			"name": {"jim"},
+		},
+	}
+
+	var p ScopeBindNode
+	err := binding.Bind(&p, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, p, ScopeBindNode{Name: "jim"})
+}
@@ -17,15 +17,29 @@
 package binding
 
 import (
+	"mime/multipart"
 	"net/url"
 	"reflect"
+	"strings"
 )
 
+var fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+
 func BindForm(i interface{}, r Request) error {
 	params, err := r.FormParams()
 	if err != nil {
 		return err
 	}
+	var files map[string][]*multipart.FileHeader
+	if strings.HasPrefix(r.ContentType(), MIMEMultipartForm) {
+		form, err := r.MultipartForm()
+		if err != nil {
+			return err
+		}
+		if form != nil {
+			files = form.File
+		}
+	}
 	t := reflect.TypeOf(i)
 	if t.Kind() != reflect.Ptr {
 		return nil
@@ -35,53 +49,84 @@ func BindForm(i interface{}, r Request) error {
 		return nil
 	}
 	ev := reflect.ValueOf(i).Elem()
-	return bindFormStruct(ev, et, params)
+	path := map[reflect.Type]bool{et: true}
+	_, err = bindFormStruct(ev, et, "", params, files, path)
+	return err
 }
 
-func bindFormStruct(v reflect.Value, t reflect.Type, params url.Values) error {
+// bindFormStruct 绑定表单参数，嵌套结构体会被递归绑定，带 form 标签的嵌套结构
+// 体使用 "标签." 作为其字段的参数名前缀，path 记录正在绑定的结构体类型，返回值
+// 表示是否有字段被成功绑定。
+func bindFormStruct(v reflect.Value, t reflect.Type, prefix string, params url.Values, files map[string][]*multipart.FileHeader, path map[reflect.Type]bool) (bool, error) {
+	bound := false
 	for j := 0; j < t.NumField(); j++ {
 		ft := t.Field(j)
 		fv := v.Field(j)
-		if ft.Anonymous {
-			if ft.Type.Kind() != reflect.Struct {
-				continue
+		name, tagged := ft.Tag.Lookup("form")
+		if name == "-" {
+			continue
+		}
+		if isNestedStruct(ft.Type) {
+			nestedPrefix := prefix
+			if tagged {
+				nestedPrefix = prefix + name + "."
 			}
-			err := bindFormStruct(fv, ft.Type, params)
+			// 带标签的自引用结构体使用更长的前缀，只要有参数匹配该前缀就可以继续绑定。
+			cyclic := tagged && hasFormPrefix(nestedPrefix, params, files)
+			ok, err := bindNestedStruct(fv, ft, path, cyclic, func(v reflect.Value, t reflect.Type) (bool, error) {
+				return bindFormStruct(v, t, nestedPrefix, params, files, path)
+			})
 			if err != nil {
-				return err
+				return false, err
 			}
+			bound = bound || ok
 			continue
 		}
-		name, ok := ft.Tag.Lookup("form")
-		if !ok || !fv.CanInterface() {
+		if !tagged || !fv.CanSet() {
 			continue
 		}
-		values := params[name]
-		if len(values) == 0 {
-			continue
-		}
-		err := bindFormField(fv, ft.Type, values)
+		ok, err := bindFormField(fv, prefix+name, params, files)
 		if err != nil {
-			return err
+			return false, err
 		}
+		bound = bound || ok
 	}
-	return nil
+	return bound, nil
 }
 
-func bindFormField(v reflect.Value, t reflect.Type, values []string) error {
-	if v.Kind() == reflect.Slice {
-		slice := reflect.MakeSlice(t, 0, len(values))
-		defer func() { v.Set(slice) }()
-		et := t.Elem()
-		for _, value := range values {
-			ev := reflect.New(et).Elem()
-			err := bindData(ev, value)
-			if err != nil {
-				return err
-			}
-			slice = reflect.Append(slice, ev)
+// hasFormPrefix 返回是否有表单参数或者文件的名称以 prefix 开头。
+func hasFormPrefix(prefix string, params url.Values, files map[string][]*multipart.FileHeader) bool {
+	for name := range params {
+		if strings.HasPrefix(name, prefix) {
+			return true
 		}
-		return nil
 	}
-	return bindData(v, values[0])
+	for name := range files {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func bindFormField(v reflect.Value, name string, params url.Values, files map[string][]*multipart.FileHeader) (bool, error) {
+	switch v.Type() {
+	case fileHeaderType:
+		if fs := files[name]; len(fs) > 0 {
+			v.Set(reflect.ValueOf(fs[0]))
+			return true, nil
+		}
+		return false, nil
+	case reflect.SliceOf(fileHeaderType):
+		if fs := files[name]; len(fs) > 0 {
+			v.Set(reflect.ValueOf(fs))
+			return true, nil
+		}
+		return false, nil
+	}
+	values := params[name]
+	if len(values) == 0 {
+		return false, nil
+	}
+	return true, bindValues(v, values)
 }
@@ -17,6 +17,7 @@
 package binding_test
 
 import (
+	"mime/multipart"
 	"net/url"
 	"testing"
 
@@ -60,3 +61,79 @@ func TestBindForm(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, p, expect)
 }
+
+type FormBindAddress struct {
+	City   string   `form:"city"`
+	Street []string `form:"street"`
+}
+
+type FormBindNestedParam struct {
+	Name    string                  `form:"name"`
+	Address FormBindAddress         `form:"address"`
+	Backup  *FormBindAddress        `form:"backup"`
+	Avatar  *multipart.FileHeader   `form:"avatar"`
+	Photos  []*multipart.FileHeader `form:"photos"`
+}
+
+func TestBindMultipartForm(t *testing.T) {
+
+	avatar := &multipart.FileHeader{Filename: "avatar.png"}
+	photo1 := &multipart.FileHeader{Filename: "photo1.png"}
+	photo2 := &multipart.FileHeader{Filename: "photo2.png"}
+
+	ctx := &MockRequest{
+		contentType: binding.MIMEMultipartForm,
+		formParams: url.Values{
+			"name":           {"jim"},
+			"address.city":   {"beijing"},
+			"address.street": {"a", "b"},
+		},
+		files: map[string][]*multipart.FileHeader{
+			"avatar": {avatar},
+			"photos": {photo1, photo2},
+		},
+	}
+
+	expect := FormBindNestedParam{
+		Name: "jim",
+		Address: FormBindAddress{
+			City:   "beijing",
+			Street: []string{"a", "b"},
+		},
+		Avatar: avatar,
+		Photos: []*multipart.FileHeader{photo1, photo2},
+	}
+
+	var p FormBindNestedParam
+	err := binding.Bind(&p, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, p, expect)
+}
+
+type FormBindNode struct {
+	Name     string        `form:"name"`
+	Parent   *FormBindNode `form:"parent"`
+	Children []FormBindNode
+}
+
+func TestBindFormSelfReference(t *testing.T) {
+
+	ctx := &MockRequest{
+		formParams: url.Values{
+			"name":               {"jim"},
+			"parent.name":        {"tom"},
+			"parent.parent.name": {"sam"},
+		},
+	}
+
+	var p FormBindNode
+	err := binding.BindForm(&p, ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, p, FormBindNode{
+		Name: "jim",
+		Parent: &FormBindNode{
+			Name:   "tom",
+			Parent: &FormBindNode{Name: "sam"},
+		},
+	})
+}
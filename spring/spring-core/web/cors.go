@@ -240,12 +240,12 @@ func applyCors(ctx Context, chain FilterChain, cors *cors) {
 
 	origin := req.Header.Get(HeaderOrigin)
 	if len(origin) == 0 {
-		chain.Next(ctx)
+		chain.Next(ctx, Recursive)
 		return
 	}
 
 	if origin == "http://"+req.Host || origin == "https://"+req.Host {
-		chain.Next(ctx)
+		chain.Next(ctx, Recursive)
 		return
 	}
 
@@ -265,7 +265,7 @@ func applyCors(ctx Context, chain FilterChain, cors *cors) {
 	}
 
 	cors.handleNormal(ctx)
-	chain.Next(ctx)
+	chain.Next(ctx, Recursive)
 }
 
 func normalize(values []string) []string {
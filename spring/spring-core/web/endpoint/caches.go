@@ -0,0 +1,85 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/go-spring/spring-base/cache"
+	"github.com/go-spring/spring-core/web"
+)
+
+// DefaultCachesPath 缓存统计接口的默认地址。
+const DefaultCachesPath = "/caches"
+
+// CacheView 缓存统计接口返回的缓存命名空间信息。
+type CacheView struct {
+	Name            string  `json:"name"`
+	Hits            int64   `json:"hits"`
+	Misses          int64   `json:"misses"`
+	HitRatio        float64 `json:"hitRatio"`
+	Loads           int64   `json:"loads"`
+	LoadErrors      int64   `json:"loadErrors"`
+	TotalLoadTime   int64   `json:"totalLoadTime"`   // 单位为毫秒
+	AverageLoadTime float64 `json:"averageLoadTime"` // 单位为毫秒
+	Evictions       int64   `json:"evictions"`
+	Size            int     `json:"size"`
+}
+
+func newCacheView(n *cache.Namespace) *CacheView {
+	s := n.Stats()
+	return &CacheView{
+		Name:            n.Name(),
+		Hits:            s.Hits,
+		Misses:          s.Misses,
+		HitRatio:        s.HitRatio(),
+		Loads:           s.Loads,
+		LoadErrors:      s.LoadErrors,
+		TotalLoadTime:   s.TotalLoadTime.Milliseconds(),
+		AverageLoadTime: float64(s.AverageLoadTime().Microseconds()) / 1000,
+		Evictions:       s.Evictions,
+		Size:            s.Size,
+	}
+}
+
+// RegisterCaches 在 path 上注册缓存统计接口，path 为空时使用 DefaultCachesPath 。
+//
+//	GET path          返回所有缓存命名空间的统计信息
+//	GET path?name=xxx 返回名为 xxx 的缓存命名空间的统计信息
+func RegisterCaches(r web.Router, path string) {
+	if path == "" {
+		path = DefaultCachesPath
+	}
+	r.GetMapping(path, getCaches)
+}
+
+func getCaches(ctx web.Context) {
+	if name := ctx.QueryParam("name"); name != "" {
+		n, ok := cache.GetNamespace(name)
+		if !ok {
+			panic(web.NewHttpError(http.StatusNotFound, "cache namespace "+name+" not found"))
+		}
+		ctx.JSON(newCacheView(n))
+		return
+	}
+	namespaces := cache.Namespaces()
+	ret := make([]*CacheView, 0, len(namespaces))
+	for _, n := range namespaces {
+		ret = append(ret, newCacheView(n))
+	}
+	ctx.JSON(ret)
+}
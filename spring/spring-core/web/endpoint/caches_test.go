@@ -0,0 +1,80 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package endpoint_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/cache"
+	"github.com/go-spring/spring-core/web"
+	"github.com/go-spring/spring-core/web/endpoint"
+)
+
+func TestRegisterCaches(t *testing.T) {
+
+	n, err := cache.NewNamespace("endpoint/test", cache.NewStorage(1, cache.SimpleHash))
+	assert.Nil(t, err)
+	defer cache.RemoveNamespace("endpoint/test")
+
+	loader := func(ctx context.Context, key string) (interface{}, error) {
+		return key, nil
+	}
+	for i := 0; i < 4; i++ {
+		_, _, err = n.Load(context.Background(), "a", loader)
+		assert.Nil(t, err)
+	}
+
+	r := web.NewRouter()
+	endpoint.RegisterCaches(r, "")
+	assert.Equal(t, len(r.Mappers()), 1)
+	assert.Equal(t, r.Mappers()[0].Path(), endpoint.DefaultCachesPath)
+
+	_, err = invoke(r, web.MethodGet, "/caches?name=unknown")
+	assert.Error(t, err, "code=404, message=cache namespace unknown not found")
+
+	expect := &endpoint.CacheView{
+		Name:     "endpoint/test",
+		Hits:     3,
+		Misses:   1,
+		HitRatio: 0.75,
+		Loads:    1,
+		Size:     1,
+	}
+
+	resp, err := invoke(r, web.MethodGet, "/caches?name=endpoint/test")
+	assert.Nil(t, err)
+	assert.Equal(t, resp.Code, http.StatusOK)
+	var v *endpoint.CacheView
+	err = json.Unmarshal(resp.Body.Bytes(), &v)
+	assert.Nil(t, err)
+	v.TotalLoadTime, v.AverageLoadTime = 0, 0
+	assert.Equal(t, v, expect)
+
+	resp, err = invoke(r, web.MethodGet, "/caches")
+	assert.Nil(t, err)
+	var caches []*endpoint.CacheView
+	err = json.Unmarshal(resp.Body.Bytes(), &caches)
+	assert.Nil(t, err)
+	assert.Equal(t, len(caches), 2)
+	assert.Equal(t, caches[0].Name, cache.DefaultNamespace)
+	caches[1].TotalLoadTime, caches[1].AverageLoadTime = 0, 0
+	assert.Equal(t, caches[1], expect)
+}
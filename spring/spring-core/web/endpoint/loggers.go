@@ -0,0 +1,117 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package endpoint 提供可以挂载到 web.Router 上的运维接口。
+package endpoint
+
+import (
+	"net/http"
+
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-core/web"
+)
+
+// DefaultLoggersPath 日志级别管理接口的默认地址。
+const DefaultLoggersPath = "/loggers"
+
+// LoggerView 日志级别管理接口返回的 Logger 信息。
+type LoggerView struct {
+	Name            string   `json:"name"`
+	Level           string   `json:"level"`
+	ConfiguredLevel string   `json:"configuredLevel"`
+	Overridden      bool     `json:"overridden"`
+	Appenders       []string `json:"appenders"`
+}
+
+func newLoggerView(info *log.LoggerInfo) *LoggerView {
+	return &LoggerView{
+		Name:            info.Name,
+		Level:           info.Level.String(),
+		ConfiguredLevel: info.ConfiguredLevel.String(),
+		Overridden:      info.Overridden,
+		Appenders:       info.Appenders,
+	}
+}
+
+// RegisterLoggers 在 path 上注册日志级别管理接口，path 为空时使用 DefaultLoggersPath 。
+//
+//	GET    path                    返回所有 Logger 的信息
+//	GET    path?name=xxx           返回名为 xxx 的 Logger 的信息
+//	PUT    path?name=xxx&level=yyy 设置名为 xxx 的 Logger 的日志级别
+//	DELETE path?name=xxx           恢复名为 xxx 的 Logger 在配置文件中的日志级别
+//
+// 根 Logger 的名称为 log.RootLoggerName 。该接口可以修改线上服务的日志级别，
+// 需要配合认证过滤器使用。
+func RegisterLoggers(r web.Router, path string) {
+	if path == "" {
+		path = DefaultLoggersPath
+	}
+	r.GetMapping(path, getLoggers)
+	r.PutMapping(path, setLoggerLevel)
+	r.DeleteMapping(path, resetLoggerLevel)
+}
+
+func getLoggers(ctx web.Context) {
+	if name := ctx.QueryParam("name"); name != "" {
+		writeLogger(ctx, name)
+		return
+	}
+	loggers, err := log.Loggers()
+	if err != nil {
+		panic(web.NewHttpError(http.StatusInternalServerError).SetInternal(err))
+	}
+	ret := make([]*LoggerView, 0, len(loggers))
+	for _, info := range loggers {
+		ret = append(ret, newLoggerView(info))
+	}
+	ctx.JSON(ret)
+}
+
+func setLoggerLevel(ctx web.Context) {
+	name := requireName(ctx)
+	level, err := log.ParseLevel(ctx.QueryParam("level"))
+	if err != nil {
+		panic(web.NewHttpError(http.StatusBadRequest, err.Error()))
+	}
+	if err = log.SetLevel(name, level); err != nil {
+		panic(web.NewHttpError(http.StatusBadRequest, err.Error()))
+	}
+	writeLogger(ctx, name)
+}
+
+func resetLoggerLevel(ctx web.Context) {
+	name := requireName(ctx)
+	if err := log.ResetLevel(name); err != nil {
+		panic(web.NewHttpError(http.StatusInternalServerError).SetInternal(err))
+	}
+	writeLogger(ctx, name)
+}
+
+func requireName(ctx web.Context) string {
+	name := ctx.QueryParam("name")
+	if name == "" {
+		panic(web.NewHttpError(http.StatusBadRequest, "query param 'name' is required"))
+	}
+	return name
+}
+
+func writeLogger(ctx web.Context, name string) {
+	info, err := log.GetLoggerInfo(name)
+	if err != nil {
+		panic(web.NewHttpError(http.StatusInternalServerError).SetInternal(err))
+	}
+	ctx.JSON(newLoggerView(info))
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package endpoint_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/web"
+	"github.com/go-spring/spring-core/web/endpoint"
+)
+
+func init() {
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Console name="Console"/>
+			</Appenders>
+			<Loggers>
+				<Root level="info">
+					<AppenderRef ref="Console"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	util.Panic(err).When(err != nil)
+}
+
+func invoke(r web.Router, method uint32, target string) (resp *httptest.ResponseRecorder, err error) {
+	var h web.Handler
+	for _, m := range r.Mappers() {
+		if m.Method() == method {
+			h = m.Handler()
+		}
+	}
+	req := httptest.NewRequest(web.GetMethod(method)[0], target, nil)
+	resp = httptest.NewRecorder()
+	ctx := web.NewBaseContext("", h, req, &web.SimpleResponse{ResponseWriter: resp})
+	defer func() {
+		if r := recover(); r != nil {
+			err = r.(*web.HttpError)
+		}
+	}()
+	h.Invoke(ctx)
+	return
+}
+
+func TestRegisterLoggers(t *testing.T) {
+
+	r := web.NewRouter()
+	endpoint.RegisterLoggers(r, "")
+	assert.Equal(t, len(r.Mappers()), 3)
+	assert.Equal(t, r.Mappers()[0].Path(), endpoint.DefaultLoggersPath)
+
+	logger := log.GetLogger("endpoint/test")
+	assert.Equal(t, logger.Level(), log.InfoLevel)
+
+	_, err := invoke(r, web.MethodPut, "/loggers?level=debug")
+	assert.Error(t, err, "code=400, message=query param 'name' is required")
+
+	_, err = invoke(r, web.MethodPut, "/loggers?name=endpoint/test&level=xxx")
+	assert.Error(t, err, "code=400, message=invalid level xxx")
+
+	resp, err := invoke(r, web.MethodPut, "/loggers?name=endpoint/test&level=debug")
+	assert.Nil(t, err)
+	assert.Equal(t, resp.Code, http.StatusOK)
+	assert.JsonEqual(t, resp.Body.String(), `{"name":"endpoint/test","level":"DEBUG","configuredLevel":"INFO","overridden":true,"appenders":["Console"]}`)
+	assert.Equal(t, logger.Level(), log.DebugLevel)
+
+	resp, err = invoke(r, web.MethodGet, "/loggers")
+	assert.Nil(t, err)
+	var loggers []*endpoint.LoggerView
+	err = json.Unmarshal(resp.Body.Bytes(), &loggers)
+	assert.Nil(t, err)
+	assert.InSlice(t, &endpoint.LoggerView{
+		Name:            "endpoint/test",
+		Level:           "DEBUG",
+		ConfiguredLevel: "INFO",
+		Overridden:      true,
+		Appenders:       []string{"Console"},
+	}, loggers)
+
+	resp, err = invoke(r, web.MethodDelete, "/loggers?name=endpoint/test")
+	assert.Nil(t, err)
+	assert.JsonEqual(t, resp.Body.String(), `{"name":"endpoint/test","level":"INFO","configuredLevel":"INFO","overridden":false,"appenders":["Console"]}`)
+	assert.Equal(t, logger.Level(), log.InfoLevel)
+}
@@ -0,0 +1,308 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-spring/spring-base/cast"
+)
+
+// Format 获取上下文语言中 key 对应的消息，然后使用 args 进行格式化。
+func Format(ctx context.Context, key string, args ...interface{}) (string, error) {
+	language := Language(ctx)
+	return FormatMessage(language, get(language, key), args...)
+}
+
+// FormatMessage 使用 language 语言的规则格式化 ICU 风格的消息，支持的语法有:
+//
+//	{name}                               引用参数
+//	{name, number[, integer|percent]}    格式化数字
+//	{name, date[, short|medium|long]}    格式化日期
+//	{name, time[, short|medium]}         格式化时间
+//	{name, plural, =0 {..} one {..} other {..}}  复数规则，# 代表数字
+//	{name, select, male {..} other {..}}         选择规则
+//
+// 当 args 只有一个 map[string]interface{} 类型的参数时按名称引用参数，否则按
+// 位置引用参数，如 {0}、{1}。使用单引号可以输出 { 、} 、# 字符，两个单引号
+// 输出一个单引号。
+func FormatMessage(language string, msg string, args ...interface{}) (string, error) {
+	f := &formatter{locale: getLocale(language)}
+	if len(args) == 1 {
+		if m, ok := args[0].(map[string]interface{}); ok {
+			f.arg = func(name string) (interface{}, bool) {
+				v, ok := m[name]
+				return v, ok
+			}
+		}
+	}
+	if f.arg == nil {
+		f.arg = func(name string) (interface{}, bool) {
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= len(args) {
+				return nil, false
+			}
+			return args[i], true
+		}
+	}
+	return f.format(msg, "")
+}
+
+type formatter struct {
+	locale *locale
+	arg    func(name string) (interface{}, bool)
+}
+
+// format 格式化消息，pound 是 plural 分支中 # 的替换值。
+func (f *formatter) format(msg string, pound string) (string, error) {
+	var buf strings.Builder
+	for i := 0; i < len(msg); i++ {
+		switch c := msg[i]; c {
+		case '\'':
+			if i+1 < len(msg) && msg[i+1] == '\'' {
+				buf.WriteByte('\'')
+				i++
+				continue
+			}
+			if i+1 < len(msg) && strings.IndexByte("{}#", msg[i+1]) >= 0 {
+				end := strings.IndexByte(msg[i+1:], '\'')
+				if end < 0 {
+					buf.WriteString(msg[i+1:])
+					return buf.String(), nil
+				}
+				buf.WriteString(msg[i+1 : i+1+end])
+				i += end + 1
+				continue
+			}
+			buf.WriteByte(c)
+		case '{':
+			end, err := matchBrace(msg, i)
+			if err != nil {
+				return "", err
+			}
+			s, err := f.formatArg(msg[i+1:end], pound)
+			if err != nil {
+				return "", err
+			}
+			buf.WriteString(s)
+			i = end
+		case '}':
+			return "", fmt.Errorf("unmatched '}' at %d in %q", i, msg)
+		case '#':
+			if pound != "" {
+				buf.WriteString(pound)
+				continue
+			}
+			buf.WriteByte(c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String(), nil
+}
+
+// matchBrace 返回与 start 处的 { 匹配的 } 的位置。
+func matchBrace(msg string, start int) (int, error) {
+	depth := 0
+	for i := start; i < len(msg); i++ {
+		switch msg[i] {
+		case '\'':
+			if i+1 < len(msg) && strings.IndexByte("{}#", msg[i+1]) >= 0 {
+				if end := strings.IndexByte(msg[i+1:], '\''); end >= 0 {
+					i += end + 1
+				}
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("unmatched '{' at %d in %q", start, msg)
+}
+
+// formatArg 格式化参数，pound 是外层 plural 分支中 # 的替换值，会传递给 select 的分支。
+func (f *formatter) formatArg(s string, pound string) (string, error) {
+	parts := strings.SplitN(s, ",", 3)
+	name := strings.TrimSpace(parts[0])
+	v, ok := f.arg(name)
+	if !ok {
+		return "", fmt.Errorf("argument %q not found", name)
+	}
+	if len(parts) == 1 {
+		return f.formatValue(v), nil
+	}
+	typ := strings.TrimSpace(parts[1])
+	style := ""
+	if len(parts) > 2 {
+		style = strings.TrimSpace(parts[2])
+	}
+	switch typ {
+	case "number":
+		n, err := cast.ToFloat64E(v)
+		if err != nil {
+			return "", err
+		}
+		return f.locale.formatNumber(n, style), nil
+	case "date", "time":
+		t, ok := v.(time.Time)
+		if !ok {
+			return "", fmt.Errorf("argument %q should be time.Time", name)
+		}
+		return f.locale.formatTime(t, typ, style), nil
+	case "plural":
+		n, err := cast.ToFloat64E(v)
+		if err != nil {
+			return "", err
+		}
+		return f.formatPlural(n, style)
+	case "select":
+		return f.formatSelect(fmt.Sprint(v), style, pound)
+	default:
+		return "", fmt.Errorf("unsupported argument type %q", typ)
+	}
+}
+
+func (f *formatter) formatValue(v interface{}) string {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return f.locale.formatNumber(cast.ToFloat64(v), "")
+	case time.Time:
+		return f.locale.formatTime(v.(time.Time), "date", "")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseOptions 解析 plural 和 select 的分支，格式为 key {message} key {message}。
+func parseOptions(s string) (keys []string, messages []string, err error) {
+	for i := 0; i < len(s); {
+		if s[i] == ' ' || s[i] == '\t' || s[i] == '\n' {
+			i++
+			continue
+		}
+		start := strings.IndexByte(s[i:], '{')
+		if start < 0 {
+			return nil, nil, fmt.Errorf("missing message for option %q", s[i:])
+		}
+		key := strings.TrimSpace(s[i : i+start])
+		end, err := matchBrace(s, i+start)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = append(keys, key)
+		messages = append(messages, s[i+start+1:end])
+		i = end + 1
+	}
+	return keys, messages, nil
+}
+
+func (f *formatter) formatPlural(n float64, style string) (string, error) {
+	offset := 0.0
+	if strings.HasPrefix(style, "offset:") {
+		s := strings.TrimPrefix(style, "offset:")
+		end := strings.IndexAny(s, " \t\n")
+		if end < 0 {
+			return "", fmt.Errorf("invalid plural style %q", style)
+		}
+		v, err := strconv.ParseFloat(s[:end], 64)
+		if err != nil {
+			return "", err
+		}
+		offset, style = v, s[end:]
+	}
+	keys, messages, err := parseOptions(style)
+	if err != nil {
+		return "", err
+	}
+	exact := "=" + strconv.FormatFloat(n, 'f', -1, 64)
+	category := f.locale.plural(n - offset)
+	pound := f.locale.formatNumber(n-offset, "")
+	for _, want := range []string{exact, category, "other"} {
+		for i, key := range keys {
+			if key == want {
+				return f.format(messages[i], pound)
+			}
+		}
+	}
+	return "", fmt.Errorf("missing 'other' option in %q", style)
+}
+
+func (f *formatter) formatSelect(v string, style string, pound string) (string, error) {
+	keys, messages, err := parseOptions(style)
+	if err != nil {
+		return "", err
+	}
+	for _, want := range []string{v, "other"} {
+		for i, key := range keys {
+			if key == want {
+				return f.format(messages[i], pound)
+			}
+		}
+	}
+	return "", fmt.Errorf("missing 'other' option in %q", style)
+}
+
+// formatNumber 按照语言的分组符号和小数点格式化数字，小数最多保留三位。
+func (l *locale) formatNumber(n float64, style string) string {
+	switch style {
+	case "integer":
+		n = math.Round(n)
+	case "percent":
+		return l.formatNumber(math.Round(n*100), "") + "%"
+	}
+	s := strconv.FormatFloat(math.Abs(n), 'f', 3, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	var buf strings.Builder
+	if n < 0 && s != "0" {
+		buf.WriteByte('-')
+	}
+	for i := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			buf.WriteString(l.group)
+		}
+		buf.WriteByte(intPart[i])
+	}
+	if fracPart != "" {
+		buf.WriteString(l.decimal)
+		buf.WriteString(fracPart)
+	}
+	return buf.String()
+}
+
+// formatTime 按照语言的日期时间格式格式化时间。
+func (l *locale) formatTime(t time.Time, typ string, style string) string {
+	if style == "" {
+		style = "medium"
+	}
+	if layout, ok := l.layouts[typ+"."+style]; ok {
+		return t.Format(layout)
+	}
+	return t.Format(l.layouts[typ+".medium"])
+}
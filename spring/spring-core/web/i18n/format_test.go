@@ -0,0 +1,110 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package i18n_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-core/web/i18n"
+)
+
+func TestFormatMessage(t *testing.T) {
+
+	testcases := []struct {
+		language string
+		msg      string
+		args     []interface{}
+		expect   string
+	}{
+		{"en", "hello {0}, {1}!", []interface{}{"jim", "tom"}, "hello jim, tom!"},
+		{"en", "hello {name}!", []interface{}{map[string]interface{}{"name": "jim"}}, "hello jim!"},
+		{"en", "it''s '{name}'", nil, "it's {name}"},
+		{"en", "{0}", []interface{}{1234567.891}, "1,234,567.891"},
+		{"de", "{0}", []interface{}{1234567.891}, "1.234.567,891"},
+		{"fr-FR", "{0, number}", []interface{}{-1234.5}, "-1\u202f234,5"},
+		{"en", "{0, number, integer}", []interface{}{1234.5}, "1,235"},
+		{"en", "{0, number, percent}", []interface{}{0.256}, "26%"},
+		{"en", "{0, plural, =0 {no files} one {# file} other {# files}}", []interface{}{0}, "no files"},
+		{"en", "{0, plural, =0 {no files} one {# file} other {# files}}", []interface{}{1}, "1 file"},
+		{"en", "{0, plural, =0 {no files} one {# file} other {# files}}", []interface{}{1200}, "1,200 files"},
+		{"fr", "{0, plural, one {# fichier} other {# fichiers}}", []interface{}{0}, "0 fichier"},
+		{"ru", "{0, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", []interface{}{21}, "21 файл"},
+		{"ru", "{0, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", []interface{}{23}, "23 файла"},
+		{"ru", "{0, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}", []interface{}{11}, "11 файлов"},
+		{"zh", "{0, plural, one {# 个文件} other {# 个文件}}", []interface{}{1}, "1 个文件"},
+		{"en", "{0, plural, offset:1 =0 {nobody} =1 {{1}} other {{1} and # others}}", []interface{}{3, "jim"}, "jim and 2 others"},
+		{"en", "{0, select, male {He} female {She} other {They}} liked it", []interface{}{"female"}, "She liked it"},
+		{"en", "{0, select, male {He} female {She} other {They}} liked it", []interface{}{"unknown"}, "They liked it"},
+		{"en", "{0, plural, one {{1, select, male {he has # file} other {they have # file}}} other {{1, select, male {he has # files} other {they have # files}}}}", []interface{}{3, "male"}, "he has 3 files"},
+	}
+
+	for _, c := range testcases {
+		s, err := i18n.FormatMessage(c.language, c.msg, c.args...)
+		assert.Nil(t, err)
+		assert.Equal(t, s, c.expect)
+	}
+
+	date := time.Date(2022, 10, 1, 14, 5, 6, 0, time.UTC)
+	dateCases := []struct {
+		language string
+		msg      string
+		expect   string
+	}{
+		{"en-US", "{0, date}", "Oct 1, 2022"},
+		{"en-US", "{0, date, long}", "October 1, 2022"},
+		{"en-US", "{0, time, short}", "2:05 PM"},
+		{"zh-CN", "{0, date}", "2022年10月1日"},
+		{"de", "{0, date, short}", "01.10.22"},
+		{"xx", "{0, date} {0, time}", "2022-10-01 14:05:06"},
+	}
+
+	for _, c := range dateCases {
+		s, err := i18n.FormatMessage(c.language, c.msg, date)
+		assert.Nil(t, err)
+		assert.Equal(t, s, c.expect)
+	}
+
+	_, err := i18n.FormatMessage("en", "{0", 1)
+	assert.Error(t, err, "unmatched '{' at 0")
+
+	_, err = i18n.FormatMessage("en", "{1}", 1)
+	assert.Error(t, err, "argument \"1\" not found")
+
+	_, err = i18n.FormatMessage("en", "{0, plural, one {x}}", 2)
+	assert.Error(t, err, "missing 'other' option")
+}
+
+func TestFormat(t *testing.T) {
+	ctx, _ := knife.New(context.Background())
+	err := i18n.SetLanguage(ctx, "en-US")
+	assert.Nil(t, err)
+	s, err := i18n.Format(ctx, "message")
+	assert.Nil(t, err)
+	assert.Equal(t, s, "this is a message")
+}
+
+func TestMatch(t *testing.T) {
+	assert.Equal(t, i18n.Languages(), []string{"en", "en-US", "zh", "zh-CN"})
+	assert.Equal(t, i18n.Match("fr", "en-us"), "en-US")
+	assert.Equal(t, i18n.Match("en-GB"), "en")
+	assert.Equal(t, i18n.Match("zh-TW"), "zh")
+	assert.Equal(t, i18n.Match("fr"), "")
+}
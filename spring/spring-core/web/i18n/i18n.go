@@ -21,6 +21,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/go-spring/spring-base/code"
@@ -338,22 +339,64 @@ func SetDefaultLanguage(language string) {
 	defaultLanguage = language
 }
 
-// SetLanguage 设置上下文语言。
+// SetLanguage 设置上下文语言，已经设置过时覆盖原来的语言。
 func SetLanguage(ctx context.Context, language string) error {
-	return knife.Store(ctx, languageKey, language)
+	return knife.Set(ctx, languageKey, language)
 }
 
-// Get 获取语言对应的配置项，从 context.Context 中获取上下文语言。
-func Get(ctx context.Context, key string) string {
-
-	language := defaultLanguage
+// Language 返回上下文语言，未设置时返回默认语言。
+func Language(ctx context.Context) string {
 	v, err := knife.Load(ctx, languageKey)
 	if err == nil {
-		str, ok := v.(string)
-		if ok {
-			language = str
+		if str, ok := v.(string); ok {
+			return str
+		}
+	}
+	return defaultLanguage
+}
+
+// Languages 返回所有已注册的语言。
+func Languages() []string {
+	var ret []string
+	for language := range languageMap {
+		ret = append(ret, language)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Match 按照优先级顺序返回第一个能够匹配已注册语言的语言，匹配顺序依次是完
+// 全匹配、去掉地区后匹配 (zh-CN 匹配 zh) 、相同语种匹配 (zh 匹配 zh-CN)，
+// 语言代码不区分大小写，没有能够匹配的语言时返回空字符串。
+func Match(languages ...string) string {
+	registered := Languages()
+	for _, language := range languages {
+		for _, s := range registered {
+			if strings.EqualFold(s, language) {
+				return s
+			}
+		}
+		base := strings.SplitN(language, "-", 2)[0]
+		for _, s := range registered {
+			if strings.EqualFold(s, base) {
+				return s
+			}
+		}
+		for _, s := range registered {
+			if strings.EqualFold(strings.SplitN(s, "-", 2)[0], base) {
+				return s
+			}
 		}
 	}
+	return ""
+}
+
+// Get 获取语言对应的配置项，从 context.Context 中获取上下文语言。
+func Get(ctx context.Context, key string) string {
+	return get(Language(ctx), key)
+}
+
+func get(language string, key string) string {
 
 	if m, ok := languageMap[language]; ok && m != nil {
 		if m.Has(key) {
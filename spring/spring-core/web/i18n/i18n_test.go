@@ -29,16 +29,16 @@ import (
 
 func init() {
 
-	p, err := conf.Map(map[string]interface{}{
+	p := conf.Map(map[string]interface{}{
 		"message": "这是一条消息",
 	})
-	err = i18n.Register("zh-CN", p)
+	err := i18n.Register("zh-CN", p)
 	util.Panic(err).When(err != nil)
 
 	err = i18n.LoadLanguage("testdata/zh.properties")
 	util.Panic(err).When(err != nil)
 
-	p, err = conf.Map(map[string]interface{}{
+	p = conf.Map(map[string]interface{}{
 		"message": "this is a message",
 	})
 	err = i18n.Register("en-US", p)
@@ -58,6 +58,10 @@ func TestGet(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, i18n.Get(ctx, "message"), "this is a message")
 
+	err = i18n.SetLanguage(ctx, "zh-CN")
+	assert.Nil(t, err)
+	assert.Equal(t, i18n.Get(ctx, "message"), "这是一条消息")
+
 	ctx, _ = knife.New(context.Background())
 	err = i18n.SetLanguage(ctx, "en")
 	assert.Nil(t, err)
@@ -0,0 +1,217 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package i18n
+
+import (
+	"math"
+	"strings"
+)
+
+// PluralRule 返回数字 n 在某种语言中的复数类别，如 one、few、many、other 。
+type PluralRule func(n float64) string
+
+// locale 语言相关的格式化规则。
+type locale struct {
+	group   string            // 数字分组符号
+	decimal string            // 小数点符号
+	plural  PluralRule        // 复数规则
+	layouts map[string]string // 日期时间格式
+}
+
+var isoLayouts = map[string]string{
+	"date.short":  "2006-01-02",
+	"date.medium": "2006-01-02",
+	"date.long":   "2006-01-02",
+	"time.short":  "15:04",
+	"time.medium": "15:04:05",
+}
+
+var defaultLocale = &locale{
+	group:   ",",
+	decimal: ".",
+	plural:  pluralOneOther,
+	layouts: isoLayouts,
+}
+
+var locales = map[string]*locale{
+	"en": {
+		group:   ",",
+		decimal: ".",
+		plural:  pluralOneOther,
+		layouts: map[string]string{
+			"date.short":  "1/2/06",
+			"date.medium": "Jan 2, 2006",
+			"date.long":   "January 2, 2006",
+			"time.short":  "3:04 PM",
+			"time.medium": "3:04:05 PM",
+		},
+	},
+	"zh": {
+		group:   ",",
+		decimal: ".",
+		plural:  pluralOther,
+		layouts: map[string]string{
+			"date.short":  "2006/1/2",
+			"date.medium": "2006年1月2日",
+			"date.long":   "2006年1月2日",
+			"time.short":  "15:04",
+			"time.medium": "15:04:05",
+		},
+	},
+	"ja": {
+		group:   ",",
+		decimal: ".",
+		plural:  pluralOther,
+		layouts: map[string]string{
+			"date.short":  "2006/01/02",
+			"date.medium": "2006/01/02",
+			"date.long":   "2006年1月2日",
+			"time.short":  "15:04",
+			"time.medium": "15:04:05",
+		},
+	},
+	"ko": {group: ",", decimal: ".", plural: pluralOther, layouts: isoLayouts},
+	"de": {
+		group:   ".",
+		decimal: ",",
+		plural:  pluralOneOther,
+		layouts: map[string]string{
+			"date.short":  "02.01.06",
+			"date.medium": "02.01.2006",
+			"date.long":   "02.01.2006",
+			"time.short":  "15:04",
+			"time.medium": "15:04:05",
+		},
+	},
+	"fr": {
+		group:   "\u202f",
+		decimal: ",",
+		plural:  pluralFrench,
+		layouts: map[string]string{
+			"date.short":  "02/01/2006",
+			"date.medium": "02/01/2006",
+			"date.long":   "02/01/2006",
+			"time.short":  "15:04",
+			"time.medium": "15:04:05",
+		},
+	},
+	"es": {group: ".", decimal: ",", plural: pluralOneOther, layouts: isoLayouts},
+	"it": {group: ".", decimal: ",", plural: pluralOneOther, layouts: isoLayouts},
+	"pt": {group: ".", decimal: ",", plural: pluralFrench, layouts: isoLayouts},
+	"ru": {group: "\u00a0", decimal: ",", plural: pluralSlavic, layouts: isoLayouts},
+	"uk": {group: "\u00a0", decimal: ",", plural: pluralSlavic, layouts: isoLayouts},
+	"pl": {group: "\u00a0", decimal: ",", plural: pluralPolish, layouts: isoLayouts},
+	"cs": {group: "\u00a0", decimal: ",", plural: pluralCzech, layouts: isoLayouts},
+}
+
+// RegisterLocale 注册或者覆盖某种语言的数字格式和复数规则，layouts 的 key
+// 形如 date.short、time.medium ，值为 time.Format 使用的格式。
+func RegisterLocale(language string, group, decimal string, plural PluralRule, layouts map[string]string) {
+	if layouts == nil {
+		layouts = isoLayouts
+	}
+	locales[language] = &locale{
+		group:   group,
+		decimal: decimal,
+		plural:  plural,
+		layouts: layouts,
+	}
+}
+
+// getLocale 返回语言的格式化规则，找不到时依次尝试去掉地区的语言和默认规则。
+func getLocale(language string) *locale {
+	if l, ok := locales[language]; ok {
+		return l
+	}
+	base := strings.SplitN(language, "-", 2)[0]
+	if l, ok := locales[base]; ok {
+		return l
+	}
+	return defaultLocale
+}
+
+func isInt(n float64) bool {
+	return n == math.Trunc(n)
+}
+
+// pluralOther 没有复数变化的语言，如中文、日文。
+func pluralOther(n float64) string {
+	return "other"
+}
+
+// pluralOneOther 1 为单数的语言，如英语、德语。
+func pluralOneOther(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralFrench 0 和 1 为单数的语言，如法语、葡萄牙语。
+func pluralFrench(n float64) string {
+	if n >= 0 && n < 2 && isInt(n) {
+		return "one"
+	}
+	return "other"
+}
+
+// pluralSlavic 俄语、乌克兰语的复数规则。
+func pluralSlavic(n float64) string {
+	if !isInt(n) {
+		return "other"
+	}
+	i := int64(math.Abs(n))
+	switch {
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// pluralPolish 波兰语的复数规则。
+func pluralPolish(n float64) string {
+	if !isInt(n) {
+		return "other"
+	}
+	i := int64(math.Abs(n))
+	switch {
+	case i == 1:
+		return "one"
+	case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+		return "few"
+	default:
+		return "many"
+	}
+}
+
+// pluralCzech 捷克语、斯洛伐克语的复数规则。
+func pluralCzech(n float64) string {
+	if !isInt(n) {
+		return "many"
+	}
+	switch i := int64(math.Abs(n)); {
+	case i == 1:
+		return "one"
+	case i >= 2 && i <= 4:
+		return "few"
+	default:
+		return "other"
+	}
+}
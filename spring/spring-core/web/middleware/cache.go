@@ -0,0 +1,304 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-spring/spring-base/cache"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-core/redis"
+	"github.com/go-spring/spring-core/web"
+)
+
+// CachedResponse 缓存的响应，Status 为 0 时表示这是一个索引项，Header 中保存
+// 响应的 Vary 头，真正的响应按照 Vary 指定的请求头另外缓存。
+type CachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// ResponseCache 响应缓存的存储接口，Get 在缓存不存在时返回 nil 。
+type ResponseCache interface {
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+	Set(ctx context.Context, key string, resp *CachedResponse, ttl time.Duration) error
+}
+
+type memoryCacheItem struct {
+	resp     *CachedResponse
+	expireAt time.Time
+}
+
+// Sweepable 过期的缓存可以被清理。
+func (e *memoryCacheItem) Sweepable() bool {
+	return time.Now().After(e.expireAt)
+}
+
+// memoryResponseSweepInterval 清理过期缓存的时间间隔。
+const memoryResponseSweepInterval = time.Minute
+
+// memoryResponseCache 基于 spring-base/cache 分片存储的内存缓存，缓存数量超过
+// 上限时按照 LRU 淘汰，过期的缓存在写入时定期清理。
+type memoryResponseCache struct {
+	storage   *cache.Storage
+	lastSweep int64 // 上次清理的时间，单位纳秒
+}
+
+// NewMemoryResponseCache 返回内存中的响应缓存，shards 为分片数量，maxSize 为
+// 缓存数量的上限，小于等于 0 时不限制数量。
+func NewMemoryResponseCache(shards int, maxSize int) ResponseCache {
+	if shards <= 0 {
+		shards = 1
+	}
+	storage := cache.NewStorage(shards, cache.SimpleHash, cache.MaxSize(maxSize))
+	return &memoryResponseCache{storage: storage, lastSweep: time.Now().UnixNano()}
+}
+
+func (c *memoryResponseCache) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	m := c.storage.Sharding(key)
+	v, ok := m.Load(key)
+	if !ok {
+		return nil, nil
+	}
+	item := v.(*memoryCacheItem)
+	if time.Now().After(item.expireAt) {
+		m.Delete(key)
+		return nil, nil
+	}
+	return item.resp, nil
+}
+
+func (c *memoryResponseCache) Set(ctx context.Context, key string, resp *CachedResponse, ttl time.Duration) error {
+	now := time.Now()
+	item := &memoryCacheItem{resp: resp, expireAt: now.Add(ttl)}
+	c.storage.Sharding(key).Store(key, item)
+	last := atomic.LoadInt64(&c.lastSweep)
+	if now.UnixNano()-last >= int64(memoryResponseSweepInterval) &&
+		atomic.CompareAndSwapInt64(&c.lastSweep, last, now.UnixNano()) {
+		c.storage.Sweep()
+	}
+	return nil
+}
+
+// redisResponseCache 基于 redis 的响应缓存，响应使用 JSON 格式保存。
+type redisResponseCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisResponseCache 返回基于 redis 的响应缓存，prefix 为 key 的前缀。
+func NewRedisResponseCache(client *redis.Client, prefix string) ResponseCache {
+	return &redisResponseCache{client: client, prefix: prefix}
+}
+
+func (c *redisResponseCache) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	s, err := c.client.Get(ctx, c.prefix+key)
+	if err != nil {
+		if redis.IsErrNil(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if s == "" {
+		return nil, nil
+	}
+	resp := new(CachedResponse)
+	if err = json.Unmarshal([]byte(s), resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *redisResponseCache) Set(ctx context.Context, key string, resp *CachedResponse, ttl time.Duration) error {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.Set(ctx, c.prefix+key, string(b), "PX", ttl.Milliseconds())
+	return err
+}
+
+type ResponseCacheConfig struct {
+	Cache   ResponseCache // 缓存的存储
+	TTL     time.Duration // 路由未设置缓存时间时使用的缓存时间，为 0 时不缓存
+	Headers []string      // 除 Accept 之外参与计算缓存 key 的请求头
+}
+
+// defaultResponseCacheSize 默认内存缓存的数量上限。
+const defaultResponseCacheSize = 10000
+
+func NewResponseCacheConfig() ResponseCacheConfig {
+	return ResponseCacheConfig{}
+}
+
+// NewResponseCacheFilter 缓存 GET 和 HEAD 请求的 200 响应，缓存 key 由请求方
+// 法、路径、查询参数、Accept 头和指定的请求头组成，响应包含 Vary 头时还会加上
+// Vary 指定的请求头，缓存时间优先使用 Mapper.Cache 设置的值。响应包含
+// Set-Cookie、Vary: * 或者 Cache-Control 为 no-store、private 时不缓存。
+func NewResponseCacheFilter(config ResponseCacheConfig) web.Filter {
+	if config.Cache == nil {
+		config.Cache = NewMemoryResponseCache(16, defaultResponseCacheSize)
+	}
+	headers := []string{web.HeaderAccept}
+	for _, h := range config.Headers {
+		if http.CanonicalHeaderKey(h) != web.HeaderAccept {
+			headers = append(headers, h)
+		}
+	}
+	return web.FuncFilter(func(ctx web.Context, chain web.FilterChain) {
+
+		ttl := web.CacheTTL(ctx)
+		if ttl <= 0 {
+			ttl = config.TTL
+		}
+
+		r := ctx.Request()
+		if ttl <= 0 || !isCacheableMethod(r.Method) || hasCacheDirective(r.Header, "no-store") {
+			chain.Next(ctx, web.Iterative)
+			return
+		}
+
+		key := responseCacheKey(r, headers)
+
+		resp, err := config.Cache.Get(ctx.Context(), key)
+		if err == nil && resp != nil && resp.Status == 0 {
+			resp, err = config.Cache.Get(ctx.Context(), varyCacheKey(key, r, resp.Header))
+		}
+		if err != nil {
+			log.GetLogger("web.ResponseCacheFilter").WithContext(ctx.Context()).Errorf("get response cache %q error: %v", key, err)
+		}
+		if resp != nil {
+			header := ctx.Response().Header()
+			for k, v := range resp.Header {
+				header[k] = v
+			}
+			writeResponse(ctx, resp.Status, resp.Body)
+			return
+		}
+
+		before := ctx.Response().Header().Clone()
+		w := bufferResponse(ctx, chain)
+		if w.status == http.StatusOK && isCacheableResponse(w.Header()) {
+			resp = &CachedResponse{
+				Status: w.status,
+				Header: diffHeader(before, w.Header()),
+				Body:   w.buf.Bytes(),
+			}
+			vary := w.Header().Values(web.HeaderVary)
+			if err = storeResponse(ctx.Context(), config.Cache, key, r, resp, vary, ttl); err != nil {
+				log.GetLogger("web.ResponseCacheFilter").WithContext(ctx.Context()).Errorf("set response cache %q error: %v", key, err)
+			}
+		}
+		writeResponse(ctx, w.status, w.buf.Bytes())
+	})
+}
+
+// responseCacheKey 返回请求的缓存 key ，查询参数按照名称排序。
+func responseCacheKey(r *http.Request, headers []string) string {
+	var sb strings.Builder
+	sb.WriteString(r.Method)
+	sb.WriteString(" ")
+	sb.WriteString(r.URL.Path)
+	if query := r.URL.Query(); len(query) > 0 {
+		sb.WriteString("?")
+		sb.WriteString(query.Encode())
+	}
+	writeRequestHeaders(&sb, r, headers)
+	return sb.String()
+}
+
+func writeRequestHeaders(sb *strings.Builder, r *http.Request, headers []string) {
+	for _, h := range headers {
+		sb.WriteString("\n")
+		sb.WriteString(http.CanonicalHeaderKey(h))
+		sb.WriteString(":")
+		sb.WriteString(strings.Join(r.Header.Values(h), ","))
+	}
+}
+
+// storeResponse 缓存响应，响应包含 Vary 头时在 key 下缓存一个索引项，真正的
+// 响应缓存在加上 Vary 指定的请求头的 key 下。
+func storeResponse(ctx context.Context, c ResponseCache, key string, r *http.Request, resp *CachedResponse, vary []string, ttl time.Duration) error {
+	if len(vary) == 0 {
+		return c.Set(ctx, key, resp, ttl)
+	}
+	index := &CachedResponse{Header: http.Header{web.HeaderVary: vary}}
+	if err := c.Set(ctx, key, index, ttl); err != nil {
+		return err
+	}
+	return c.Set(ctx, varyCacheKey(key, r, index.Header), resp, ttl)
+}
+
+// varyCacheKey 返回在 key 后面加上 Vary 指定的请求头之后的缓存 key 。
+func varyCacheKey(key string, r *http.Request, header http.Header) string {
+	var names []string
+	for _, v := range header.Values(web.HeaderVary) {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(key)
+	sb.WriteString("\nVary")
+	writeRequestHeaders(&sb, r, names)
+	return sb.String()
+}
+
+func hasCacheDirective(header http.Header, directive string) bool {
+	for _, v := range header.Values(web.HeaderCacheControl) {
+		for _, s := range strings.Split(v, ",") {
+			s = strings.TrimSpace(s)
+			if strings.EqualFold(strings.SplitN(s, "=", 2)[0], directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isCacheableResponse(header http.Header) bool {
+	if header.Get(web.HeaderSetCookie) != "" {
+		return false
+	}
+	for _, v := range header.Values(web.HeaderVary) {
+		if strings.TrimSpace(v) == "*" {
+			return false
+		}
+	}
+	return !hasCacheDirective(header, "no-store") && !hasCacheDirective(header, "private")
+}
+
+// diffHeader 返回执行处理函数时新增或者修改的响应头，前面的过滤器设置的响应
+// 头不需要缓存。
+func diffHeader(before, after http.Header) http.Header {
+	ret := make(http.Header)
+	for k, v := range after {
+		if old, ok := before[k]; ok && strings.Join(old, "\n") == strings.Join(v, "\n") {
+			continue
+		}
+		ret[k] = v
+	}
+	return ret
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/redis"
+	"github.com/go-spring/spring-core/web"
+	"github.com/go-spring/spring-core/web/middleware"
+	"github.com/golang/mock/gomock"
+)
+
+func TestResponseCacheFilter(t *testing.T) {
+
+	count := 0
+	m := web.NewMapper(web.MethodGet, "/hello", web.FUNC(func(ctx web.Context) {
+		count++
+		ctx.SetHeader("X-Count", "1")
+		ctx.String("hello %s", ctx.QueryParam("name"))
+	})).Cache(time.Minute)
+	assert.Equal(t, m.CacheTTL(), time.Minute)
+
+	f := middleware.NewResponseCacheFilter(middleware.ResponseCacheConfig{
+		Headers: []string{web.HeaderAcceptLanguage},
+	})
+
+	request := func(method, url string, header map[string]string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(method, "http://127.0.0.1:8080"+url, nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		ctx := web.NewBaseContext("/hello", m.Handler(), r, &web.SimpleResponse{ResponseWriter: w})
+		web.NewFilterChain([]web.Filter{f, web.HandlerFilter(m.Handler())}).Next(ctx, web.Recursive)
+		return w
+	}
+
+	w := request(http.MethodGet, "/hello?name=jim&a=1", nil)
+	assert.Equal(t, w.Body.String(), "hello jim")
+	assert.Equal(t, count, 1)
+
+	w = request(http.MethodGet, "/hello?a=1&name=jim", nil)
+	assert.Equal(t, w.Body.String(), "hello jim")
+	assert.Equal(t, w.Header().Get("X-Count"), "1")
+	assert.Equal(t, count, 1)
+
+	w = request(http.MethodGet, "/hello?name=jim&a=1", map[string]string{web.HeaderAcceptLanguage: "en"})
+	assert.Equal(t, w.Body.String(), "hello jim")
+	assert.Equal(t, count, 2)
+
+	w = request(http.MethodGet, "/hello?name=tom", map[string]string{web.HeaderCacheControl: "no-store"})
+	assert.Equal(t, w.Body.String(), "hello tom")
+	assert.Equal(t, count, 3)
+
+	w = request(http.MethodPost, "/hello?name=jim&a=1", nil)
+	assert.Equal(t, w.Body.String(), "hello jim")
+	assert.Equal(t, count, 4)
+}
+
+type TenantResp struct {
+	Tenant string `json:"tenant" xml:"tenant"`
+}
+
+func TestResponseCacheFilterVary(t *testing.T) {
+
+	count := 0
+	m := web.NewMapper(web.MethodGet, "/hello", web.FUNC(func(ctx web.Context) {
+		count++
+		ctx.SetHeader(web.HeaderVary, "X-Tenant")
+		web.Render(ctx, &TenantResp{Tenant: ctx.Header("X-Tenant")})
+	})).Cache(time.Minute)
+
+	f := middleware.NewResponseCacheFilter(middleware.NewResponseCacheConfig())
+
+	request := func(header map[string]string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:8080/hello", nil)
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		ctx := web.NewBaseContext("/hello", m.Handler(), r, &web.SimpleResponse{ResponseWriter: w})
+		web.NewFilterChain([]web.Filter{f, web.HandlerFilter(m.Handler())}).Next(ctx, web.Recursive)
+		return w
+	}
+
+	w := request(map[string]string{"X-Tenant": "a"})
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationJSONCharsetUTF8)
+	assert.Equal(t, count, 1)
+
+	w = request(map[string]string{"X-Tenant": "a", web.HeaderAccept: web.MIMEApplicationXML})
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationXMLCharsetUTF8)
+	assert.Equal(t, count, 2)
+
+	w = request(map[string]string{"X-Tenant": "b"})
+	assert.Equal(t, w.Body.String(), `{"tenant":"b"}`)
+	assert.Equal(t, count, 3)
+
+	w = request(map[string]string{"X-Tenant": "a"})
+	assert.Equal(t, w.Body.String(), `{"tenant":"a"}`)
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationJSONCharsetUTF8)
+	assert.Equal(t, count, 3)
+}
+
+func TestMemoryResponseCache(t *testing.T) {
+
+	ctx := context.Background()
+	c := middleware.NewMemoryResponseCache(1, 2)
+	for _, key := range []string{"a", "b", "c"} {
+		err := c.Set(ctx, key, &middleware.CachedResponse{Status: http.StatusOK}, time.Minute)
+		assert.Nil(t, err)
+	}
+	resp, err := c.Get(ctx, "a")
+	assert.Nil(t, err)
+	assert.Nil(t, resp)
+	resp, err = c.Get(ctx, "c")
+	assert.Nil(t, err)
+	assert.Equal(t, resp.Status, http.StatusOK)
+}
+
+func TestRedisResponseCache(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	driver := redis.NewMockDriver(ctrl)
+	driver.EXPECT().Exec(ctx, []interface{}{"GET", "web:GET /"}).Return(nil, redis.ErrNil())
+	driver.EXPECT().Exec(ctx, []interface{}{"SET", "web:GET /", `{"status":200,"header":{"Etag":["\"1\""]},"body":"aGVsbG8="}`, "PX", int64(60000)}).Return("OK", nil)
+	driver.EXPECT().Exec(ctx, []interface{}{"GET", "web:GET /"}).Return(`{"status":200,"header":{"Etag":["\"1\""]},"body":"aGVsbG8="}`, nil)
+
+	c := middleware.NewRedisResponseCache(redis.NewClient(driver), "web:")
+	resp, err := c.Get(ctx, "GET /")
+	assert.Nil(t, err)
+	assert.Nil(t, resp)
+
+	resp = &middleware.CachedResponse{
+		Status: http.StatusOK,
+		Header: http.Header{"Etag": []string{`"1"`}},
+		Body:   []byte("hello"),
+	}
+	err = c.Set(ctx, "GET /", resp, time.Minute)
+	assert.Nil(t, err)
+
+	resp, err = c.Get(ctx, "GET /")
+	assert.Nil(t, err)
+	assert.Equal(t, resp.Status, http.StatusOK)
+	assert.Equal(t, string(resp.Body), "hello")
+	assert.Equal(t, resp.Header.Get(web.HeaderETag), `"1"`)
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/web"
+)
+
+type ETagConfig struct {
+	Weak bool // 是否生成弱校验的 ETag
+}
+
+func NewETagConfig() ETagConfig {
+	return ETagConfig{}
+}
+
+// NewETagFilter 缓冲 GET 和 HEAD 请求的响应，根据响应体计算 ETag ，然后根据
+// If-None-Match 和 If-Modified-Since 请求头决定是否返回 304 。处理函数已经设
+// 置 ETag 时不再重新计算。
+func NewETagFilter(config ETagConfig) web.Filter {
+	return web.FuncFilter(func(ctx web.Context, chain web.FilterChain) {
+
+		if !isCacheableMethod(ctx.Request().Method) {
+			chain.Next(ctx, web.Iterative)
+			return
+		}
+
+		w := bufferResponse(ctx, chain)
+		if w.status == http.StatusOK && w.Header().Get(web.HeaderETag) == "" {
+			w.Header().Set(web.HeaderETag, ETag(w.buf.Bytes(), config.Weak))
+		}
+		writeResponse(ctx, w.status, w.buf.Bytes())
+	})
+}
+
+// ETag 根据数据的 SHA1 值生成 ETag ，weak 为 true 时生成弱校验的 ETag 。
+func ETag(data []byte, weak bool) string {
+	sum := sha1.Sum(data)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	if weak {
+		return "W/" + etag
+	}
+	return etag
+}
+
+func isCacheableMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// bufferedWriter 缓存响应的状态码和响应体，不直接发送给客户端。
+type bufferedWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.buf.Write(data)
+}
+
+// bufferResponse 执行后续的过滤器和处理函数，返回缓存的响应。
+func bufferResponse(ctx web.Context, chain web.FilterChain) *bufferedWriter {
+	r := ctx.Response().Get()
+	w := &bufferedWriter{ResponseWriter: r}
+	ctx.Response().Set(w)
+	defer func() { ctx.Response().Set(r) }()
+	chain.Next(ctx, web.Recursive)
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w
+}
+
+// writeResponse 发送响应，满足条件请求时只返回 304 。
+func writeResponse(ctx web.Context, status int, body []byte) {
+	w := ctx.Response()
+	if status == http.StatusOK && notModified(ctx.Request(), w.Header()) {
+		w.Header().Del(web.HeaderContentType)
+		w.Header().Del(web.HeaderContentLength)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	util.Panic(err).When(err != nil)
+}
+
+// notModified 判断客户端缓存的响应是否仍然有效，If-None-Match 优先于
+// If-Modified-Since 。
+func notModified(r *http.Request, header http.Header) bool {
+	if inm := r.Header.Get(web.HeaderIfNoneMatch); inm != "" {
+		etag := header.Get(web.HeaderETag)
+		return etag != "" && matchETag(inm, etag)
+	}
+	ims := r.Header.Get(web.HeaderIfModifiedSince)
+	lm := header.Get(web.HeaderLastModified)
+	if ims == "" || lm == "" {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(lm)
+	if err != nil {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(t)
+}
+
+// matchETag 使用弱比较判断 If-None-Match 是否包含 etag 。
+func matchETag(inm string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, s := range strings.Split(inm, ",") {
+		s = strings.TrimSpace(s)
+		if s == "*" || strings.TrimPrefix(s, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/web"
+	"github.com/go-spring/spring-core/web/middleware"
+)
+
+func TestETagFilter(t *testing.T) {
+
+	lastModified := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	etag := middleware.ETag([]byte("hello"), true)
+
+	testcases := []struct {
+		header map[string]string
+		status int
+		body   string
+	}{
+		{nil, http.StatusOK, "hello"},
+		{map[string]string{web.HeaderIfNoneMatch: etag}, http.StatusNotModified, ""},
+		{map[string]string{web.HeaderIfNoneMatch: `"xxx", ` + etag[2:]}, http.StatusNotModified, ""},
+		{map[string]string{web.HeaderIfNoneMatch: `"xxx"`}, http.StatusOK, "hello"},
+		{map[string]string{web.HeaderIfModifiedSince: lastModified.Format(http.TimeFormat)}, http.StatusNotModified, ""},
+		{map[string]string{web.HeaderIfModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat)}, http.StatusOK, "hello"},
+		{map[string]string{
+			web.HeaderIfNoneMatch:     `"xxx"`,
+			web.HeaderIfModifiedSince: lastModified.Format(http.TimeFormat),
+		}, http.StatusOK, "hello"},
+	}
+
+	f := middleware.NewETagFilter(middleware.ETagConfig{Weak: true})
+	for _, c := range testcases {
+		r, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:8080/", nil)
+		for k, v := range c.header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		ctx := web.NewBaseContext("", nil, r, &web.SimpleResponse{ResponseWriter: w})
+		web.NewFilterChain([]web.Filter{f, web.HandlerFilter(web.FUNC(func(ctx web.Context) {
+			ctx.SetHeader(web.HeaderLastModified, lastModified.Format(http.TimeFormat))
+			ctx.String("hello")
+		}))}).Next(ctx, web.Recursive)
+		assert.Equal(t, w.Code, c.status)
+		assert.Equal(t, w.Body.String(), c.body)
+		assert.Equal(t, w.Header().Get(web.HeaderETag), etag)
+	}
+
+	r, _ := http.NewRequest(http.MethodPost, "http://127.0.0.1:8080/", nil)
+	r.Header.Set(web.HeaderIfNoneMatch, "*")
+	w := httptest.NewRecorder()
+	ctx := web.NewBaseContext("", nil, r, &web.SimpleResponse{ResponseWriter: w})
+	web.NewFilterChain([]web.Filter{f, web.HandlerFilter(web.FUNC(func(ctx web.Context) {
+		ctx.String("hello")
+	}))}).Next(ctx, web.Recursive)
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Header().Get(web.HeaderETag), "")
+}
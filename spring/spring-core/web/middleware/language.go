@@ -0,0 +1,98 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/web"
+	"github.com/go-spring/spring-core/web/i18n"
+)
+
+type LanguageConfig struct {
+	QueryParam string // 指定语言的查询参数，为空时不使用查询参数
+	Cookie     string // 指定语言的 cookie ，为空时不使用 cookie
+}
+
+func NewLanguageConfig() LanguageConfig {
+	return LanguageConfig{QueryParam: "lang", Cookie: "lang"}
+}
+
+// NewLanguageFilter 依次根据查询参数、cookie 和 Accept-Language 头从已注册
+// 的语言中选择上下文语言，都不能匹配时使用 i18n 的默认语言。
+func NewLanguageFilter(config LanguageConfig) web.Filter {
+	return web.FuncFilter(func(ctx web.Context, chain web.FilterChain) {
+		var candidates []string
+		if config.QueryParam != "" {
+			if s := ctx.QueryParam(config.QueryParam); s != "" {
+				candidates = append(candidates, s)
+			}
+		}
+		if config.Cookie != "" {
+			if c, err := ctx.Cookie(config.Cookie); err == nil && c.Value != "" {
+				candidates = append(candidates, c.Value)
+			}
+		}
+		accept := ctx.Header(web.HeaderAcceptLanguage)
+		candidates = append(candidates, parseAcceptLanguage(accept)...)
+		if language := i18n.Match(candidates...); language != "" {
+			err := i18n.SetLanguage(ctx.Context(), language)
+			util.Panic(err).When(err != nil)
+			ctx.SetHeader(web.HeaderContentLanguage, language)
+		}
+		chain.Next(ctx, web.Iterative)
+	})
+}
+
+// parseAcceptLanguage 返回按 q 值降序排列的语言列表，忽略 * 和 q=0 的语言。
+func parseAcceptLanguage(accept string) []string {
+	type language struct {
+		tag string
+		q   float64
+	}
+	var languages []language
+	for _, s := range strings.Split(accept, ",") {
+		parts := strings.Split(s, ";")
+		tag := strings.TrimSpace(parts[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if f, err := strconv.ParseFloat(p[2:], 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q > 0 {
+			languages = append(languages, language{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+	var ret []string
+	for _, l := range languages {
+		ret = append(ret, l.tag)
+	}
+	return ret
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/conf"
+	"github.com/go-spring/spring-core/web"
+	"github.com/go-spring/spring-core/web/i18n"
+	"github.com/go-spring/spring-core/web/middleware"
+)
+
+func init() {
+	for _, language := range []string{"en", "zh-CN", "fr-FR"} {
+		err := i18n.Register(language, conf.New())
+		util.Panic(err).When(err != nil)
+	}
+}
+
+func TestLanguageFilter(t *testing.T) {
+
+	testcases := []struct {
+		url    string
+		cookie string
+		accept string
+		expect string
+	}{
+		{"/", "", "", "zh-CN"},
+		{"/", "", "de;q=0.9, en-US;q=0.8, zh;q=0.5", "en"},
+		{"/", "", "de, zh-TW;q=0.9, fr-FR;q=0", "zh-CN"},
+		{"/", "fr", "en", "fr-FR"},
+		{"/?lang=en", "fr", "zh", "en"},
+		{"/?lang=xx", "", "fr-CA", "fr-FR"},
+	}
+
+	f := middleware.NewLanguageFilter(middleware.NewLanguageConfig())
+	for _, c := range testcases {
+		r, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:8080"+c.url, nil)
+		if c.accept != "" {
+			r.Header.Set(web.HeaderAcceptLanguage, c.accept)
+		}
+		if c.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: "lang", Value: c.cookie})
+		}
+		w := httptest.NewRecorder()
+		ctx := web.NewBaseContext("", nil, r, &web.SimpleResponse{ResponseWriter: w})
+		web.NewFilterChain([]web.Filter{f, f}).Next(ctx, web.Recursive)
+		assert.Equal(t, i18n.Language(ctx.Context()), c.expect)
+	}
+}
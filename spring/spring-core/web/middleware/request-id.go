@@ -17,6 +17,7 @@
 package middleware
 
 import (
+	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-core/web"
 	"github.com/google/uuid"
 )
@@ -24,12 +25,16 @@ import (
 type RequestIDConfig struct {
 	Header    string
 	Generator func() string
+	LogField  string // 请求 ID 在日志上下文中的字段名，为空时使用 requestID
 }
 
 func NewRequestIDConfig() RequestIDConfig {
 	return RequestIDConfig{}
 }
 
+// DefaultRequestIDLogField 请求 ID 在日志上下文中默认的字段名。
+const DefaultRequestIDLogField = "requestID"
+
 func NewRequestIDFilter(config RequestIDConfig) web.Filter {
 	if config.Header == "" {
 		config.Header = web.HeaderXRequestID
@@ -39,12 +44,16 @@ func NewRequestIDFilter(config RequestIDConfig) web.Filter {
 			return uuid.New().String()
 		}
 	}
+	if config.LogField == "" {
+		config.LogField = DefaultRequestIDLogField
+	}
 	return web.FuncFilter(func(ctx web.Context, chain web.FilterChain) {
 		reqID := ctx.Header(config.Header)
 		if reqID == "" {
 			reqID = config.Generator()
 		}
 		ctx.SetHeader(web.HeaderXRequestID, reqID)
+		ctx.SetContext(log.WithMDC(ctx.Context(), log.String(config.LogField, reqID)))
 		chain.Next(ctx, web.Iterative)
 	})
 }
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-core/web"
 	"github.com/go-spring/spring-core/web/middleware"
 )
@@ -35,4 +36,7 @@ func TestRequestIDFilter(t *testing.T) {
 	})
 	web.NewFilterChain([]web.Filter{f}).Next(ctx, web.Recursive)
 	assert.Equal(t, w.Result().Header.Get(web.HeaderXRequestID), "0d9ad123-327f-bde5-14b4-8f93c36c3546")
+	field, ok := log.ContextField(ctx.Context(), middleware.DefaultRequestIDLogField)
+	assert.True(t, ok)
+	assert.Equal(t, field, log.String("requestID", "0d9ad123-327f-bde5-14b4-8f93c36c3546"))
 }
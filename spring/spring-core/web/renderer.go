@@ -0,0 +1,190 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-spring/spring-base/util"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+// Renderer 将处理函数的返回值按照某种 MIME 类型写入响应。
+type Renderer interface {
+	Render(ctx Context, i interface{}) error
+}
+
+// RendererFunc func 形式的 Renderer 。
+type RendererFunc func(ctx Context, i interface{}) error
+
+func (f RendererFunc) Render(ctx Context, i interface{}) error {
+	return f(ctx, i)
+}
+
+var renderers = map[string]Renderer{
+	MIMEApplicationJSON:     RendererFunc(RenderJSON),
+	MIMEApplicationXML:      RendererFunc(RenderXML),
+	MIMETextXML:             RendererFunc(RenderXML),
+	MIMEApplicationYAML:     RendererFunc(RenderYAML),
+	MIMETextYAML:            RendererFunc(RenderYAML),
+	MIMEApplicationProtobuf: RendererFunc(RenderProtobuf),
+	MIMEApplicationMsgpack:  RendererFunc(RenderMsgpack),
+}
+
+// defaultRenderType 客户端未指定或者指定的类型均不支持时使用的类型。
+var defaultRenderType = MIMEApplicationJSON
+
+// RegisterRenderer 注册 MIME 类型对应的 Renderer 。
+func RegisterRenderer(mime string, r Renderer) {
+	renderers[mime] = r
+}
+
+// SetDefaultRenderType 设置默认的响应类型，该类型必须已经注册过 Renderer 。
+func SetDefaultRenderType(mime string) {
+	if _, ok := renderers[mime]; !ok {
+		panic(fmt.Errorf("no renderer registered for %q", mime))
+	}
+	defaultRenderType = mime
+}
+
+// Render 根据请求的 Accept 头选择合适的 Renderer 写入响应，协商出的 Renderer
+// 无法编码返回值时 (例如返回值不是 proto.Message) 使用默认类型写入响应。 Maybe panic.
+func Render(ctx Context, i interface{}) {
+	mime := NegotiateRenderType(ctx.Header(HeaderAccept))
+	err := renderers[mime].Render(ctx, i)
+	if err != nil && mime != defaultRenderType {
+		err = renderers[defaultRenderType].Render(ctx, i)
+	}
+	util.Panic(err).When(err != nil)
+}
+
+type acceptRange struct {
+	mime string
+	q    float64
+}
+
+// parseAccept 解析 Accept 头，返回按 q 值降序排列的 MIME 类型列表。
+func parseAccept(accept string) []acceptRange {
+	var ret []acceptRange
+	for _, s := range strings.Split(accept, ",") {
+		parts := strings.Split(s, ";")
+		mime := strings.ToLower(strings.TrimSpace(parts[0]))
+		if mime == "" {
+			continue
+		}
+		q := 1.0
+		for _, p := range parts[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+			f, err := strconv.ParseFloat(p[2:], 64)
+			if err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			ret = append(ret, acceptRange{mime: mime, q: q})
+		}
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].q > ret[j].q
+	})
+	return ret
+}
+
+// NegotiateRenderType 返回 Accept 头中优先级最高并且已经注册过 Renderer 的
+// MIME 类型，没有合适的类型时返回默认类型。
+func NegotiateRenderType(accept string) string {
+	for _, r := range parseAccept(accept) {
+		switch {
+		case r.mime == "*/*":
+			return defaultRenderType
+		case strings.HasSuffix(r.mime, "/*"):
+			prefix := strings.TrimSuffix(r.mime, "*")
+			if strings.HasPrefix(defaultRenderType, prefix) {
+				return defaultRenderType
+			}
+			var types []string
+			for mime := range renderers {
+				if strings.HasPrefix(mime, prefix) {
+					types = append(types, mime)
+				}
+			}
+			if len(types) > 0 {
+				sort.Strings(types)
+				return types[0]
+			}
+		default:
+			if _, ok := renderers[r.mime]; ok {
+				return r.mime
+			}
+		}
+	}
+	return defaultRenderType
+}
+
+// RenderJSON 以 JSON 格式写入响应。
+func RenderJSON(ctx Context, i interface{}) error {
+	ctx.JSON(i)
+	return nil
+}
+
+// RenderXML 以 XML 格式写入响应。
+func RenderXML(ctx Context, i interface{}) error {
+	ctx.XML(i)
+	return nil
+}
+
+// RenderYAML 以 YAML 格式写入响应。
+func RenderYAML(ctx Context, i interface{}) error {
+	b, err := yaml.Marshal(i)
+	if err != nil {
+		return err
+	}
+	ctx.Blob(MIMEApplicationYAMLCharsetUTF8, b)
+	return nil
+}
+
+// RenderProtobuf 以 protobuf 格式写入响应，i 必须是 proto.Message 类型。
+func RenderProtobuf(ctx Context, i interface{}) error {
+	m, ok := i.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%T is not a proto.Message", i)
+	}
+	b, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	ctx.Blob(MIMEApplicationProtobuf, b)
+	return nil
+}
+
+// RenderMsgpack 以 msgpack 格式写入响应。
+func RenderMsgpack(ctx Context, i interface{}) error {
+	b, err := msgpack.Marshal(i)
+	if err != nil {
+		return err
+	}
+	ctx.Blob(MIMEApplicationMsgpack, b)
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/web"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestNegotiateRenderType(t *testing.T) {
+	assert.Equal(t, web.NegotiateRenderType(""), web.MIMEApplicationJSON)
+	assert.Equal(t, web.NegotiateRenderType("*/*"), web.MIMEApplicationJSON)
+	assert.Equal(t, web.NegotiateRenderType("text/html"), web.MIMEApplicationJSON)
+	assert.Equal(t, web.NegotiateRenderType("application/xml"), web.MIMEApplicationXML)
+	assert.Equal(t, web.NegotiateRenderType("application/*"), web.MIMEApplicationJSON)
+	assert.Equal(t, web.NegotiateRenderType("application/xml;q=0.5, application/protobuf"), web.MIMEApplicationProtobuf)
+	assert.Equal(t, web.NegotiateRenderType("application/xml;q=0.5, application/protobuf;q=0"), web.MIMEApplicationXML)
+	assert.Equal(t, web.NegotiateRenderType("text/html, application/x-yaml;q=0.9, */*;q=0.8"), web.MIMEApplicationYAML)
+}
+
+func render(accept string, i interface{}) *httptest.ResponseRecorder {
+	r, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:8080/", nil)
+	if accept != "" {
+		r.Header.Set(web.HeaderAccept, accept)
+	}
+	w := httptest.NewRecorder()
+	ctx := web.NewBaseContext("", nil, r, &web.SimpleResponse{ResponseWriter: w})
+	web.Render(ctx, i)
+	return w
+}
+
+func TestRender(t *testing.T) {
+
+	type Resp struct {
+		Name string `json:"name" xml:"name" yaml:"name" msgpack:"name"`
+	}
+
+	w := render("", &Resp{Name: "jim"})
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationJSONCharsetUTF8)
+	assert.Equal(t, w.Body.String(), `{"name":"jim"}`)
+
+	w = render("application/xml", &Resp{Name: "jim"})
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationXMLCharsetUTF8)
+	assert.Equal(t, w.Body.String(), "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Resp><name>jim</name></Resp>")
+
+	w = render("application/x-yaml", &Resp{Name: "jim"})
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationYAMLCharsetUTF8)
+	assert.Equal(t, w.Body.String(), "name: jim\n")
+
+	w = render("application/msgpack", &Resp{Name: "jim"})
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationMsgpack)
+	var r Resp
+	err := msgpack.Unmarshal(w.Body.Bytes(), &r)
+	assert.Nil(t, err)
+	assert.Equal(t, r, Resp{Name: "jim"})
+
+	w = render("application/protobuf", wrapperspb.String("jim"))
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationProtobuf)
+	var s wrapperspb.StringValue
+	err = proto.Unmarshal(w.Body.Bytes(), &s)
+	assert.Nil(t, err)
+	assert.Equal(t, s.GetValue(), "jim")
+
+	w = render("application/protobuf", &Resp{Name: "jim"})
+	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationJSONCharsetUTF8)
+	assert.Equal(t, w.Body.String(), `{"name":"jim"}`)
+}
+
+func TestRegisterRenderer(t *testing.T) {
+
+	web.RegisterRenderer(web.MIMETextPlain, web.RendererFunc(func(ctx web.Context, i interface{}) error {
+		ctx.String("%v", i)
+		return nil
+	}))
+
+	w := render("text/plain", "hello")
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMETextPlainCharsetUTF8)
+	assert.Equal(t, w.Body.String(), "hello")
+
+	web.SetDefaultRenderType(web.MIMETextPlain)
+	defer web.SetDefaultRenderType(web.MIMEApplicationJSON)
+
+	w = render("", "world")
+	assert.Equal(t, w.Body.String(), "world")
+
+	assert.Panic(t, func() {
+		web.SetDefaultRenderType("application/unknown")
+	}, "no renderer registered for \"application/unknown\"")
+}
@@ -18,6 +18,7 @@ package web
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-spring/spring-base/util"
 )
@@ -91,6 +92,41 @@ func (m *Mapper) Operation(op Operation) {
 	m.swagger = op
 }
 
+// Cache 设置路由响应的缓存时间，需要配合响应缓存过滤器使用，对文件资源无效。
+func (m *Mapper) Cache(ttl time.Duration) *Mapper {
+	if _, ok := m.handler.(*FileHandler); ok {
+		return m
+	}
+	if h, ok := m.handler.(*cacheHandler); ok {
+		h.ttl = ttl
+		return m
+	}
+	m.handler = &cacheHandler{Handler: m.handler, ttl: ttl}
+	return m
+}
+
+// CacheTTL 返回路由响应的缓存时间，未设置时返回 0 。
+func (m *Mapper) CacheTTL() time.Duration {
+	if h, ok := m.handler.(*cacheHandler); ok {
+		return h.ttl
+	}
+	return 0
+}
+
+// cacheHandler 携带缓存时间的处理函数。
+type cacheHandler struct {
+	Handler
+	ttl time.Duration
+}
+
+// CacheTTL 返回当前请求匹配的路由的缓存时间，未设置时返回 0 。
+func CacheTTL(ctx Context) time.Duration {
+	if h, ok := ctx.Handler().(*cacheHandler); ok {
+		return h.ttl
+	}
+	return 0
+}
+
 // Router 路由注册接口
 type Router interface {
 
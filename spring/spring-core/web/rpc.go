@@ -24,6 +24,7 @@ import (
 
 	"github.com/go-spring/spring-base/knife"
 	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/validate"
 )
 
 const (
@@ -49,6 +50,11 @@ func (b *bindHandler) call(ctx Context) interface{} {
 	// 反射创建需要绑定请求参数
 	bindVal := reflect.New(b.bindType.Elem())
 	err := ctx.Bind(bindVal.Interface())
+	if errs, ok := err.(validate.Errors); ok {
+		e := NewHttpError(http.StatusBadRequest)
+		e.Internal = errs
+		panic(e)
+	}
 	util.Panic(err).When(err != nil)
 
 	// 执行处理函数，并返回结果
@@ -98,7 +104,7 @@ func GetHTTPRequest(ctx context.Context) *http.Request {
 	return r
 }
 
-// RPCInvoke 可自定义的 rpc 执行函数。
+// RPCInvoke 可自定义的 rpc 执行函数，默认根据 Accept 头选择响应格式。
 var RPCInvoke = func(ctx Context, fn func(Context) interface{}) {
-	ctx.JSON(fn(ctx))
+	Render(ctx, fn(ctx))
 }
@@ -27,6 +27,7 @@ import (
 	"github.com/go-spring/spring-base/cast"
 	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/validate"
 )
 
 // HandlerFunc 标准 Web 处理函数
@@ -198,6 +199,8 @@ func (s *server) ErrorHandler() ErrorHandler {
 			return
 		}
 		switch v := err.Internal.(type) {
+		case validate.Errors:
+			ValidationErrorHandler(ctx, v)
 		case string:
 			ctx.String(v)
 		default:
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+
+	"github.com/go-spring/spring-core/validate"
+	"github.com/go-spring/spring-core/web/i18n"
+)
+
+// validationMessagePrefix 字段校验错误在 i18n 中的 key 前缀，完整的 key 为
+// "validate.<rule>"，消息使用 i18n.FormatMessage 格式化，可以引用 field、
+// param 两个参数，为了避免泄露敏感信息不提供字段的值。
+const validationMessagePrefix = "validate."
+
+// ValidationFieldError 校验失败的字段在响应中的格式。
+type ValidationFieldError struct {
+	Field   string `json:"field" xml:"field"`     // 字段路径
+	Rule    string `json:"rule" xml:"rule"`       // 校验规则
+	Message string `json:"message" xml:"message"` // 错误信息
+}
+
+// ValidationMessage 返回字段校验错误的本地化消息，当前语言没有配置该规则的
+// 消息时返回默认消息。
+func ValidationMessage(ctx Context, e *validate.FieldError) string {
+	language := i18n.Language(ctx.Context())
+	msg := i18n.Get(ctx.Context(), validationMessagePrefix+e.Rule)
+	if msg == "" {
+		return e.Message
+	}
+	s, err := i18n.FormatMessage(language, msg, map[string]interface{}{
+		"field": e.Field,
+		"param": e.Param,
+	})
+	if err != nil {
+		return e.Message
+	}
+	return s
+}
+
+// ValidationErrorBody 构造校验失败时的响应体，可以通过替换它定制响应格式。
+var ValidationErrorBody = func(ctx Context, errs validate.Errors) interface{} {
+	var data []*ValidationFieldError
+	for _, e := range errs {
+		data = append(data, &ValidationFieldError{
+			Field:   e.Field,
+			Rule:    e.Rule,
+			Message: ValidationMessage(ctx, e),
+		})
+	}
+	return &RpcResult{
+		ErrorCode: NewErrorCode(http.StatusBadRequest, http.StatusText(http.StatusBadRequest)),
+		Data:      data,
+	}
+}
+
+// ValidationErrorHandler 以 400 状态码返回校验失败的字段，响应格式和 BIND
+// 处理函数一样根据 Accept 头选择，可以通过替换它定制处理方式。 Maybe panic.
+var ValidationErrorHandler = func(ctx Context, errs validate.Errors) {
+	w := ctx.Response().Get()
+	ctx.Response().Set(&statusWriter{ResponseWriter: w, code: http.StatusBadRequest})
+	defer ctx.Response().Set(w)
+	Render(ctx, ValidationErrorBody(ctx, errs))
+}
+
+// statusWriter 在第一次写入响应时使用指定的状态码，保证 Renderer 设置的响应
+// 头在状态码之前写入。
+type statusWriter struct {
+	http.ResponseWriter
+	code  int
+	wrote bool
+}
+
+func (w *statusWriter) WriteHeader(int) {
+	if !w.wrote {
+		w.wrote = true
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(w.code)
+	return w.ResponseWriter.Write(b)
+}
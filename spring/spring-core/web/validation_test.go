@@ -0,0 +1,82 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/conf"
+	"github.com/go-spring/spring-core/validate"
+	"github.com/go-spring/spring-core/web"
+	"github.com/go-spring/spring-core/web/i18n"
+)
+
+type ValidateReq struct {
+	Name string `query:"name" expr:"len($)>=3"`
+	Age  int    `query:"age" expr:"$>=18"`
+}
+
+func TestValidationError(t *testing.T) {
+
+	err := i18n.Register("en-GB", conf.Map(map[string]interface{}{
+		"validate.expr": "{field} is invalid",
+	}))
+	util.Panic(err).When(err != nil)
+
+	h := web.BIND(func(ctx context.Context, req *ValidateReq) interface{} {
+		return req
+	})
+
+	r, _ := http.NewRequest(http.MethodGet, "http://127.0.0.1:8080/?name=jo&age=3", nil)
+	w := httptest.NewRecorder()
+	ctx := web.NewBaseContext("", nil, r, &web.SimpleResponse{ResponseWriter: w})
+
+	var errs validate.Errors
+	func() {
+		defer func() {
+			e, ok := recover().(*web.HttpError)
+			assert.True(t, ok)
+			assert.Equal(t, e.Code, http.StatusBadRequest)
+			errs = e.Internal.(validate.Errors)
+		}()
+		h.Invoke(ctx)
+	}()
+	assert.Equal(t, len(errs), 2)
+
+	web.ValidationErrorHandler(ctx, errs)
+	assert.Equal(t, w.Code, http.StatusBadRequest)
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationJSONCharsetUTF8)
+	assert.Equal(t, w.Body.String(), `{"code":400,"msg":"Bad Request","data":[`+
+		`{"field":"Name","rule":"expr","message":"validate failed on \"len($)\u003e=3\""},`+
+		`{"field":"Age","rule":"expr","message":"validate failed on \"$\u003e=18\""}]}`)
+
+	r.Header.Set(web.HeaderAccept, web.MIMEApplicationXML)
+	w = httptest.NewRecorder()
+	ctx = web.NewBaseContext("", nil, r, &web.SimpleResponse{ResponseWriter: w})
+	web.ValidationErrorHandler(ctx, errs)
+	assert.Equal(t, w.Code, http.StatusBadRequest)
+	assert.Equal(t, w.Header().Get(web.HeaderContentType), web.MIMEApplicationXMLCharsetUTF8)
+
+	err = i18n.SetLanguage(ctx.Context(), "en-GB")
+	util.Panic(err).When(err != nil)
+	assert.Equal(t, web.ValidationMessage(ctx, errs[0]), "Name is invalid")
+}
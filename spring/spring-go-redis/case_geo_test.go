@@ -0,0 +1,47 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package SpringGoRedis_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-core/redis"
+)
+
+func TestGeoAdd(t *testing.T) {
+	runCase(t, new(redis.Cases).GeoAdd())
+}
+
+func TestGeoDist(t *testing.T) {
+	runCase(t, new(redis.Cases).GeoDist())
+}
+
+func TestGeoHash(t *testing.T) {
+	runCase(t, new(redis.Cases).GeoHash())
+}
+
+func TestGeoPos(t *testing.T) {
+	runCase(t, new(redis.Cases).GeoPos())
+}
+
+func TestGeoSearch(t *testing.T) {
+	runCase(t, new(redis.Cases).GeoSearch())
+}
+
+func TestGeoSearchStore(t *testing.T) {
+	runCase(t, new(redis.Cases).GeoSearchStore())
+}
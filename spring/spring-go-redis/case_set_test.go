@@ -66,6 +66,10 @@ func TestSPop(t *testing.T) {
 	runCase(t, new(redis.Cases).SPop())
 }
 
+func TestSPopN(t *testing.T) {
+	runCase(t, new(redis.Cases).SPopN())
+}
+
 func TestSRandMember(t *testing.T) {
 	runCase(t, new(redis.Cases).SRandMember())
 }
@@ -74,6 +78,10 @@ func TestSRem(t *testing.T) {
 	runCase(t, new(redis.Cases).SRem())
 }
 
+func TestSScan(t *testing.T) {
+	runCase(t, new(redis.Cases).SScan())
+}
+
 func TestSUnion(t *testing.T) {
 	runCase(t, new(redis.Cases).SUnion())
 }
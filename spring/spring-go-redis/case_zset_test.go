@@ -58,14 +58,26 @@ func TestZPopMax(t *testing.T) {
 	runCase(t, new(redis.Cases).ZPopMax())
 }
 
+func TestZPopMaxN(t *testing.T) {
+	runCase(t, new(redis.Cases).ZPopMaxN())
+}
+
 func TestZPopMin(t *testing.T) {
 	runCase(t, new(redis.Cases).ZPopMin())
 }
 
+func TestZPopMinN(t *testing.T) {
+	runCase(t, new(redis.Cases).ZPopMinN())
+}
+
 func TestZRandMember(t *testing.T) {
 	runCase(t, new(redis.Cases).ZRandMember())
 }
 
+func TestZRandMemberN(t *testing.T) {
+	runCase(t, new(redis.Cases).ZRandMemberN())
+}
+
 func TestZRange(t *testing.T) {
 	runCase(t, new(redis.Cases).ZRange())
 }
@@ -102,6 +114,10 @@ func TestZRevRange(t *testing.T) {
 	runCase(t, new(redis.Cases).ZRevRange())
 }
 
+func TestZRevRangeWithScores(t *testing.T) {
+	runCase(t, new(redis.Cases).ZRevRangeWithScores())
+}
+
 func TestZRevRangeByLex(t *testing.T) {
 	runCase(t, new(redis.Cases).ZRevRangeByLex())
 }
@@ -114,6 +130,10 @@ func TestZRevRank(t *testing.T) {
 	runCase(t, new(redis.Cases).ZRevRank())
 }
 
+func TestZScan(t *testing.T) {
+	runCase(t, new(redis.Cases).ZScan())
+}
+
 func TestZScore(t *testing.T) {
 	runCase(t, new(redis.Cases).ZScore())
 }
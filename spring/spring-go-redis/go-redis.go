@@ -35,8 +35,32 @@ func NewClient(config redis.Config) (*redis.Client, error) {
 
 func Open(config redis.Config) (redis.Driver, error) {
 
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	var client g.UniversalClient
+	switch config.Mode {
+	case redis.ModeCluster:
+		client = newClusterClient(config)
+	case redis.ModeSentinel:
+		client = newFailoverClient(config)
+	default:
+		client = newClient(config)
+	}
+
+	if config.Ping {
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Driver{client: client}, nil
+}
+
+func newClient(config redis.Config) g.UniversalClient {
 	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	client := g.NewClient(&g.Options{
+	return g.NewClient(&g.Options{
 		Addr:         address,
 		Username:     config.Username,
 		Password:     config.Password,
@@ -45,19 +69,46 @@ func Open(config redis.Config) (redis.Driver, error) {
 		ReadTimeout:  time.Duration(config.ReadTimeout) * time.Millisecond,
 		WriteTimeout: time.Duration(config.WriteTimeout) * time.Millisecond,
 		IdleTimeout:  time.Duration(config.IdleTimeout) * time.Millisecond,
+		PoolSize:     config.MaxActive,
 	})
+}
 
-	if config.Ping {
-		if err := client.Ping(context.Background()).Err(); err != nil {
-			return nil, err
-		}
-	}
+func newClusterClient(config redis.Config) g.UniversalClient {
+	readFrom := config.Cluster.ReadFrom
+	return g.NewClusterClient(&g.ClusterOptions{
+		Addrs:         config.Cluster.Addrs,
+		MaxRedirects:  config.Cluster.MaxRedirects,
+		ReadOnly:      readFrom == redis.ReadFromReplica || readFrom == redis.ReadFromRandom,
+		RouteRandomly: readFrom == redis.ReadFromRandom,
+		Username:      config.Username,
+		Password:      config.Password,
+		DialTimeout:   time.Duration(config.ConnectTimeout) * time.Millisecond,
+		ReadTimeout:   time.Duration(config.ReadTimeout) * time.Millisecond,
+		WriteTimeout:  time.Duration(config.WriteTimeout) * time.Millisecond,
+		IdleTimeout:   time.Duration(config.IdleTimeout) * time.Millisecond,
+		PoolSize:      config.MaxActive,
+	})
+}
 
-	return &Driver{client: client}, nil
+func newFailoverClient(config redis.Config) g.UniversalClient {
+	return g.NewFailoverClient(&g.FailoverOptions{
+		MasterName:       config.Sentinel.MasterName,
+		SentinelAddrs:    config.Sentinel.Addrs,
+		SentinelUsername: config.Sentinel.Username,
+		SentinelPassword: config.Sentinel.Password,
+		Username:         config.Username,
+		Password:         config.Password,
+		DB:               config.Database,
+		DialTimeout:      time.Duration(config.ConnectTimeout) * time.Millisecond,
+		ReadTimeout:      time.Duration(config.ReadTimeout) * time.Millisecond,
+		WriteTimeout:     time.Duration(config.WriteTimeout) * time.Millisecond,
+		IdleTimeout:      time.Duration(config.IdleTimeout) * time.Millisecond,
+		PoolSize:         config.MaxActive,
+	})
 }
 
 type Driver struct {
-	client *g.Client
+	client g.UniversalClient
 }
 
 func (c *Driver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
@@ -71,3 +122,38 @@ func (c *Driver) Exec(ctx context.Context, args []interface{}) (interface{}, err
 	}
 	return ret.Val(), nil
 }
+
+// Subscribe subscribes the channels by a dedicated connection.
+func (c *Driver) Subscribe(ctx context.Context, channels ...string) (redis.Subscription, error) {
+	pubsub := c.client.Subscribe(ctx, channels...)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+	s := &subscription{
+		pubsub: pubsub,
+		ch:     make(chan *redis.Message, 100),
+	}
+	go s.run()
+	return s, nil
+}
+
+type subscription struct {
+	pubsub *g.PubSub
+	ch     chan *redis.Message
+}
+
+func (s *subscription) run() {
+	defer close(s.ch)
+	for msg := range s.pubsub.Channel() {
+		s.ch <- &redis.Message{Channel: msg.Channel, Payload: msg.Payload}
+	}
+}
+
+func (s *subscription) Channel() <-chan *redis.Message {
+	return s.ch
+}
+
+func (s *subscription) Close() error {
+	return s.pubsub.Close()
+}
@@ -4,6 +4,7 @@ go 1.14
 
 require (
 	github.com/go-redis/redis/v8 v8.11.4
+	github.com/go-spring/spring-base v1.1.3
 	github.com/go-spring/spring-core v1.1.3
 )
 
@@ -58,6 +58,10 @@ func TestHMGet(t *testing.T) {
 	runCase(t, new(redis.Cases).HMGet())
 }
 
+func TestHScan(t *testing.T) {
+	runCase(t, new(redis.Cases).HScan())
+}
+
 func TestHSet(t *testing.T) {
 	runCase(t, new(redis.Cases).HSet())
 }
@@ -62,6 +62,10 @@ func TestPTTL(t *testing.T) {
 	runCase(t, new(redis.Cases).PTTL())
 }
 
+func TestRandomKey(t *testing.T) {
+	runCase(t, new(redis.Cases).RandomKey())
+}
+
 func TestRename(t *testing.T) {
 	runCase(t, new(redis.Cases).Rename())
 }
@@ -70,6 +74,10 @@ func TestRenameNX(t *testing.T) {
 	runCase(t, new(redis.Cases).RenameNX())
 }
 
+func TestScan(t *testing.T) {
+	runCase(t, new(redis.Cases).Scan())
+}
+
 func TestTouch(t *testing.T) {
 	runCase(t, new(redis.Cases).Touch())
 }
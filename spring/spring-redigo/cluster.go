@@ -0,0 +1,280 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package SpringRedigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-spring/spring-core/redis"
+	g "github.com/gomodule/redigo/redis"
+)
+
+// clusterDriver routes commands to the nodes owning the hash slot of their
+// first keys, the slot table is loaded by CLUSTER SLOTS and refreshed when
+// a MOVED redirect is received. Commands without keys are sent to a random
+// node.
+type clusterDriver struct {
+	config redis.Config
+	mutex  sync.RWMutex
+	pools  map[string]*g.Pool
+	slots  [redis.SlotCount][]string // master first, then replicas.
+}
+
+func openCluster(config redis.Config) (redis.Driver, error) {
+	if config.Cluster.MaxRedirects == 0 {
+		config.Cluster.MaxRedirects = 3
+	}
+	d := &clusterDriver{
+		config: config,
+		pools:  make(map[string]*g.Pool),
+	}
+	if err := d.refresh(config.Cluster.Addrs); err != nil {
+		return nil, err
+	}
+	if config.Ping {
+		if _, err := d.do(context.Background(), d.randomAddr(), false, []interface{}{"PING"}); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// readFromReplica returns whether read-only commands can be sent to replicas.
+func (d *clusterDriver) readFromReplica() bool {
+	r := d.config.Cluster.ReadFrom
+	return r == redis.ReadFromReplica || r == redis.ReadFromRandom
+}
+
+// getPool returns the connection pool of the node, creates it if not exists.
+func (d *clusterDriver) getPool(addr string) *g.Pool {
+	d.mutex.RLock()
+	p, ok := d.pools[addr]
+	d.mutex.RUnlock()
+	if ok {
+		return p
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if p, ok = d.pools[addr]; ok {
+		return p
+	}
+	p = &g.Pool{
+		Dial: func() (g.Conn, error) {
+			conn, err := g.Dial("tcp", addr, dialOptions(d.config)...)
+			if err != nil {
+				return nil, err
+			}
+			if d.readFromReplica() {
+				if _, err = conn.Do("READONLY"); err != nil {
+					_ = conn.Close()
+					return nil, err
+				}
+			}
+			return conn, nil
+		},
+		MaxIdle:     d.config.MaxIdle,
+		MaxActive:   d.config.MaxActive,
+		Wait:        d.config.MaxActive > 0,
+		IdleTimeout: time.Duration(d.config.IdleTimeout) * time.Millisecond,
+	}
+	d.pools[addr] = p
+	return p
+}
+
+// refresh loads the slot table from the first available node of addrs.
+func (d *clusterDriver) refresh(addrs []string) error {
+	var lastErr error
+	for _, addr := range addrs {
+		slots, err := d.loadSlots(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		d.mutex.Lock()
+		d.slots = *slots
+		d.mutex.Unlock()
+		return nil
+	}
+	return fmt.Errorf("redis: no cluster node is available: %v", lastErr)
+}
+
+func (d *clusterDriver) loadSlots(addr string) (*[redis.SlotCount][]string, error) {
+	conn := d.getPool(addr).Get()
+	defer conn.Close()
+	ranges, err := g.Values(conn.Do("CLUSTER", "SLOTS"))
+	if err != nil {
+		return nil, err
+	}
+	var slots [redis.SlotCount][]string
+	for _, v := range ranges {
+		r, err := g.Values(v, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(r) < 3 {
+			return nil, errors.New("redis: invalid cluster slots")
+		}
+		start, err := g.Int(r[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		end, err := g.Int(r[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		if start < 0 || end >= redis.SlotCount || start > end {
+			return nil, fmt.Errorf("redis: invalid slot range %d-%d", start, end)
+		}
+		var nodes []string
+		for _, n := range r[2:] {
+			node, err := g.Values(n, nil)
+			if err != nil {
+				return nil, err
+			}
+			if len(node) < 2 {
+				return nil, errors.New("redis: invalid cluster node")
+			}
+			host, err := g.String(node[0], nil)
+			if err != nil {
+				return nil, err
+			}
+			port, err := g.Int(node[1], nil)
+			if err != nil {
+				return nil, err
+			}
+			if host == "" {
+				host, _, _ = net.SplitHostPort(addr)
+			}
+			nodes = append(nodes, net.JoinHostPort(host, strconv.Itoa(port)))
+		}
+		for i := start; i <= end; i++ {
+			slots[i] = nodes
+		}
+	}
+	return &slots, nil
+}
+
+// knownAddrs returns the masters in the slot table and the seed nodes.
+func (d *clusterDriver) knownAddrs() []string {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	var addrs []string
+	seen := make(map[string]bool)
+	for _, nodes := range d.slots {
+		if len(nodes) > 0 && !seen[nodes[0]] {
+			seen[nodes[0]] = true
+			addrs = append(addrs, nodes[0])
+		}
+	}
+	for _, addr := range d.config.Cluster.Addrs {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+func (d *clusterDriver) randomAddr() string {
+	addrs := d.knownAddrs()
+	return addrs[rand.Intn(len(addrs))]
+}
+
+// pickAddr returns the address of the node to which the command is sent.
+func (d *clusterDriver) pickAddr(args []interface{}) (string, error) {
+	key, ok := redis.CommandKey(args)
+	if !ok {
+		return d.randomAddr(), nil
+	}
+	slot := redis.HashSlot(key)
+	d.mutex.RLock()
+	nodes := d.slots[slot]
+	d.mutex.RUnlock()
+	if len(nodes) == 0 {
+		return "", fmt.Errorf("redis: slot %d is not served", slot)
+	}
+	if d.readFromReplica() && redis.IsReadOnlyCommand(args) {
+		switch d.config.Cluster.ReadFrom {
+		case redis.ReadFromReplica:
+			if len(nodes) > 1 {
+				return nodes[1+rand.Intn(len(nodes)-1)], nil
+			}
+		case redis.ReadFromRandom:
+			return nodes[rand.Intn(len(nodes))], nil
+		}
+	}
+	return nodes[0], nil
+}
+
+func (d *clusterDriver) do(ctx context.Context, addr string, asking bool, args []interface{}) (interface{}, error) {
+	conn, err := d.getPool(addr).GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if asking {
+		if _, err = conn.Do("ASKING"); err != nil {
+			return nil, err
+		}
+	}
+	return conn.Do(args[0].(string), args[1:]...)
+}
+
+// redirect parses the MOVED and ASK errors, returns the kind and the address.
+func redirect(err error) (string, string, bool) {
+	e, ok := err.(g.Error)
+	if !ok {
+		return "", "", false
+	}
+	s := strings.Fields(string(e))
+	if len(s) != 3 || (s[0] != "MOVED" && s[0] != "ASK") {
+		return "", "", false
+	}
+	return s[0], s[2], true
+}
+
+func (d *clusterDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	addr, err := d.pickAddr(args)
+	if err != nil {
+		if err = d.refresh(d.knownAddrs()); err != nil {
+			return nil, err
+		}
+		if addr, err = d.pickAddr(args); err != nil {
+			return nil, err
+		}
+	}
+	asking := false
+	for i := 0; ; i++ {
+		result, err := d.do(ctx, addr, asking, args)
+		kind, target, ok := redirect(err)
+		if !ok || i >= d.config.Cluster.MaxRedirects {
+			return toResult(result, err)
+		}
+		if kind == "MOVED" {
+			_ = d.refresh(append([]string{target}, d.knownAddrs()...))
+		}
+		addr, asking = target, kind == "ASK"
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package SpringRedigo
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-spring/spring-core/redis"
+	g "github.com/gomodule/redigo/redis"
+)
+
+// subscription receives the messages by a dedicated connection, the
+// connection is closed when the subscription is closed.
+type subscription struct {
+	conn g.PubSubConn
+	ch   chan *redis.Message
+	once sync.Once
+}
+
+// subscribe subscribes the channels by conn, and waits for the confirmations.
+func subscribe(conn g.Conn, channels []string) (redis.Subscription, error) {
+	args := make([]interface{}, len(channels))
+	for i, c := range channels {
+		args[i] = c
+	}
+	s := &subscription{
+		conn: g.PubSubConn{Conn: conn},
+		ch:   make(chan *redis.Message, 100),
+	}
+	if err := s.conn.Subscribe(args...); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	for range channels {
+		if err, ok := s.conn.ReceiveWithTimeout(0).(error); ok {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+	go s.run()
+	return s, nil
+}
+
+func (s *subscription) run() {
+	defer close(s.ch)
+	for {
+		switch v := s.conn.ReceiveWithTimeout(0).(type) {
+		case g.Message:
+			s.ch <- &redis.Message{Channel: v.Channel, Payload: string(v.Data)}
+		case error:
+			return
+		}
+	}
+}
+
+func (s *subscription) Channel() <-chan *redis.Message {
+	return s.ch
+}
+
+func (s *subscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		err = s.conn.Close()
+	})
+	return err
+}
+
+// Subscribe subscribes the channels by a new connection.
+func (c *Driver) Subscribe(ctx context.Context, channels ...string) (redis.Subscription, error) {
+	conn, err := g.DialContext(ctx, "tcp", c.address, dialOptions(c.config)...)
+	if err != nil {
+		return nil, err
+	}
+	return subscribe(conn, channels)
+}
+
+// Subscribe subscribes the channels by a new connection to the master.
+func (d *sentinelDriver) Subscribe(ctx context.Context, channels ...string) (redis.Subscription, error) {
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	return subscribe(conn, channels)
+}
+
+// Subscribe subscribes the channels by a new connection to a random node,
+// the messages are broadcast to all nodes of the cluster.
+func (d *clusterDriver) Subscribe(ctx context.Context, channels ...string) (redis.Subscription, error) {
+	conn, err := g.DialContext(ctx, "tcp", d.randomAddr(), dialOptions(d.config)...)
+	if err != nil {
+		return nil, err
+	}
+	return subscribe(conn, channels)
+}
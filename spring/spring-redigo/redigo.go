@@ -35,14 +35,19 @@ func NewClient(config redis.Config) (*redis.Client, error) {
 
 func Open(config redis.Config) (redis.Driver, error) {
 
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch config.Mode {
+	case redis.ModeCluster:
+		return openCluster(config)
+	case redis.ModeSentinel:
+		return openSentinel(config)
+	}
+
 	address := fmt.Sprintf("%s:%d", config.Host, config.Port)
-	conn, err := g.Dial("tcp", address,
-		g.DialUsername(config.Username),
-		g.DialPassword(config.Password),
-		g.DialDatabase(config.Database),
-		g.DialConnectTimeout(time.Duration(config.ConnectTimeout)*time.Millisecond),
-		g.DialReadTimeout(time.Duration(config.ReadTimeout)*time.Millisecond),
-		g.DialWriteTimeout(time.Duration(config.WriteTimeout)*time.Millisecond))
+	conn, err := g.Dial("tcp", address, dialOptions(config)...)
 	if err != nil {
 		return nil, err
 	}
@@ -53,30 +58,49 @@ func Open(config redis.Config) (redis.Driver, error) {
 		}
 	}
 
-	return &Driver{conn: conn}, nil
+	return &Driver{conn: conn, address: address, config: config}, nil
+}
+
+func dialOptions(config redis.Config) []g.DialOption {
+	return []g.DialOption{
+		g.DialUsername(config.Username),
+		g.DialPassword(config.Password),
+		g.DialDatabase(config.Database),
+		g.DialConnectTimeout(time.Duration(config.ConnectTimeout) * time.Millisecond),
+		g.DialReadTimeout(time.Duration(config.ReadTimeout) * time.Millisecond),
+		g.DialWriteTimeout(time.Duration(config.WriteTimeout) * time.Millisecond),
+	}
 }
 
 type Driver struct {
-	conn g.Conn
+	conn    g.Conn
+	address string
+	config  redis.Config
 }
 
 func (c *Driver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
-	result, err := c.conn.Do(args[0].(string), args[1:]...)
+	return toResult(c.conn.Do(args[0].(string), args[1:]...))
+}
+
+func toResult(result interface{}, err error) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
 	if result == nil {
 		return nil, redis.ErrNil()
 	}
+	return toString(result), nil
+}
+
+// toString converts the bulk strings to strings, nested arrays included.
+func toString(result interface{}) interface{} {
 	switch r := result.(type) {
 	case []byte:
-		return string(r), nil
+		return string(r)
 	case []interface{}:
 		for i := 0; i < len(r); i++ {
-			if s, ok := r[i].([]byte); ok {
-				r[i] = string(s)
-			}
+			r[i] = toString(r[i])
 		}
 	}
-	return result, nil
+	return result
 }
@@ -0,0 +1,439 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package SpringRedigo_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/redis"
+	SpringRedigo "github.com/go-spring/spring-redigo"
+)
+
+// fakeConn is the state of a connection of fakeServer.
+type fakeConn struct {
+	asking bool
+}
+
+// fakeServer is an in-process redis server speaking RESP, the replies are
+// generated by the handler.
+type fakeServer struct {
+	l       net.Listener
+	handler func(c *fakeConn, args []string) interface{}
+}
+
+func newFakeServer(t *testing.T, handler func(c *fakeConn, args []string) interface{}) *fakeServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeServer{l: l, handler: handler}
+	go s.serve()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+func (s *fakeServer) Addr() string {
+	return s.l.Addr().String()
+}
+
+func (s *fakeServer) HostPort() (string, int) {
+	addr := s.l.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func (s *fakeServer) serve() {
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeServer) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	c := &fakeConn{}
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		var reply interface{}
+		switch strings.ToUpper(args[0]) {
+		case "PING":
+			reply = "PONG"
+		case "ASKING":
+			c.asking = true
+			reply = "OK"
+		default:
+			reply = s.handler(c, args)
+			c.asking = false
+		}
+		writeReply(w, reply)
+		if err = w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := 0; i < n; i++ {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, size+2)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+	return args, nil
+}
+
+// multiReply is written as several replies, it's used to push the messages
+// after the confirmation of SUBSCRIBE.
+type multiReply []interface{}
+
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case multiReply:
+		for _, e := range v {
+			writeReply(w, e)
+		}
+	case nil:
+		w.WriteString("$-1\r\n")
+	case error:
+		w.WriteString("-" + v.Error() + "\r\n")
+	case int:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case string:
+		w.WriteString("+" + v + "\r\n")
+	case []byte:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, e := range v {
+			writeReply(w, e)
+		}
+	}
+}
+
+func TestOpen_Validate(t *testing.T) {
+	_, err := SpringRedigo.Open(redis.Config{Mode: redis.ModeCluster})
+	assert.Error(t, err, "redis: cluster addrs is empty")
+	_, err = SpringRedigo.Open(redis.Config{Mode: redis.ModeSentinel})
+	assert.Error(t, err, "redis: sentinel master name is empty")
+}
+
+func TestSentinel(t *testing.T) {
+
+	master := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		if args[0] == "GET" && args[1] == "mykey" {
+			return []byte("master")
+		}
+		return fmt.Errorf("ERR unknown command '%s'", args[0])
+	})
+
+	host, port := master.HostPort()
+	sentinel := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		if args[0] == "SENTINEL" && args[2] == "mymaster" {
+			return []interface{}{[]byte(host), []byte(strconv.Itoa(port))}
+		}
+		return nil
+	})
+
+	d, err := SpringRedigo.Open(redis.Config{
+		Mode: redis.ModeSentinel,
+		Ping: true,
+		Sentinel: redis.SentinelConfig{
+			MasterName: "mymaster",
+			Addrs:      []string{"127.0.0.1:1", sentinel.Addr()},
+		},
+	})
+	assert.Nil(t, err)
+
+	c := redis.NewClient(d)
+	r, err := c.Get(context.Background(), "mykey")
+	assert.Nil(t, err)
+	assert.Equal(t, r, "master")
+
+	_, err = SpringRedigo.Open(redis.Config{
+		Mode: redis.ModeSentinel,
+		Ping: true,
+		Sentinel: redis.SentinelConfig{
+			MasterName: "unknown",
+			Addrs:      []string{sentinel.Addr()},
+		},
+	})
+	assert.Error(t, err, "redis: master unknown not found")
+}
+
+func TestSentinel_PoolReuse(t *testing.T) {
+
+	master := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		return []byte("master")
+	})
+
+	var resolved int32
+	host, port := master.HostPort()
+	sentinel := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		atomic.AddInt32(&resolved, 1)
+		return []interface{}{[]byte(host), []byte(strconv.Itoa(port))}
+	})
+
+	d, err := SpringRedigo.Open(redis.Config{
+		Mode:    redis.ModeSentinel,
+		Ping:    true,
+		MaxIdle: 1,
+		Sentinel: redis.SentinelConfig{
+			MasterName: "mymaster",
+			Addrs:      []string{sentinel.Addr()},
+		},
+	})
+	assert.Nil(t, err)
+
+	// the idle connection is reused, so the master is resolved only once.
+	c := redis.NewClient(d)
+	for i := 0; i < 3; i++ {
+		_, err = c.Get(context.Background(), "mykey")
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, atomic.LoadInt32(&resolved), int32(1))
+}
+
+func TestSentinel_Failover(t *testing.T) {
+
+	var demoted int32
+	oldMaster := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		if atomic.LoadInt32(&demoted) == 1 {
+			return errors.New("READONLY You can't write against a read only replica.")
+		}
+		return "OK"
+	})
+	newMaster := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		return "OK"
+	})
+
+	sentinel := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		host, port := oldMaster.HostPort()
+		if atomic.LoadInt32(&demoted) == 1 {
+			host, port = newMaster.HostPort()
+		}
+		return []interface{}{[]byte(host), []byte(strconv.Itoa(port))}
+	})
+
+	d, err := SpringRedigo.Open(redis.Config{
+		Mode:    redis.ModeSentinel,
+		Ping:    true,
+		MaxIdle: 1,
+		Sentinel: redis.SentinelConfig{
+			MasterName: "mymaster",
+			Addrs:      []string{sentinel.Addr()},
+		},
+	})
+	assert.Nil(t, err)
+
+	c := redis.NewClient(d)
+	ctx := context.Background()
+	_, err = c.Set(ctx, "mykey", "a")
+	assert.Nil(t, err)
+
+	// the pooled connection to the demoted master is discarded after it
+	// replies READONLY, and the next connection is dialed to the new master.
+	atomic.StoreInt32(&demoted, 1)
+	_, err = c.Set(ctx, "mykey", "b")
+	assert.Error(t, err, "READONLY")
+	_, err = c.Set(ctx, "mykey", "c")
+	assert.Nil(t, err)
+}
+
+func TestSentinel_RoleCheck(t *testing.T) {
+
+	var demoted int32
+	oldMaster := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		if args[0] == "ROLE" && atomic.LoadInt32(&demoted) == 1 {
+			return []interface{}{[]byte("slave")}
+		}
+		return []byte("old")
+	})
+	newMaster := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		return []byte("new")
+	})
+
+	sentinel := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		host, port := oldMaster.HostPort()
+		if atomic.LoadInt32(&demoted) == 1 {
+			host, port = newMaster.HostPort()
+		}
+		return []interface{}{[]byte(host), []byte(strconv.Itoa(port))}
+	})
+
+	d, err := SpringRedigo.Open(redis.Config{
+		Mode:    redis.ModeSentinel,
+		Ping:    true,
+		MaxIdle: 1,
+		Sentinel: redis.SentinelConfig{
+			MasterName: "mymaster",
+			Addrs:      []string{sentinel.Addr()},
+		},
+	})
+	assert.Nil(t, err)
+
+	c := redis.NewClient(d)
+	ctx := context.Background()
+	r, err := c.Get(ctx, "mykey")
+	assert.Nil(t, err)
+	assert.Equal(t, r, "old")
+
+	// the role of the connection idle for a while is checked when borrowed.
+	atomic.StoreInt32(&demoted, 1)
+	time.Sleep(1100 * time.Millisecond)
+	r, err = c.Get(ctx, "mykey")
+	assert.Nil(t, err)
+	assert.Equal(t, r, "new")
+}
+
+func TestCluster(t *testing.T) {
+
+	var nodeA, nodeB *fakeServer
+	slots := func(c *fakeConn, args []string) interface{} {
+		hostA, portA := nodeA.HostPort()
+		hostB, portB := nodeB.HostPort()
+		return []interface{}{
+			[]interface{}{0, 8191, []interface{}{[]byte(hostA), portA}},
+			[]interface{}{8192, 16383, []interface{}{[]byte(hostB), portB}},
+		}
+	}
+
+	// foo is served by B, bar, {bar}moved and {bar}ask are served by A.
+	assert.Equal(t, redis.HashSlot("foo"), 12182)
+	assert.True(t, redis.HashSlot("bar") < 8192)
+
+	nodeA = newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		switch args[0] {
+		case "CLUSTER":
+			return slots(c, args)
+		case "GET":
+			slot := redis.HashSlot(args[1])
+			switch args[1] {
+			case "{bar}moved":
+				return fmt.Errorf("MOVED %d %s", slot, nodeB.Addr())
+			case "{bar}ask":
+				return fmt.Errorf("ASK %d %s", slot, nodeB.Addr())
+			}
+			return []byte("A")
+		}
+		return nil
+	})
+
+	nodeB = newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		switch args[0] {
+		case "CLUSTER":
+			return slots(c, args)
+		case "GET":
+			slot := redis.HashSlot(args[1])
+			if slot < 8192 && args[1] == "{bar}ask" && !c.asking {
+				return fmt.Errorf("MOVED %d %s", slot, nodeA.Addr())
+			}
+			return []byte("B")
+		}
+		return nil
+	})
+
+	d, err := SpringRedigo.Open(redis.Config{
+		Mode: redis.ModeCluster,
+		Ping: true,
+		Cluster: redis.ClusterConfig{
+			Addrs:        []string{nodeA.Addr()},
+			MaxRedirects: 3,
+		},
+	})
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	c := redis.NewClient(d)
+
+	testcases := []struct {
+		key    string
+		expect string
+	}{
+		{"bar", "A"},
+		{"foo", "B"},
+		{"{bar}moved", "B"},
+		{"{bar}ask", "B"},
+	}
+	for _, tc := range testcases {
+		r, err := c.Get(ctx, tc.key)
+		assert.Nil(t, err)
+		assert.Equal(t, r, tc.expect)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+
+	s := newFakeServer(t, func(c *fakeConn, args []string) interface{} {
+		if args[0] == "SUBSCRIBE" {
+			return multiReply{
+				[]interface{}{[]byte("subscribe"), []byte(args[1]), 1},
+				[]interface{}{[]byte("message"), []byte(args[1]), []byte("hello")},
+			}
+		}
+		return nil
+	})
+
+	host, port := s.HostPort()
+	d, err := SpringRedigo.Open(redis.Config{Host: host, Port: port})
+	assert.Nil(t, err)
+
+	c := redis.NewClient(d)
+	sub, err := c.Subscribe(context.Background(), "news")
+	assert.Nil(t, err)
+
+	msg := <-sub.Channel()
+	assert.Equal(t, msg, &redis.Message{Channel: "news", Payload: "hello"})
+
+	assert.Nil(t, sub.Close())
+	_, ok := <-sub.Channel()
+	assert.False(t, ok)
+}
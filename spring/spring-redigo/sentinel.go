@@ -0,0 +1,169 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package SpringRedigo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-spring/spring-core/redis"
+	g "github.com/gomodule/redigo/redis"
+)
+
+// roleCheckIdle is how long a pooled connection is idle before its role is
+// checked when it's borrowed again.
+const roleCheckIdle = time.Second
+
+// sentinelDriver sends commands to the master resolved by sentinels, the
+// master is resolved again when a new connection is dialed, so the driver
+// follows the failover after the connections to the old master are discarded
+// by the pool, which happens when they are broken, when they reply READONLY,
+// or when they aren't connected to a master after being idle.
+type sentinelDriver struct {
+	config redis.Config
+	pool   *g.Pool
+}
+
+func openSentinel(config redis.Config) (redis.Driver, error) {
+	d := &sentinelDriver{config: config}
+	d.pool = &g.Pool{
+		Dial:         d.dialPooled,
+		TestOnBorrow: testRole,
+		MaxIdle:      config.MaxIdle,
+		MaxActive:    config.MaxActive,
+		Wait:         config.MaxActive > 0,
+		IdleTimeout:  time.Duration(config.IdleTimeout) * time.Millisecond,
+	}
+	if config.Ping {
+		conn := d.pool.Get()
+		defer conn.Close()
+		if _, err := conn.Do("PING"); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (d *sentinelDriver) dial() (g.Conn, error) {
+	addr, err := d.masterAddr()
+	if err != nil {
+		return nil, err
+	}
+	return g.Dial("tcp", addr, dialOptions(d.config)...)
+}
+
+// dialPooled dials a connection to the master for the pool.
+func (d *sentinelDriver) dialPooled() (g.Conn, error) {
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	return &masterConn{Conn: conn}, nil
+}
+
+// testRole checks that the connection idle for a while is still connected to
+// a master, otherwise it's discarded by the pool.
+func testRole(c g.Conn, t time.Time) error {
+	if time.Since(t) < roleCheckIdle {
+		return nil
+	}
+	r, err := g.Values(c.Do("ROLE"))
+	if err != nil {
+		return err
+	}
+	if len(r) == 0 {
+		return errors.New("redis: invalid role reply")
+	}
+	role, err := g.String(r[0], nil)
+	if err != nil {
+		return err
+	}
+	if role != "master" {
+		return fmt.Errorf("redis: role is %s, not master", role)
+	}
+	return nil
+}
+
+// masterConn is a connection to the master, it's discarded by the pool after
+// it replies READONLY, which means the master is demoted by a failover.
+type masterConn struct {
+	g.Conn
+	readonly bool
+}
+
+func (c *masterConn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	r, err := c.Conn.Do(cmd, args...)
+	if e, ok := err.(g.Error); ok && strings.HasPrefix(string(e), "READONLY") {
+		c.readonly = true
+	}
+	return r, err
+}
+
+func (c *masterConn) Err() error {
+	if c.readonly {
+		return errors.New("redis: connected to a replica")
+	}
+	return c.Conn.Err()
+}
+
+// masterAddr asks the sentinels in order for the address of the master.
+func (d *sentinelDriver) masterAddr() (string, error) {
+	s := d.config.Sentinel
+	opts := []g.DialOption{
+		g.DialUsername(s.Username),
+		g.DialPassword(s.Password),
+		g.DialConnectTimeout(time.Duration(d.config.ConnectTimeout) * time.Millisecond),
+		g.DialReadTimeout(time.Duration(d.config.ReadTimeout) * time.Millisecond),
+		g.DialWriteTimeout(time.Duration(d.config.WriteTimeout) * time.Millisecond),
+	}
+	var lastErr error
+	for _, addr := range s.Addrs {
+		conn, err := g.Dial("tcp", addr, opts...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		r, err := g.Strings(conn.Do("SENTINEL", "get-master-addr-by-name", s.MasterName))
+		_ = conn.Close()
+		if err == g.ErrNil {
+			err = fmt.Errorf("redis: master %s not found", s.MasterName)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if len(r) != 2 {
+			lastErr = errors.New("redis: invalid master address")
+			continue
+		}
+		return net.JoinHostPort(r[0], r[1]), nil
+	}
+	return "", fmt.Errorf("redis: no sentinel is available: %v", lastErr)
+}
+
+func (d *sentinelDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return toResult(conn.Do(args[0].(string), args[1:]...))
+}
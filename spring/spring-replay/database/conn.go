@@ -16,402 +16,577 @@
 
 package database
 
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+
+	"github.com/go-spring/spring-replay/recorder"
+	"github.com/go-spring/spring-replay/replayer"
+)
+
+var (
+	_ driver.DriverContext      = (*Driver)(nil)
+	_ driver.Connector          = (*Connector)(nil)
+	_ driver.ExecerContext      = (*Conn)(nil)
+	_ driver.QueryerContext     = (*Conn)(nil)
+	_ driver.ConnPrepareContext = (*Conn)(nil)
+	_ driver.ConnBeginTx        = (*Conn)(nil)
+	_ driver.StmtExecContext    = (*Stmt)(nil)
+	_ driver.StmtQueryContext   = (*Stmt)(nil)
+
+	_ driver.RowsNextResultSet              = (*recordRows)(nil)
+	_ driver.RowsColumnTypeScanType         = (*recordRows)(nil)
+	_ driver.RowsColumnTypeDatabaseTypeName = (*recordRows)(nil)
+	_ driver.RowsColumnTypeLength           = (*recordRows)(nil)
+	_ driver.RowsColumnTypeNullable         = (*recordRows)(nil)
+	_ driver.RowsColumnTypePrecisionScale   = (*recordRows)(nil)
+)
+
+// errNoRecord 是回放模式下没有匹配的录制数据时返回的错误。
+var errNoRecord = errors.New("no recorded data found")
+
+// Driver 包装 driver.Driver ，录制模式下录制 SQL 语句、参数、结果和错误，回放
+// 模式下使用会话中录制的数据作为 SQL 语句的结果，并且不会访问真实的数据库，
+// 事务和预处理语句什么也不做，没有匹配的数据或者不在回放会话中时返回错误。
+type Driver struct {
+	driver driver.Driver
+}
+
+// NewDriver 返回包装 d 的 driver.Driver ，用法如下：
 //
-//import (
-//	"context"
-//	"database/sql/driver"
-//	"encoding/json"
-//	"errors"
-//	"io"
-//	"unsafe"
-//
-//	"github.com/go-spring/spring-base/clock"
-//	"github.com/go-spring/spring-base/net/recorder"
-//	"github.com/go-spring/spring-base/net/replayer"
-//)
-//
-//var (
-//	errContextDriver = errors.New("please use context driver")
-//	errContextMethod = errors.New("please use context method")
-//)
-//
-//type sqlKeyType int
-//
-//var sqlKey sqlKeyType
-//
-//func GetSQL(ctx context.Context) (string, bool) {
-//	v := ctx.Value(sqlKey)
-//	if v == nil {
-//		return "", false
-//	}
-//	return v.(string), true
-//}
-//
-//func SaveSQL(ctx context.Context, sql string) context.Context {
-//	return context.WithValue(ctx, sqlKey, sql)
-//}
-//
-//type Driver struct {
-//	driver driver.Driver
-//}
-//
-//func NewDriver(driver driver.Driver) driver.Driver {
-//	return &Driver{driver: driver}
-//}
-//
-//func (d *Driver) Open(name string) (driver.Conn, error) {
-//	connector, err := d.OpenConnector(name)
-//	if err != nil {
-//		if err == errContextDriver {
-//			var conn driver.Conn
-//			conn, err = d.driver.Open(name)
-//			if err != nil {
-//				return nil, err
-//			}
-//			return &Conn{conn}, nil
-//		}
-//		return nil, err
-//	}
-//	return connector.Connect(context.Background())
-//}
-//
-//func (d *Driver) OpenConnector(name string) (driver.Connector, error) {
-//	ciCtx, ok := d.driver.(driver.DriverContext)
-//	if !ok {
-//		return nil, errContextDriver
-//	}
-//	connector, err := ciCtx.OpenConnector(name)
-//	if err != nil {
-//		return nil, err
-//	}
-//	return &Connector{connector: connector}, nil
-//}
-//
-//type Connector struct {
-//	connector driver.Connector
-//}
-//
-//func (c *Connector) Driver() driver.Driver {
-//	return c.connector.Driver()
-//}
-//
-//func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
-//	conn, err := c.connector.Connect(ctx)
-//	if err != nil {
-//		return nil, err
-//	}
-//	return &Conn{conn}, nil
-//}
-//
-//type Conn struct {
-//	conn driver.Conn
-//}
-//
-//func (c *Conn) Close() error {
-//	return c.conn.Close()
-//}
-//
-//func (c *Conn) Prepare(query string) (driver.Stmt, error) {
-//	stmt, err := c.PrepareContext(context.Background(), query)
-//	if err != nil {
-//		if err == errContextDriver {
-//			stmt, err = c.conn.Prepare(query)
-//			if err != nil {
-//				return nil, err
-//			}
-//			return newStmt(stmt), nil
-//		}
-//		return nil, err
-//	}
-//	return stmt, nil
-//}
-//
-//func (c *Conn) Begin() (driver.Tx, error) {
-//	tx, err := c.BeginTx(context.Background(), driver.TxOptions{})
-//	if err != nil {
-//		if err == errContextDriver {
-//			return c.conn.Begin()
-//		}
-//		return nil, err
-//	}
-//	return tx, nil
-//}
-//
-//func (c *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
-//	ciCtx, ok := c.conn.(driver.ConnPrepareContext)
-//	if !ok {
-//		return nil, errContextDriver
-//	}
-//	stmt, err := ciCtx.PrepareContext(ctx, query)
-//	if err != nil {
-//		return nil, err
-//	}
-//	return newStmt(stmt), nil
-//}
-//
-//func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
-//	ciCtx, ok := c.conn.(driver.ConnBeginTx)
-//	if !ok {
-//		return nil, errContextDriver
-//	}
-//	return ciCtx.BeginTx(ctx, opts)
-//}
-//
-//func newStmt(stmt driver.Stmt) driver.Stmt {
-//	if replayer.ReplayMode() {
-//		stmt = &replayStmt{baseStmt{stmt}}
-//	}
-//	if recorder.RecordMode() {
-//		stmt = &recordStmt{baseStmt{stmt}}
-//	}
-//	return stmt
-//}
-//
-//type baseStmt struct {
-//	stmt driver.Stmt
-//}
-//
-//func (c *baseStmt) Close() error {
-//	return c.stmt.Close()
-//}
-//
-//func (c *baseStmt) NumInput() int {
-//	return c.stmt.NumInput()
-//}
-//
-//func (c *baseStmt) Exec(args []driver.Value) (driver.Result, error) {
-//	if recorder.RecordMode() || replayer.ReplayMode() {
-//		return nil, errContextMethod
-//	}
-//	return c.stmt.Exec(args)
-//}
-//
-//func (c *baseStmt) Query(args []driver.Value) (driver.Rows, error) {
-//	if recorder.RecordMode() || replayer.ReplayMode() {
-//		return nil, errContextMethod
-//	}
-//	return c.stmt.Query(args)
-//}
-//
-//type recordStmt struct {
-//	baseStmt
-//}
-//
-//func (c *recordStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-//	siCtx, ok := c.stmt.(driver.StmtExecContext)
-//	if !ok {
-//		return nil, errContextDriver
-//	}
-//	result, err := siCtx.ExecContext(ctx, args)
-//	if err != nil {
-//		return nil, err
-//	}
-//	if recorder.RecordMode() {
-//		var sql string
-//		sql, ok = GetSQL(ctx)
-//		if !ok {
-//			return nil, errors.New("no sql found")
-//		}
-//		lastInsertId, _ := result.LastInsertId()
-//		rowsAffected, _ := result.RowsAffected()
-//		recorder.RecordAction(ctx, &recorder.Action{
-//			Protocol: recorder.SQL,
-//			Request:  sql,
-//			Response: Result{
-//				TheLastInsertId: lastInsertId,
-//				TheRowsAffected: rowsAffected,
-//			},
-//			Timestamp: clock.Now(ctx).UnixNano(),
-//		})
-//	}
-//	return result, nil
-//}
-//
-//func (c *recordStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-//	siCtx, ok := c.stmt.(driver.StmtQueryContext)
-//	if !ok {
-//		return nil, errContextDriver
-//	}
-//	rows, err := siCtx.QueryContext(ctx, args)
-//	if err != nil {
-//		return nil, err
-//	}
-//	return &recordRows{ctx: ctx, rows: rows}, nil
-//}
-//
-//type replayStmt struct {
-//	baseStmt
-//}
-//
-//func (c *replayStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
-//	sql, ok := GetSQL(ctx)
-//	if !ok {
-//		return nil, errors.New("no sql found")
-//	}
-//	action := &recorder.Action{
-//		Protocol: recorder.SQL,
-//		Request:  sql,
-//	}
-//	ok, err := recorder.ReplayAction(ctx, action, func(r1, r2 interface{}) bool {
-//		return r1 == r2
-//	})
-//	if err != nil {
-//		return nil, err
-//	}
-//	data, ok := action.Response.(json.RawMessage)
-//	if !ok {
-//		return nil, errors.New("error response type")
-//	}
-//	var r Result
-//	if err = json.Unmarshal(data, &r); err != nil {
-//		return nil, err
-//	}
-//	return r, nil
-//}
-//
-//func (c *replayStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
-//	siCtx, ok := c.stmt.(driver.StmtQueryContext)
-//	if !ok {
-//		return nil, errContextDriver
-//	}
-//	rows, err := siCtx.QueryContext(ctx, args)
-//	if err != nil {
-//		return nil, err
-//	}
-//	rrs := &replayRows{ctx: ctx, rows: rows}
-//	if err = rrs.init(); err != nil {
-//		return nil, err
-//	}
-//	return rrs, nil
-//}
-//
-//type Result struct {
-//	TheLastInsertId int64 `json:"last_insert_id"`
-//	TheRowsAffected int64 `json:"rows_affected"`
-//}
-//
-//func (r Result) LastInsertId() (int64, error) {
-//	return r.TheLastInsertId, nil
-//}
-//
-//func (r Result) RowsAffected() (int64, error) {
-//	return r.TheRowsAffected, nil
-//}
-//
-//type recordRows struct {
-//	ctx  context.Context
-//	rows driver.Rows
-//	data struct {
-//		Cols []string
-//		Rows [][]driver.Value
-//	}
-//}
-//
-//func (r *recordRows) Columns() []string {
-//	return r.rows.Columns()
-//}
-//
-//func (r *recordRows) Close() error {
-//	return r.rows.Close()
-//}
-//
-//func (r *recordRows) Next(dest []driver.Value) error {
-//	if err := r.rows.Next(dest); err != nil {
-//		if err == io.EOF && recorder.RecordMode() {
-//			sql, ok := GetSQL(r.ctx)
-//			if !ok {
-//				return errors.New("no sql found")
-//			}
-//			r.data.Cols = r.Columns()
-//			recorder.RecordAction(r.ctx, &recorder.Action{
-//				Protocol:  recorder.SQL,
-//				Request:   sql,
-//				Response:  r.data,
-//				Timestamp: clock.Now(r.ctx).UnixNano(),
-//			})
-//		}
-//		return err
-//	}
-//	if recorder.RecordMode() {
-//		cols := make([]driver.Value, len(dest))
-//		for i := range dest {
-//			err := convertAssign(&cols[i], dest[i])
-//			if err != nil {
-//				return err
-//			}
-//			switch v := cols[i].(type) {
-//			case []byte:
-//				v = append([]byte("[]byte@@"), v...)
-//				cols[i] = bytesToString(v)
-//			}
-//		}
-//		r.data.Rows = append(r.data.Rows, cols)
-//	}
-//	return nil
-//}
-//
-////func stringToBytes(s string) []byte {
-////	return *(*[]byte)(unsafe.Pointer(
-////		&struct {
-////			s   string
-////			cap int
-////		}{
-////			s, len(s),
-////		},
-////	))
-////}
-//
-//func bytesToString(b []byte) string {
-//	return *(*string)(unsafe.Pointer(&b))
-//}
-//
-//type replayRows struct {
-//	ctx  context.Context
-//	rows driver.Rows
-//	data struct {
-//		Curr int
-//		Cols []string
-//		Rows [][]driver.Value
-//	}
-//}
-//
-//func (r *replayRows) init() error {
-//	sql, ok := GetSQL(r.ctx)
-//	if !ok {
-//		return errors.New("no sql found")
-//	}
-//	action := &recorder.Action{
-//		Protocol: recorder.SQL,
-//		Request:  sql,
-//	}
-//	ok, err := recorder.ReplayAction(r.ctx, action, func(r1, r2 interface{}) bool {
-//		return r1 == r2
-//	})
-//	if err != nil {
-//		return err
-//	}
-//	data, ok := action.Response.(json.RawMessage)
-//	if !ok {
-//		return errors.New("error response type")
-//	}
-//	return json.Unmarshal(data, &r.data)
-//}
-//
-//func (r *replayRows) Columns() []string {
-//	return r.data.Cols
-//}
-//
-//func (r *replayRows) Close() error {
-//	return r.rows.Close()
-//}
+//	sql.Register("mysql-replay", database.NewDriver(&mysql.MySQLDriver{}))
+func NewDriver(d driver.Driver) *Driver {
+	return &Driver{driver: d}
+}
+
+// OpenDB 使用包装 d 的 driver.Driver 打开数据库，dsn 为数据源名称。
+func OpenDB(d driver.Driver, dsn string) (*sql.DB, error) {
+	c, err := NewDriver(d).OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(c), nil
+}
+
+func (d *Driver) Open(name string) (driver.Conn, error) {
+	if replayer.ReplayMode() {
+		return &Conn{}, nil
+	}
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn}, nil
+}
+
+func (d *Driver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &Connector{connector: c, driver: d}, nil
+	}
+	return &Connector{connector: &dsnConnector{name: name, driver: d.driver}, driver: d}, nil
+}
+
+// dsnConnector 是不支持 driver.DriverContext 的 driver.Driver 的 driver.Connector 。
+type dsnConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Connector 包装 driver.Connector ，功能同 Driver 。
+type Connector struct {
+	connector driver.Connector
+	driver    *Driver
+}
+
+// NewConnector 返回包装 c 的 driver.Connector ，用法如下：
 //
-//func (r *replayRows) Next(dest []driver.Value) error {
-//	if r.data.Curr >= len(r.data.Rows) {
-//		return io.EOF
-//	}
-//	data := r.data.Rows[r.data.Curr]
-//	for i, v := range data {
-//		dest[i] = v
-//	}
-//	return nil
-//}
+//	db := sql.OpenDB(database.NewConnector(connector))
+func NewConnector(c driver.Connector) *Connector {
+	return &Connector{connector: c, driver: NewDriver(c.Driver())}
+}
+
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if replayer.ReplayMode() {
+		return &Conn{}, nil
+	}
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn}, nil
+}
+
+func (c *Connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// Conn 包装 driver.Conn ，回放模式下 conn 为 nil ，只使用录制的数据。
+type Conn struct {
+	conn driver.Conn
+}
+
+func (c *Conn) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+func (c *Conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.conn == nil {
+		return replayTx{}, nil
+	}
+	if ci, ok := c.conn.(driver.ConnBeginTx); ok {
+		return ci.BeginTx(ctx, opts)
+	}
+	if opts.ReadOnly || opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("driver doesn't support non-default transaction options")
+	}
+	return c.conn.Begin()
+}
+
+func (c *Conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		stmt driver.Stmt
+		err  error
+	)
+	if c.conn == nil {
+		return &Stmt{query: query}, nil
+	}
+	if ci, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = ci.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt{stmt: stmt, query: query}, nil
+}
+
+func (c *Conn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *Conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *Conn) CheckNamedValue(v *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
+
+func (c *Conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return execContext(ctx, query, args, func() (driver.Result, error) {
+		if c.conn == nil {
+			return nil, errNoRecord
+		}
+		if ci, ok := c.conn.(driver.ExecerContext); ok {
+			return ci.ExecContext(ctx, query, args)
+		}
+		return nil, driver.ErrSkip
+	})
+}
+
+func (c *Conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return queryContext(ctx, query, args, func() (driver.Rows, error) {
+		if c.conn == nil {
+			return nil, errNoRecord
+		}
+		if ci, ok := c.conn.(driver.QueryerContext); ok {
+			return ci.QueryContext(ctx, query, args)
+		}
+		return nil, driver.ErrSkip
+	})
+}
+
+// replayTx 是回放模式下什么也不做的 driver.Tx 。
+type replayTx struct{}
+
+func (replayTx) Commit() error   { return nil }
+func (replayTx) Rollback() error { return nil }
+
+// Stmt 包装 driver.Stmt ，回放模式下 stmt 为 nil ，只使用录制的数据。
+type Stmt struct {
+	stmt  driver.Stmt
+	query string
+}
+
+func (s *Stmt) Close() error {
+	if s.stmt == nil {
+		return nil
+	}
+	return s.stmt.Close()
+}
+
+func (s *Stmt) NumInput() int {
+	if s.stmt == nil {
+		return -1
+	}
+	return s.stmt.NumInput()
+}
+
+func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), toNamedValues(args))
+}
+
+func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), toNamedValues(args))
+}
+
+func (s *Stmt) CheckNamedValue(v *driver.NamedValue) error {
+	if s.stmt == nil {
+		return driver.ErrSkip
+	}
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(v)
+	}
+	return driver.ErrSkip
+}
+
+func (s *Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return execContext(ctx, s.query, args, func() (driver.Result, error) {
+		if s.stmt == nil {
+			return nil, errNoRecord
+		}
+		if si, ok := s.stmt.(driver.StmtExecContext); ok {
+			return si.ExecContext(ctx, args)
+		}
+		values, err := toValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.stmt.Exec(values)
+	})
+}
+
+func (s *Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return queryContext(ctx, s.query, args, func() (driver.Rows, error) {
+		if s.stmt == nil {
+			return nil, errNoRecord
+		}
+		if si, ok := s.stmt.(driver.StmtQueryContext); ok {
+			return si.QueryContext(ctx, args)
+		}
+		values, err := toValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.stmt.Query(values)
+	})
+}
+
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
+
+func toValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("driver doesn't support named values")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// inReplaySession 返回是否处于回放会话中。
+func inReplaySession(ctx context.Context) bool {
+	if !replayer.ReplayMode() {
+		return false
+	}
+	_, err := replayer.GetSessionID(ctx)
+	return err == nil
+}
+
+// execContext 执行 SQL 语句，录制模式下录制执行结果，回放模式下优先使用录制
+// 的执行结果。
+func execContext(ctx context.Context, query string, args []driver.NamedValue,
+	fn func() (driver.Result, error)) (driver.Result, error) {
+
+	if inReplaySession(ctx) {
+		resp, ok, err := replayer.Query(ctx, recorder.SQL, encodeRequest(query, args))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return decodeResult(resp)
+		}
+	}
+
+	result, err := fn()
+	if err == driver.ErrSkip || !recorder.RecordMode() {
+		return result, err
+	}
+
+	recorder.RecordAction(ctx, recorder.SQL, &recorder.SimpleAction{
+		Request: func() string {
+			return encodeRequest(query, args)
+		},
+		Response: func() string {
+			return encodeResult(result, err)
+		},
+	})
+	return result, err
+}
+
+// queryContext 执行 SQL 查询，录制模式下在结果集读取完毕或者关闭时录制读取到
+// 的数据，回放模式下优先使用录制的数据。
+func queryContext(ctx context.Context, query string, args []driver.NamedValue,
+	fn func() (driver.Rows, error)) (driver.Rows, error) {
+
+	if inReplaySession(ctx) {
+		resp, ok, err := replayer.Query(ctx, recorder.SQL, encodeRequest(query, args))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return decodeRows(resp)
+		}
+	}
+
+	rows, err := fn()
+	if err == driver.ErrSkip || !recorder.RecordMode() {
+		return rows, err
+	}
+
+	request := encodeRequest(query, args)
+	if err != nil {
+		recorder.RecordAction(ctx, recorder.SQL, &recorder.SimpleAction{
+			Request: func() string {
+				return request
+			},
+			Response: func() string {
+				return encodeError(err)
+			},
+		})
+		return nil, err
+	}
+	return &recordRows{ctx: ctx, rows: rows, request: request}, nil
+}
+
+// recordRows 包装 driver.Rows ，记录读取到的每个结果集的列和数据。
+type recordRows struct {
+	ctx      context.Context
+	rows     driver.Rows
+	request  string
+	sets     []*resultSet
+	err      error
+	recorded bool
+}
+
+// set 返回当前结果集，第一次调用时读取第一个结果集的列和列类型。
+func (r *recordRows) set() *resultSet {
+	if len(r.sets) == 0 {
+		r.sets = append(r.sets, newResultSet(r.rows))
+	}
+	return r.sets[len(r.sets)-1]
+}
+
+func (r *recordRows) Columns() []string {
+	return r.rows.Columns()
+}
+
+func (r *recordRows) Close() error {
+	r.record()
+	return r.rows.Close()
+}
+
+func (r *recordRows) Next(dest []driver.Value) error {
+	set := r.set()
+	if err := r.rows.Next(dest); err != nil {
+		if err != io.EOF {
+			r.err = err
+			r.record()
+		} else if !r.HasNextResultSet() {
+			r.record()
+		}
+		return err
+	}
+	row := make([]driver.Value, len(dest))
+	for i, v := range dest {
+		if b, ok := v.([]byte); ok {
+			v = append([]byte(nil), b...)
+		}
+		row[i] = v
+	}
+	set.data = append(set.data, row)
+	return nil
+}
+
+func (r *recordRows) HasNextResultSet() bool {
+	if rs, ok := r.rows.(driver.RowsNextResultSet); ok {
+		return rs.HasNextResultSet()
+	}
+	return false
+}
+
+func (r *recordRows) NextResultSet() error {
+	rs, ok := r.rows.(driver.RowsNextResultSet)
+	if !ok {
+		return io.EOF
+	}
+	r.set()
+	if err := rs.NextResultSet(); err != nil {
+		if err != io.EOF {
+			r.err = err
+		}
+		r.record()
+		return err
+	}
+	r.sets = append(r.sets, newResultSet(r.rows))
+	return nil
+}
+
+func (r *recordRows) ColumnTypeScanType(index int) reflect.Type {
+	if rs, ok := r.rows.(driver.RowsColumnTypeScanType); ok {
+		return rs.ColumnTypeScanType(index)
+	}
+	return scanTypeAny
+}
+
+func (r *recordRows) ColumnTypeDatabaseTypeName(index int) string {
+	if rs, ok := r.rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return rs.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+func (r *recordRows) ColumnTypeLength(index int) (int64, bool) {
+	if rs, ok := r.rows.(driver.RowsColumnTypeLength); ok {
+		return rs.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+func (r *recordRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if rs, ok := r.rows.(driver.RowsColumnTypeNullable); ok {
+		return rs.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+func (r *recordRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if rs, ok := r.rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return rs.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
+
+// record 录制读取到的数据，只录制一次。
+func (r *recordRows) record() {
+	if r.recorded {
+		return
+	}
+	r.recorded = true
+	r.set()
+	sets, err := r.sets, r.err
+	recorder.RecordAction(r.ctx, recorder.SQL, &recorder.SimpleAction{
+		Request: func() string {
+			return r.request
+		},
+		Response: func() string {
+			return encodeRows(sets, err)
+		},
+	})
+}
+
+// replayRows 是使用录制的数据构造的 driver.Rows 。
+type replayRows struct {
+	sets []*resultSet
+	err  error
+	set  int
+	next int
+}
+
+func (r *replayRows) Columns() []string {
+	return r.sets[r.set].columns
+}
+
+func (r *replayRows) Close() error {
+	return nil
+}
+
+func (r *replayRows) Next(dest []driver.Value) error {
+	data := r.sets[r.set].data
+	if r.next >= len(data) {
+		if r.err != nil && !r.HasNextResultSet() {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, data[r.next])
+	r.next++
+	return nil
+}
+
+func (r *replayRows) HasNextResultSet() bool {
+	return r.set+1 < len(r.sets)
+}
+
+func (r *replayRows) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.set++
+	r.next = 0
+	return nil
+}
+
+func (r *replayRows) ColumnTypeScanType(index int) reflect.Type {
+	return r.sets[r.set].ColumnTypeScanType(index)
+}
+
+func (r *replayRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.sets[r.set].ColumnTypeDatabaseTypeName(index)
+}
+
+func (r *replayRows) ColumnTypeLength(index int) (int64, bool) {
+	return r.sets[r.set].ColumnTypeLength(index)
+}
+
+func (r *replayRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return r.sets[r.set].ColumnTypeNullable(index)
+}
+
+func (r *replayRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	return r.sets[r.set].ColumnTypePrecisionScale(index)
+}
@@ -16,332 +16,336 @@
 
 package database
 
-//
-//var errNilPtr = errors.New("destination pointer is nil")
-//
-//func convertAssign(dest, src interface{}) error {
-//	// Common cases, without reflect.
-//	switch s := src.(type) {
-//	case string:
-//		switch d := dest.(type) {
-//		case *string:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = s
-//			return nil
-//		case *[]byte:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = []byte(s)
-//			return nil
-//		case *sql.RawBytes:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = append((*d)[:0], s...)
-//			return nil
-//		}
-//	case []byte:
-//		switch d := dest.(type) {
-//		case *string:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = string(s)
-//			return nil
-//		case *interface{}:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = cloneBytes(s)
-//			return nil
-//		case *[]byte:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = cloneBytes(s)
-//			return nil
-//		case *sql.RawBytes:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = s
-//			return nil
-//		}
-//	case time.Time:
-//		switch d := dest.(type) {
-//		case *time.Time:
-//			*d = s
-//			return nil
-//		case *string:
-//			*d = s.Format(time.RFC3339Nano)
-//			return nil
-//		case *[]byte:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = []byte(s.Format(time.RFC3339Nano))
-//			return nil
-//		case *sql.RawBytes:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = s.AppendFormat((*d)[:0], time.RFC3339Nano)
-//			return nil
-//		}
-//	case decimalDecompose:
-//		switch d := dest.(type) {
-//		case decimalCompose:
-//			return d.Compose(s.Decompose(nil))
-//		}
-//	case nil:
-//		switch d := dest.(type) {
-//		case *interface{}:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = nil
-//			return nil
-//		case *[]byte:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = nil
-//			return nil
-//		case *sql.RawBytes:
-//			if d == nil {
-//				return errNilPtr
-//			}
-//			*d = nil
-//			return nil
-//		}
-//	}
-//
-//	var sv reflect.Value
-//
-//	switch d := dest.(type) {
-//	case *string:
-//		sv = reflect.ValueOf(src)
-//		switch sv.Kind() {
-//		case reflect.Bool,
-//			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-//			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
-//			reflect.Float32, reflect.Float64:
-//			*d = asString(src)
-//			return nil
-//		}
-//	case *[]byte:
-//		sv = reflect.ValueOf(src)
-//		if b, ok := asBytes(nil, sv); ok {
-//			*d = b
-//			return nil
-//		}
-//	case *sql.RawBytes:
-//		sv = reflect.ValueOf(src)
-//		if b, ok := asBytes([]byte(*d)[:0], sv); ok {
-//			*d = sql.RawBytes(b)
-//			return nil
-//		}
-//	case *bool:
-//		bv, err := driver.Bool.ConvertValue(src)
-//		if err == nil {
-//			*d = bv.(bool)
-//		}
-//		return err
-//	case *interface{}:
-//		*d = src
-//		return nil
-//	}
-//
-//	dpv := reflect.ValueOf(dest)
-//	if dpv.Kind() != reflect.Ptr {
-//		return errors.New("destination not a pointer")
-//	}
-//	if dpv.IsNil() {
-//		return errNilPtr
-//	}
-//
-//	if !sv.IsValid() {
-//		sv = reflect.ValueOf(src)
-//	}
-//
-//	dv := reflect.Indirect(dpv)
-//	if sv.IsValid() && sv.Type().AssignableTo(dv.Type()) {
-//		switch b := src.(type) {
-//		case []byte:
-//			dv.Set(reflect.ValueOf(cloneBytes(b)))
-//		default:
-//			dv.Set(sv)
-//		}
-//		return nil
-//	}
-//
-//	if dv.Kind() == sv.Kind() && sv.Type().ConvertibleTo(dv.Type()) {
-//		dv.Set(sv.Convert(dv.Type()))
-//		return nil
-//	}
-//
-//	// The following conversions use a string value as an intermediate representation
-//	// to convert between various numeric types.
-//	//
-//	// This also allows scanning into user defined types such as "type Int int64".
-//	// For symmetry, also check for string destination types.
-//	switch dv.Kind() {
-//	case reflect.Ptr:
-//		if src == nil {
-//			dv.Set(reflect.Zero(dv.Type()))
-//			return nil
-//		}
-//		dv.Set(reflect.New(dv.Type().Elem()))
-//		return convertAssign(dv.Interface(), src)
-//	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-//		if src == nil {
-//			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
-//		}
-//		s := asString(src)
-//		i64, err := strconv.ParseInt(s, 10, dv.Type().Bits())
-//		if err != nil {
-//			err = strconvErr(err)
-//			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %v", src, s, dv.Kind(), err)
-//		}
-//		dv.SetInt(i64)
-//		return nil
-//	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-//		if src == nil {
-//			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
-//		}
-//		s := asString(src)
-//		u64, err := strconv.ParseUint(s, 10, dv.Type().Bits())
-//		if err != nil {
-//			err = strconvErr(err)
-//			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %v", src, s, dv.Kind(), err)
-//		}
-//		dv.SetUint(u64)
-//		return nil
-//	case reflect.Float32, reflect.Float64:
-//		if src == nil {
-//			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
-//		}
-//		s := asString(src)
-//		f64, err := strconv.ParseFloat(s, dv.Type().Bits())
-//		if err != nil {
-//			err = strconvErr(err)
-//			return fmt.Errorf("converting driver.Value type %T (%q) to a %s: %v", src, s, dv.Kind(), err)
-//		}
-//		dv.SetFloat(f64)
-//		return nil
-//	case reflect.String:
-//		if src == nil {
-//			return fmt.Errorf("converting NULL to %s is unsupported", dv.Kind())
-//		}
-//		switch v := src.(type) {
-//		case string:
-//			dv.SetString(v)
-//			return nil
-//		case []byte:
-//			dv.SetString(string(v))
-//			return nil
-//		}
-//	}
-//
-//	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %T", src, dest)
-//}
-//
-//func strconvErr(err error) error {
-//	if ne, ok := err.(*strconv.NumError); ok {
-//		return ne.Err
-//	}
-//	return err
-//}
-//
-//func cloneBytes(b []byte) []byte {
-//	if b == nil {
-//		return nil
-//	}
-//	c := make([]byte, len(b))
-//	copy(c, b)
-//	return c
-//}
-//
-//func asString(src interface{}) string {
-//	switch v := src.(type) {
-//	case string:
-//		return v
-//	case []byte:
-//		return string(v)
-//	}
-//	rv := reflect.ValueOf(src)
-//	switch rv.Kind() {
-//	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-//		return strconv.FormatInt(rv.Int(), 10)
-//	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-//		return strconv.FormatUint(rv.Uint(), 10)
-//	case reflect.Float64:
-//		return strconv.FormatFloat(rv.Float(), 'g', -1, 64)
-//	case reflect.Float32:
-//		return strconv.FormatFloat(rv.Float(), 'g', -1, 32)
-//	case reflect.Bool:
-//		return strconv.FormatBool(rv.Bool())
-//	}
-//	return fmt.Sprintf("%v", src)
-//}
-//
-//func asBytes(buf []byte, rv reflect.Value) (b []byte, ok bool) {
-//	switch rv.Kind() {
-//	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-//		return strconv.AppendInt(buf, rv.Int(), 10), true
-//	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-//		return strconv.AppendUint(buf, rv.Uint(), 10), true
-//	case reflect.Float32:
-//		return strconv.AppendFloat(buf, rv.Float(), 'g', -1, 32), true
-//	case reflect.Float64:
-//		return strconv.AppendFloat(buf, rv.Float(), 'g', -1, 64), true
-//	case reflect.Bool:
-//		return strconv.AppendBool(buf, rv.Bool()), true
-//	case reflect.String:
-//		s := rv.String()
-//		return append(buf, s...), true
-//	}
-//	return
-//}
-//
-//// decimal composes or decomposes a decimal value to and from individual parts.
-//// There are four parts: a boolean negative flag, a form byte with three possible states
-//// (finite=0, infinite=1, NaN=2), a base-2 big-endian integer
-//// coefficient (also known as a significand) as a []byte, and an int32 exponent.
-//// These are composed into a final value as "decimal = (neg) (form=finite) coefficient * 10 ^ exponent".
-//// A zero length coefficient is a zero value.
-//// The big-endian integer coefficient stores the most significant byte first (at coefficient[0]).
-//// If the form is not finite the coefficient and exponent should be ignored.
-//// The negative parameter may be set to true for any form, although implementations are not required
-//// to respect the negative parameter in the non-finite form.
-////
-//// Implementations may choose to set the negative parameter to true on a zero or NaN value,
-//// but implementations that do not differentiate between negative and positive
-//// zero or NaN values should ignore the negative parameter without error.
-//// If an implementation does not support Infinity it may be converted into a NaN without error.
-//// If a value is set that is larger than what is supported by an implementation,
-//// an error must be returned.
-//// Implementations must return an error if a NaN or Infinity is attempted to be set while neither
-//// are supported.
-////
-//// NOTE(kardianos): This is an experimental interface. See https://golang.org/issue/30870
-//type decimal interface {
-//	decimalDecompose
-//	decimalCompose
-//}
-//
-//type decimalDecompose interface {
-//	// Decompose returns the internal decimal state in parts.
-//	// If the provided buf has sufficient capacity, buf may be returned as the coefficient with
-//	// the value set and length set as appropriate.
-//	Decompose(buf []byte) (form byte, negative bool, coefficient []byte, exponent int32)
-//}
-//
-//type decimalCompose interface {
-//	// Compose sets the internal decimal value from parts. If the value cannot be
-//	// represented then an error should be returned.
-//	Compose(form byte, negative bool, coefficient []byte, exponent int32) error
-//}
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-spring/spring-replay/internal/json"
+	"github.com/go-spring/spring-replay/recorder"
+)
+
+// 录制的 driver.Value 中，int64 和 nil 保存为 JSON 的数字和 null ，string 保存
+// 为 JSON 的字符串，其他类型保存为带有类型前缀的字符串，例如 "time@@..." 。
+const (
+	stringPrefix = "string@@"
+	floatPrefix  = "float64@@"
+	boolPrefix   = "bool@@"
+	bytesPrefix  = "bytes@@"
+	base64Prefix = "base64@@"
+	timePrefix   = "time@@"
+)
+
+type sqlRequest struct {
+	Query string        `json:",omitempty"`
+	Args  []interface{} `json:",omitempty"`
+}
+
+// sqlResponse 是 SQL 语句的录制结果，查询结果的第一个结果集保存在 Columns 、
+// ColumnTypes 和 Rows 中，其他的结果集按顺序保存在 ResultSets 中。
+type sqlResponse struct {
+	LastInsertId int64 `json:",omitempty"`
+	RowsAffected int64 `json:",omitempty"`
+	sqlResultSet
+	ResultSets []sqlResultSet `json:",omitempty"`
+	Error      string         `json:",omitempty"`
+}
+
+type sqlResultSet struct {
+	Columns     []string         `json:",omitempty"`
+	ColumnTypes []*sqlColumnType `json:",omitempty"`
+	Rows        [][]interface{}  `json:",omitempty"`
+}
+
+// sqlColumnType 是驱动提供的列类型信息，没有提供的信息不录制。
+type sqlColumnType struct {
+	DatabaseTypeName string `json:",omitempty"`
+	ScanType         string `json:",omitempty"`
+	Length           *int64 `json:",omitempty"`
+	Nullable         *bool  `json:",omitempty"`
+	Precision        *int64 `json:",omitempty"`
+	Scale            *int64 `json:",omitempty"`
+}
+
+// scanTypeAny 是驱动没有提供列的 ScanType 时使用的类型。
+var scanTypeAny = reflect.TypeOf(new(interface{})).Elem()
+
+// scanTypes 是回放时能够还原的 ScanType ，其他类型还原为 scanTypeAny 。
+var scanTypes = map[string]reflect.Type{}
+
+func init() {
+	for _, v := range []interface{}{
+		int8(0), int16(0), int32(0), int64(0),
+		uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0), false, "", []byte(nil), time.Time{},
+		sql.RawBytes(nil), sql.NullBool{}, sql.NullFloat64{}, sql.NullInt32{},
+		sql.NullInt64{}, sql.NullString{}, sql.NullTime{},
+	} {
+		t := reflect.TypeOf(v)
+		scanTypes[t.String()] = t
+	}
+}
+
+// resultSet 是一个结果集的列、列类型和数据，驱动没有提供列类型时 types 为 nil 。
+type resultSet struct {
+	columns []string
+	types   []*sqlColumnType
+	data    [][]driver.Value
+}
+
+// newResultSet 返回 rows 当前结果集的列和列类型。
+func newResultSet(rows driver.Rows) *resultSet {
+	set := &resultSet{columns: rows.Columns()}
+	scanType, ok1 := rows.(driver.RowsColumnTypeScanType)
+	typeName, ok2 := rows.(driver.RowsColumnTypeDatabaseTypeName)
+	length, ok3 := rows.(driver.RowsColumnTypeLength)
+	nullable, ok4 := rows.(driver.RowsColumnTypeNullable)
+	decimal, ok5 := rows.(driver.RowsColumnTypePrecisionScale)
+	if !ok1 && !ok2 && !ok3 && !ok4 && !ok5 {
+		return set
+	}
+	for i := range set.columns {
+		var t sqlColumnType
+		if ok1 {
+			t.ScanType = scanType.ColumnTypeScanType(i).String()
+		}
+		if ok2 {
+			t.DatabaseTypeName = typeName.ColumnTypeDatabaseTypeName(i)
+		}
+		if ok3 {
+			if n, ok := length.ColumnTypeLength(i); ok {
+				t.Length = &n
+			}
+		}
+		if ok4 {
+			if b, ok := nullable.ColumnTypeNullable(i); ok {
+				t.Nullable = &b
+			}
+		}
+		if ok5 {
+			if p, s, ok := decimal.ColumnTypePrecisionScale(i); ok {
+				t.Precision, t.Scale = &p, &s
+			}
+		}
+		set.types = append(set.types, &t)
+	}
+	return set
+}
+
+// columnType 返回第 index 列录制的列类型，没有录制时返回空的列类型。
+func (s *resultSet) columnType(index int) *sqlColumnType {
+	if index < len(s.types) && s.types[index] != nil {
+		return s.types[index]
+	}
+	return &sqlColumnType{}
+}
+
+func (s *resultSet) ColumnTypeScanType(index int) reflect.Type {
+	if t, ok := scanTypes[s.columnType(index).ScanType]; ok {
+		return t
+	}
+	return scanTypeAny
+}
+
+func (s *resultSet) ColumnTypeDatabaseTypeName(index int) string {
+	return s.columnType(index).DatabaseTypeName
+}
+
+func (s *resultSet) ColumnTypeLength(index int) (int64, bool) {
+	if t := s.columnType(index); t.Length != nil {
+		return *t.Length, true
+	}
+	return 0, false
+}
+
+func (s *resultSet) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if t := s.columnType(index); t.Nullable != nil {
+		return *t.Nullable, true
+	}
+	return false, false
+}
+
+func (s *resultSet) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if t := s.columnType(index); t.Precision != nil && t.Scale != nil {
+		return *t.Precision, *t.Scale, true
+	}
+	return 0, 0, false
+}
+
+// encodeRequest 返回 SQL 语句和参数的录制格式，SQL 语句经过 Normalize 处理。
+func encodeRequest(query string, args []driver.NamedValue) string {
+	r := sqlRequest{Query: Normalize(query)}
+	for _, arg := range args {
+		r.Args = append(r.Args, encodeValue(arg.Value))
+	}
+	return recorder.ToJson(r)
+}
+
+func encodeError(err error) string {
+	return recorder.ToJson(sqlResponse{Error: err.Error()})
+}
+
+func encodeResult(result driver.Result, err error) string {
+	if err != nil {
+		return encodeError(err)
+	}
+	var r sqlResponse
+	r.LastInsertId, _ = result.LastInsertId()
+	r.RowsAffected, _ = result.RowsAffected()
+	return recorder.ToJson(r)
+}
+
+func encodeRows(sets []*resultSet, err error) string {
+	var r sqlResponse
+	for i, set := range sets {
+		s := sqlResultSet{Columns: set.columns, ColumnTypes: set.types}
+		for _, row := range set.data {
+			values := make([]interface{}, len(row))
+			for j, v := range row {
+				values[j] = encodeValue(v)
+			}
+			s.Rows = append(s.Rows, values)
+		}
+		if i == 0 {
+			r.sqlResultSet = s
+		} else {
+			r.ResultSets = append(r.ResultSets, s)
+		}
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	return recorder.ToJson(r)
+}
+
+func decodeResponse(data string) (*sqlResponse, error) {
+	var r sqlResponse
+	d := json.NewDecoder(bytes.NewReader([]byte(data)))
+	d.UseNumber()
+	if err := d.Decode(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// result 是使用录制的数据构造的 driver.Result 。
+type result struct {
+	lastInsertId int64
+	rowsAffected int64
+}
+
+func (r *result) LastInsertId() (int64, error) {
+	return r.lastInsertId, nil
+}
+
+func (r *result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+func decodeResult(data string) (driver.Result, error) {
+	r, err := decodeResponse(data)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != "" {
+		return nil, errors.New(r.Error)
+	}
+	return &result{lastInsertId: r.LastInsertId, rowsAffected: r.RowsAffected}, nil
+}
+
+func decodeRows(data string) (driver.Rows, error) {
+	r, err := decodeResponse(data)
+	if err != nil {
+		return nil, err
+	}
+	if r.Error != "" && r.Columns == nil {
+		return nil, errors.New(r.Error)
+	}
+	rows := &replayRows{}
+	for _, s := range append([]sqlResultSet{r.sqlResultSet}, r.ResultSets...) {
+		set := &resultSet{columns: s.Columns, types: s.ColumnTypes}
+		for _, row := range s.Rows {
+			values := make([]driver.Value, len(row))
+			for i, v := range row {
+				if values[i], err = decodeValue(v); err != nil {
+					return nil, err
+				}
+			}
+			set.data = append(set.data, values)
+		}
+		rows.sets = append(rows.sets, set)
+	}
+	if r.Error != "" {
+		rows.err = errors.New(r.Error)
+	}
+	return rows, nil
+}
+
+// encodeValue 返回 driver.Value 的录制格式。
+func encodeValue(v driver.Value) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case int64:
+		return x
+	case string:
+		if strings.Contains(x, "@@") {
+			return stringPrefix + x
+		}
+		return x
+	case float64:
+		return floatPrefix + strconv.FormatFloat(x, 'g', -1, 64)
+	case bool:
+		return boolPrefix + strconv.FormatBool(x)
+	case []byte:
+		if utf8.Valid(x) {
+			return bytesPrefix + string(x)
+		}
+		return base64Prefix + base64.StdEncoding.EncodeToString(x)
+	case time.Time:
+		return timePrefix + x.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// decodeValue 将录制格式的数据还原为 driver.Value 。
+func decodeValue(v interface{}) (driver.Value, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		return x.Int64()
+	case string:
+		i := strings.Index(x, "@@")
+		if i < 0 {
+			return x, nil
+		}
+		s := x[i+2:]
+		switch x[:i+2] {
+		case stringPrefix:
+			return s, nil
+		case floatPrefix:
+			return strconv.ParseFloat(s, 64)
+		case boolPrefix:
+			return strconv.ParseBool(s)
+		case bytesPrefix:
+			return []byte(s), nil
+		case base64Prefix:
+			return base64.StdEncoding.DecodeString(s)
+		case timePrefix:
+			return time.Parse(time.RFC3339Nano, s)
+		}
+		return nil, fmt.Errorf("invalid value %q", x)
+	default:
+		return nil, fmt.Errorf("invalid value %v", x)
+	}
+}
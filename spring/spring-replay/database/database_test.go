@@ -0,0 +1,353 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-replay/database"
+	"github.com/go-spring/spring-replay/recorder"
+	"github.com/go-spring/spring-replay/replayer"
+)
+
+func init() {
+
+	config := `
+		<?xml version="1.0" encoding="UTF-8"?>
+		<Configuration>
+			<Appenders>
+				<Console name="Console"/>
+			</Appenders>
+			<Loggers>
+				<Root level="info">
+					<AppenderRef ref="Console"/>
+				</Root>
+			</Loggers>
+		</Configuration>
+	`
+	err := log.RefreshBuffer(config, ".xml")
+	util.Panic(err).When(err != nil)
+
+	recorder.Init()
+}
+
+var (
+	errDown     = errors.New("database is down")
+	errNotFound = errors.New("table not found")
+	createdAt   = time.Date(2022, 3, 4, 5, 6, 7, 8, time.UTC)
+)
+
+// fakeDriver 是只支持几条固定语句的 driver.Driver ，down 为 true 时所有的语句
+// 都返回错误。
+type fakeDriver struct {
+	down bool
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("tx not supported") }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.d.down {
+		return nil, errDown
+	}
+	return driver.RowsAffected(len(args)), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.d.down {
+		return nil, errDown
+	}
+	if query == "SELECT * FROM none" {
+		return nil, errNotFound
+	}
+	users := [][]driver.Value{
+		{int64(1), []byte("jim"), 1.5, true, createdAt, nil},
+		{int64(2), []byte{0xff, 0x00}, 2.5, false, createdAt, "a@@b"},
+	}
+	if query == "CALL users()" {
+		return &fakeRows{sets: [][][]driver.Value{users[:1], users[1:]}}, nil
+	}
+	return &fakeRows{sets: [][][]driver.Value{users}}, nil
+}
+
+// fakeRows 是包含一个或者多个结果集的 driver.Rows ，提供 BIGINT 类型的 id 列
+// 的类型信息。
+type fakeRows struct {
+	sets [][][]driver.Value
+	set  int
+	next int
+}
+
+func (r *fakeRows) Columns() []string {
+	return []string{"id", "name", "score", "valid", "created_at", "remark"}
+}
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	data := r.sets[r.set]
+	if r.next >= len(data) {
+		return io.EOF
+	}
+	copy(dest, data[r.next])
+	r.next++
+	return nil
+}
+
+func (r *fakeRows) HasNextResultSet() bool {
+	return r.set+1 < len(r.sets)
+}
+
+func (r *fakeRows) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.set++
+	r.next = 0
+	return nil
+}
+
+func (r *fakeRows) ColumnTypeDatabaseTypeName(index int) string {
+	if index == 0 {
+		return "BIGINT"
+	}
+	return ""
+}
+
+func (r *fakeRows) ColumnTypeScanType(index int) reflect.Type {
+	if index == 0 {
+		return reflect.TypeOf(int64(0))
+	}
+	return reflect.TypeOf(new(interface{})).Elem()
+}
+
+func (r *fakeRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	return index != 0, true
+}
+
+type user struct {
+	ID        int64
+	Name      []byte
+	Score     float64
+	Valid     bool
+	CreatedAt time.Time
+	Remark    sql.NullString
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func queryUsers(ctx context.Context, db queryer, query string, args ...interface{}) ([]user, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUsers(rows)
+}
+
+// scanUsers 读取当前结果集中的 user 。
+func scanUsers(rows *sql.Rows) ([]user, error) {
+	var users []user
+	for rows.Next() {
+		var u user
+		err := rows.Scan(&u.ID, &u.Name, &u.Score, &u.Valid, &u.CreatedAt, &u.Remark)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// queryResultSets 读取所有结果集中的 user ，并返回第一个结果集的列类型。
+func queryResultSets(ctx context.Context, db *sql.DB, query string) ([][]user, []*sql.ColumnType, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+	var sets [][]user
+	for {
+		users, err := scanUsers(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		sets = append(sets, users)
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	return sets, types, rows.Err()
+}
+
+func TestNormalize(t *testing.T) {
+	testcases := []struct {
+		query  string
+		expect string
+	}{
+		{"  SELECT *\n\tFROM  user  ", "SELECT * FROM user"},
+		{"SELECT * FROM user WHERE id = $1 AND name = $2", "SELECT * FROM user WHERE id = ? AND name = ?"},
+		{"SELECT * FROM user WHERE id = :id AND name = @name", "SELECT * FROM user WHERE id = ? AND name = ?"},
+		{"SELECT '  a :b $1', \"x  \\\" y\" FROM t", "SELECT '  a :b $1', \"x  \\\" y\" FROM t"},
+		{"SELECT id::text, @@version FROM t", "SELECT id::text, @@version FROM t"},
+	}
+	for _, c := range testcases {
+		assert.Equal(t, database.Normalize(c.query), c.expect)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+
+	d := &fakeDriver{}
+	sessionID := "e7b8bb1a39d94c0b9ab6bd8e6a4b2d19"
+	var session string
+
+	expectUsers := []user{
+		{ID: 1, Name: []byte("jim"), Score: 1.5, Valid: true, CreatedAt: createdAt},
+		{ID: 2, Name: []byte{0xff, 0x00}, Score: 2.5, CreatedAt: createdAt, Remark: sql.NullString{String: "a@@b", Valid: true}},
+	}
+
+	t.Run("record", func(t *testing.T) {
+		recorder.SetRecordMode(true)
+		defer recorder.SetRecordMode(false)
+
+		db, err := database.OpenDB(d, "fake")
+		assert.Nil(t, err)
+		defer db.Close()
+
+		ctx, _ := knife.New(context.Background())
+		recorder.StartRecord(ctx, func() (string, error) {
+			return sessionID, nil
+		})
+
+		r, err := db.ExecContext(ctx, "UPDATE user SET name = $1 WHERE id = $2", "tom", 1)
+		assert.Nil(t, err)
+		n, _ := r.RowsAffected()
+		assert.Equal(t, n, int64(2))
+
+		users, err := queryUsers(ctx, db, "SELECT * FROM user WHERE id > ?", 0)
+		assert.Nil(t, err)
+		assert.Equal(t, users, expectUsers)
+
+		_, err = queryUsers(ctx, db, "SELECT * FROM none")
+		assert.Equal(t, err, errNotFound)
+
+		sets, types, err := queryResultSets(ctx, db, "CALL users()")
+		assert.Nil(t, err)
+		assert.Equal(t, sets, [][]user{expectUsers[:1], expectUsers[1:]})
+		assert.Equal(t, types[0].DatabaseTypeName(), "BIGINT")
+
+		s := recorder.StopRecord(ctx)
+		assert.Equal(t, len(s.Actions), 4)
+		session = recorder.ToJson(s)
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		replayer.SetReplayMode(true)
+		defer replayer.SetReplayMode(false)
+
+		// 回放模式下不访问真实的数据库。
+		d.down = true
+		defer func() { d.down = false }()
+
+		db, err := database.OpenDB(d, "fake")
+		assert.Nil(t, err)
+		defer db.Close()
+
+		agent := replayer.NewLocalAgent()
+		replayer.SetReplayAgent(agent)
+		_, err = agent.Store(session)
+		assert.Nil(t, err)
+
+		ctx, _ := knife.New(context.Background())
+		err = replayer.SetSessionID(ctx, sessionID)
+		assert.Nil(t, err)
+
+		// 事务和预处理语句什么也不做，只使用录制的数据。
+		tx, err := db.BeginTx(ctx, nil)
+		assert.Nil(t, err)
+		r, err := tx.ExecContext(ctx, "UPDATE user\n\tSET name = :name WHERE id = :id", "tom", 1)
+		assert.Nil(t, err)
+		n, _ := r.RowsAffected()
+		assert.Equal(t, n, int64(2))
+		assert.Nil(t, tx.Commit())
+
+		stmt, err := db.PrepareContext(ctx, "SELECT *  FROM user WHERE id > ?")
+		assert.Nil(t, err)
+		users, err := queryUsers(ctx, stmtQueryer{stmt}, "", 0)
+		assert.Nil(t, err)
+		assert.Equal(t, users, expectUsers)
+		assert.Nil(t, stmt.Close())
+
+		_, err = queryUsers(ctx, db, "SELECT * FROM none")
+		assert.Error(t, err, errNotFound.Error())
+
+		sets, types, err := queryResultSets(ctx, db, "CALL users()")
+		assert.Nil(t, err)
+		assert.Equal(t, sets, [][]user{expectUsers[:1], expectUsers[1:]})
+		assert.Equal(t, types[0].DatabaseTypeName(), "BIGINT")
+		assert.Equal(t, types[0].ScanType(), reflect.TypeOf(int64(0)))
+		nullable, ok := types[1].Nullable()
+		assert.True(t, nullable && ok)
+
+		// 录制的数据只能匹配一次，没有匹配的数据时返回错误。
+		_, err = db.ExecContext(ctx, "UPDATE user SET name = $1 WHERE id = $2", "tom", 1)
+		assert.Error(t, err, "no recorded data found")
+
+		// 不在回放会话中时返回错误。
+		_, err = db.ExecContext(context.Background(), "UPDATE user SET name = $1 WHERE id = $2", "tom", 1)
+		assert.Error(t, err, "no recorded data found")
+	})
+}
+
+// stmtQueryer 使用预处理语句执行查询，忽略查询语句。
+type stmtQueryer struct {
+	stmt *sql.Stmt
+}
+
+func (q stmtQueryer) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.stmt.QueryContext(ctx, args...)
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package database
+
+import (
+	"strings"
+
+	"github.com/go-spring/spring-replay/internal/json"
+	"github.com/go-spring/spring-replay/recorder"
+)
+
+func init() {
+	recorder.RegisterProtocol(recorder.SQL, &protocol{})
+}
+
+// Normalize 返回用于匹配的 SQL 语句，连续的空白字符替换为一个空格，并且去掉首
+// 尾的空白字符，占位符 ?、$1、:name 和 @name 统一替换为 ?，引号中的内容保持不变。
+func Normalize(query string) string {
+	var (
+		buf   strings.Builder
+		quote byte
+		space bool
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if quote != 0 {
+			buf.WriteByte(c)
+			if c == '\\' && i+1 < len(query) {
+				i++
+				buf.WriteByte(query[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if isSpace(c) {
+			space = true
+			continue
+		}
+		if space && buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		space = false
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+			buf.WriteByte(c)
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			i = skip(query, i+1, isDigit)
+			buf.WriteByte('?')
+		case (c == ':' || c == '@') && i+1 < len(query) && isWord(query[i+1]) && (i == 0 || query[i-1] != c):
+			i = skip(query, i+1, isWord)
+			buf.WriteByte('?')
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String()
+}
+
+// skip 返回从 i 开始最后一个满足 fn 的字符的位置。
+func skip(s string, i int, fn func(byte) bool) int {
+	for i+1 < len(s) && fn(s[i+1]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWord(c byte) bool {
+	return isDigit(c) || c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+type protocol struct{}
+
+// GetLabel 返回 SQL 语句的第一个单词，例如 SELECT 、INSERT 等。
+func (p *protocol) GetLabel(data string) string {
+	var r sqlRequest
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		return ""
+	}
+	return strings.ToUpper(strings.SplitN(r.Query, " ", 2)[0])
+}
+
+func (p *protocol) FlatRequest(data string) (map[string]string, error) {
+	return recorder.FlatJSON(data), nil
+}
+
+func (p *protocol) FlatResponse(data string) (map[string]string, error) {
+	return recorder.FlatJSON(data), nil
+}
@@ -2,4 +2,12 @@ module github.com/go-spring/spring-replay
 
 go 1.14
 
-require github.com/go-spring/spring-base v1.1.2
+require (
+	github.com/go-spring/spring-base v1.1.3
+	github.com/go-spring/spring-core v1.1.3
+)
+
+replace (
+	github.com/go-spring/spring-base => ../spring-base
+	github.com/go-spring/spring-core => ../spring-core
+)
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"errors"
 	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 
@@ -48,26 +49,27 @@ func csvQuoteCount(s string) int {
 
 // EncodeCSV 将数据转换为 CSV 格式，可用于 redis 结果格式化。
 func EncodeCSV(data ...interface{}) string {
-	var buf bytes.Buffer
-	for i, arg := range data {
-		switch s := arg.(type) {
+	return strings.Join(appendCSV(nil, data), ",")
+}
+
+// appendCSV 将数据编码后追加到 s 中，嵌套的数组会被展开。
+func appendCSV(s []string, data []interface{}) []string {
+	for _, arg := range data {
+		switch v := arg.(type) {
 		case nil:
-			buf.WriteString("NULL")
+			s = append(s, "NULL")
 		case []interface{}:
-			return EncodeCSV(s...)
+			s = appendCSV(s, v)
 		case string:
-			if c := csvQuoteCount(s); c == 1 {
-				s = strconv.Quote(s)
+			if c := csvQuoteCount(v); c == 1 {
+				v = strconv.Quote(v)
 			}
-			buf.WriteString(strconv.Quote(s))
+			s = append(s, strconv.Quote(v))
 		default:
-			buf.WriteString(strconv.Quote(cast.ToString(arg)))
-		}
-		if i < len(data)-1 {
-			buf.WriteByte(',')
+			s = append(s, strconv.Quote(cast.ToString(arg)))
 		}
 	}
-	return buf.String()
+	return s
 }
 
 // DecodeCSV 将 CSV 格式的数据转换为字符串数组。
@@ -110,6 +112,10 @@ func DecodeCSV(data string) ([]string, error) {
 						c = '\b'
 					case 'a':
 						c = '\a'
+					case 'f':
+						c = '\f'
+					case 'v':
+						c = '\v'
 					}
 					buf.WriteByte(c)
 				} else if c == '"' {
@@ -132,6 +138,9 @@ func DecodeCSV(data string) ([]string, error) {
 					if inQuote {
 						return nil, errors.New("invalid syntax")
 					}
+					if buf.Len() > 0 {
+						done = true
+					}
 				case '"':
 					inQuote = true
 				case '\'':
@@ -232,6 +241,10 @@ func DecodeTTY(data string) ([]string, error) {
 						c = '\b'
 					case 'a':
 						c = '\a'
+					case 'f':
+						c = '\f'
+					case 'v':
+						c = '\v'
 					}
 					buf.WriteByte(c)
 				} else if c == '"' {
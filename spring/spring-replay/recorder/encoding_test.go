@@ -46,6 +46,20 @@ func TestCSV(t *testing.T) {
 	})
 }
 
+func TestCSV_Nested(t *testing.T) {
+	inputs := []interface{}{
+		"0",
+		[]interface{}{"a", nil, []interface{}{"1", 2}},
+		[]interface{}{},
+		"b",
+	}
+	data := recorder.EncodeCSV(inputs...)
+	assert.Equal(t, data, `"0","a",NULL,"1","2","b"`)
+	outputs, err := recorder.DecodeCSV(data)
+	assert.Nil(t, err)
+	assert.Equal(t, outputs, []string{"0", "a", "NULL", "1", "2", "b"})
+}
+
 func TestTTY(t *testing.T) {
 	inputs := []interface{}{
 		"CMD",
@@ -26,6 +26,7 @@ import (
 	"github.com/go-spring/spring-base/clock"
 	"github.com/go-spring/spring-base/knife"
 	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/run"
 	"github.com/go-spring/spring-base/util"
 )
 
@@ -61,7 +62,7 @@ func RecordMode() bool {
 
 // SetRecordMode 打开或者关闭录制模式，仅用于单元测试。
 func SetRecordMode(mode bool) {
-	util.MustTestMode()
+	run.MustTestMode()
 	recorder.mode = mode
 }
 
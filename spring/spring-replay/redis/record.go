@@ -16,27 +16,63 @@
 
 package redis
 
-//
-//type recordConn struct {
-//	conn ConnPool
-//}
-//
-//func (c *recordConn) Exec(ctx context.Context, cmd string, args []interface{}) (ret interface{}, err error) {
-//	defer func() {
-//		recorder.RecordAction(ctx, recorder.REDIS, &recorder.SimpleAction{
-//			Request: func() string {
-//				return recorder.EncodeTTY(append([]interface{}{cmd}, args...)...)
-//			},
-//			Response: func() string {
-//				if err == nil {
-//					return recorder.EncodeCSV(ret)
-//				}
-//				if IsErrNil(err) {
-//					return "NULL"
-//				}
-//				return "(err) " + err.Error()
-//			},
-//		})
-//	}()
-//	return c.conn.Exec(ctx, cmd, args)
-//}
+import (
+	"context"
+
+	"github.com/go-spring/spring-core/redis"
+	"github.com/go-spring/spring-replay/recorder"
+)
+
+// recordDriver 录制 redis 命令及其结果的 redis.Driver 。
+type recordDriver struct {
+	next redis.Driver
+}
+
+// NewRecorder 返回录制 redis 命令及其结果的 redis.Driver ，可以作为 redis.Recorder 。
+func NewRecorder(next redis.Driver) redis.Driver {
+	return &recordDriver{next: next}
+}
+
+func (d *recordDriver) Exec(ctx context.Context, args []interface{}) (ret interface{}, err error) {
+	defer func() {
+		recorder.RecordAction(ctx, recorder.REDIS, &recorder.SimpleAction{
+			Request: func() string {
+				return recorder.EncodeTTY(args...)
+			},
+			Response: func() string {
+				return encodeResponse(ret, err)
+			},
+		})
+	}()
+	return d.next.Exec(ctx, args)
+}
+
+// encodeResponse 返回 redis 命令结果的录制格式，空值为 NULL ，错误为 (err) 加上
+// 错误信息，其他为 CSV 格式。
+func encodeResponse(ret interface{}, err error) string {
+	if err != nil {
+		if redis.IsErrNil(err) {
+			return "NULL"
+		}
+		return "(err) " + err.Error()
+	}
+	switch r := ret.(type) {
+	case *redis.Result:
+		return encodeStrings(r.Data)
+	case []string:
+		return encodeStrings(r)
+	}
+	return recorder.EncodeCSV(ret)
+}
+
+func encodeStrings(data []string) string {
+	s := make([]interface{}, len(data))
+	for i, v := range data {
+		if v == "NULL" {
+			s[i] = nil
+		} else {
+			s[i] = v
+		}
+	}
+	return recorder.EncodeCSV(s...)
+}
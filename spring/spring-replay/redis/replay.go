@@ -16,66 +16,90 @@
 
 package redis
 
-//
-//func init() {
-//	recorder.RegisterProtocol(recorder.REDIS, &protocol{})
-//}
-//
-//type replayResult struct {
-//	data []string
-//}
-//
-//type replayConn struct {
-//	conn ConnPool
-//}
-//
-//func (c *replayConn) Exec(ctx context.Context, cmd string, args []interface{}) (interface{}, error) {
-//
-//	req := recorder.EncodeTTY(append([]interface{}{cmd}, args...)...)
-//	response, ok, err := replayer.BestQuery(ctx, recorder.REDIS, req)
-//	if err != nil {
-//		return nil, err
-//	}
-//	if !ok {
-//		return c.conn.Exec(ctx, cmd, args)
-//	}
-//
-//	csv, err := recorder.DecodeCSV(response)
-//	if err != nil {
-//		return nil, err
-//	}
-//
-//	if len(csv) == 1 {
-//		s := csv[0]
-//		if s == "NULL" {
-//			return nil, ErrNil()
-//		}
-//		if strings.HasPrefix(s, "(err) ") {
-//			return nil, errors.New(strings.TrimPrefix(s, "(err) "))
-//		}
-//	}
-//
-//	return &replayResult{csv}, nil
-//}
-//
-//type protocol struct{}
-//
-//func (p *protocol) GetLabel(data string) string {
-//	return strings.SplitN(data, " ", 2)[0]
-//}
-//
-//func (p *protocol) FlatRequest(data string) (map[string]string, error) {
-//	csv, err := recorder.DecodeTTY(data)
-//	if err != nil {
-//		return nil, err
-//	}
-//	return recorder.FlatJSON(csv), nil
-//}
-//
-//func (p *protocol) FlatResponse(data string) (map[string]string, error) {
-//	csv, err := recorder.DecodeCSV(data)
-//	if err != nil {
-//		return nil, err
-//	}
-//	return recorder.FlatJSON(csv), nil
-//}
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/go-spring/spring-base/run"
+	"github.com/go-spring/spring-core/redis"
+	"github.com/go-spring/spring-replay/recorder"
+	"github.com/go-spring/spring-replay/replayer"
+)
+
+func init() {
+	recorder.RegisterProtocol(recorder.REDIS, &protocol{})
+	if run.RecordMode() {
+		redis.Recorder = NewRecorder
+	}
+	if run.ReplayMode() {
+		redis.Replayer = NewReplayer
+	}
+}
+
+// replayDriver 使用录制的数据作为 redis 命令结果的 redis.Driver 。
+type replayDriver struct {
+	next redis.Driver
+}
+
+// NewReplayer 返回使用录制的数据作为 redis 命令结果的 redis.Driver ，可以作为
+// redis.Replayer 。没有匹配的数据或者不在回放会话中时执行真实的 redis 命令，
+// next 为 nil 时返回错误。
+func NewReplayer(next redis.Driver) redis.Driver {
+	return &replayDriver{next: next}
+}
+
+func (d *replayDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	if _, err := replayer.GetSessionID(ctx); err == nil {
+		req := recorder.EncodeTTY(args...)
+		resp, ok, err := replayer.BestQuery(ctx, recorder.REDIS, req)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return decodeResponse(resp)
+		}
+	}
+	if d.next == nil {
+		return nil, errors.New("no recorded data found")
+	}
+	return d.next.Exec(ctx, args)
+}
+
+// decodeResponse 将录制格式的数据还原为 redis 命令的结果。
+func decodeResponse(resp string) (interface{}, error) {
+	if strings.EqualFold(resp, "NULL") {
+		return nil, redis.ErrNil()
+	}
+	if strings.HasPrefix(resp, "(err) ") {
+		return nil, errors.New(strings.TrimPrefix(resp, "(err) "))
+	}
+	csv, err := recorder.DecodeCSV(resp)
+	if err != nil {
+		return nil, err
+	}
+	return redis.NewResult(csv...), nil
+}
+
+type protocol struct{}
+
+// GetLabel 返回 redis 命令的名称。
+func (p *protocol) GetLabel(data string) string {
+	return strings.ToUpper(strings.SplitN(data, " ", 2)[0])
+}
+
+func (p *protocol) FlatRequest(data string) (map[string]string, error) {
+	csv, err := recorder.DecodeTTY(data)
+	if err != nil {
+		return nil, err
+	}
+	return recorder.FlatJSON(csv), nil
+}
+
+func (p *protocol) FlatResponse(data string) (map[string]string, error) {
+	csv, err := recorder.DecodeCSV(data)
+	if err != nil {
+		return nil, err
+	}
+	return recorder.FlatJSON(csv), nil
+}
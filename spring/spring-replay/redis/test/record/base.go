@@ -14,53 +14,44 @@
  * limitations under the License.
  */
 
+// Package record 使用真实的 redis 服务录制测试用例，并和用例中的录制数据进行比较。
 package record
 
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-base/clock"
+	"github.com/go-spring/spring-base/knife"
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-replay/recorder"
+	replay "github.com/go-spring/spring-replay/redis"
 )
 
-func flushAll(conn redis.ConnPool) (string, error) {
-	c, err := redis.NewClient(conn)
-	if err != nil {
-		return "", err
-	}
-	return c.OpsForServer().FlushAll(context.Background())
-}
+// RunCase 清空 redis 数据之后使用 d 执行测试用例 c ，并将录制的数据和 c.Data 进行比较。
+func RunCase(t *testing.T, d redis.Driver, c *redis.Case) {
+
+	_, err := redis.NewClient(d).FlushAll(context.Background())
+	assert.Nil(t, err)
+
+	recorder.SetRecordMode(true)
+	defer func() {
+		recorder.SetRecordMode(false)
+	}()
+
+	ctx, _ := knife.New(context.Background())
+	err = clock.SetFixedTime(ctx, time.Unix(0, 0))
+	assert.Nil(t, err)
+
+	recorder.StartRecord(ctx, func() (string, error) {
+		return "df3b64266ebe4e63a464e135000a07cd", nil
+	})
 
-func RunCase(t *testing.T, conn redis.ConnPool, c cases.Case) {
+	c.Func(t, ctx, redis.NewClient(replay.NewRecorder(d)))
 
-	//ok, err := flushAll(conn)
-	//assert.Nil(t, err)
-	//assert.True(t, redis.IsOK(ok))
-	//
-	//recorder.SetRecordMode(true)
-	//defer func() {
-	//	recorder.SetRecordMode(false)
-	//}()
-	//
-	//client, err := redis.NewClient(conn)
-	//assert.Nil(t, err)
-	//
-	//ctx, _ := knife.New(context.Background())
-	//err = clock.SetFixedTime(ctx, time.Unix(0, 0))
-	//assert.Nil(t, err)
-	//
-	//recorder.StartRecord(ctx, func() (string, error) {
-	//	return "df3b64266ebe4e63a464e135000a07cd", nil
-	//})
-	//
-	//c.Func(t, ctx, client)
-	//
-	//session := recorder.StopRecord(ctx)
-	//if c.Skip {
-	//	return
-	//}
-	//
-	//str := recorder.ToPrettyJson(session)
-	//assert.JsonEqual(t, str, c.Data)
+	session := recorder.StopRecord(ctx)
+	str := recorder.ToPrettyJson(session)
+	assert.JsonEqual(t, str, c.Data)
 }
@@ -14,23 +14,51 @@
  * limitations under the License.
  */
 
-package record
+package record_test
 
 import (
 	"context"
+	"errors"
+	"os/exec"
+	"strings"
 	"testing"
 
+	"github.com/go-spring/spring-base/cast"
 	"github.com/go-spring/spring-core/redis"
-	SpringGoRedis "github.com/go-spring/spring-go-redis"
+	"github.com/go-spring/spring-replay/recorder"
 )
 
-func runCase(t *testing.T, c *redis.Case) {
-	d, err := SpringGoRedis.Open(redis.Config{Port: 6379})
+// cliDriver 使用 redis-cli 执行 redis 命令。
+type cliDriver struct{}
+
+func (d *cliDriver) Exec(ctx context.Context, args []interface{}) (interface{}, error) {
+	cmdArgs := []string{"--csv"}
+	for _, arg := range args {
+		cmdArgs = append(cmdArgs, cast.ToString(arg))
+	}
+	output, err := exec.CommandContext(ctx, "redis-cli", cmdArgs...).CombinedOutput()
 	if err != nil {
-		t.Fatal(err)
+		return nil, err
+	}
+	csv, err := recorder.DecodeCSV(strings.TrimSuffix(string(output), "\n"))
+	if err != nil {
+		return nil, err
+	}
+	if len(csv) == 1 && csv[0] == "NULL" {
+		return nil, redis.ErrNil()
+	}
+	if len(csv) > 1 && csv[0] == "ERROR" {
+		return nil, errors.New(csv[1])
+	}
+	return redis.NewResult(csv...), nil
+}
+
+// liveDriver 返回连接本地 redis 服务的 redis.Driver ，录制测试需要真实的
+// redis 服务，没有安装 redis-cli 或者 redis 服务不可用时跳过测试。
+func liveDriver(t *testing.T) redis.Driver {
+	output, err := exec.Command("redis-cli", "ping").Output()
+	if err != nil || strings.TrimSpace(string(output)) != "PONG" {
+		t.Skip("no live redis server found")
 	}
-	ctx := context.Background()
-	client := redis.NewClient(d)
-	client.FlushAll(ctx)
-	c.Func(t, ctx, client)
+	return &cliDriver{}
 }
@@ -20,25 +20,36 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
 )
 
-func BitCount(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.BitCount)
+func BitCount(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).BitCount())
 }
 
-func BitOpAnd(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.BitOpAnd)
+func BitOpAnd(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).BitOpAnd())
 }
 
-func BitPos(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.BitPos)
+func BitOpOr(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).BitOpOr())
 }
 
-func GetBit(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.GetBit)
+func BitOpXor(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).BitOpXor())
 }
 
-func SetBit(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SetBit)
+func BitOpNot(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).BitOpNot())
+}
+
+func BitPos(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).BitPos())
+}
+
+func GetBit(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).GetBit())
+}
+
+func SetBit(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SetBit())
 }
@@ -14,30 +14,42 @@
  * limitations under the License.
  */
 
-package record
+package record_test
 
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis"
+	"github.com/go-spring/spring-replay/redis/test/record"
 )
 
 func TestBitCount(t *testing.T) {
-	runCase(t, new(redis.Cases).BitCount())
-}
-
-//func TestBitOpAnd(t *testing.T) {
-//	RunCase(t, record.BitOpAnd)
-//}
-//
-//func TestBitPos(t *testing.T) {
-//	RunCase(t, record.BitPos)
-//}
-//
-//func TestGetBit(t *testing.T) {
-//	RunCase(t, record.GetBit)
-//}
-//
-//func TestSetBit(t *testing.T) {
-//	RunCase(t, record.SetBit)
-//}
+	record.BitCount(t, liveDriver(t))
+}
+
+func TestBitOpAnd(t *testing.T) {
+	record.BitOpAnd(t, liveDriver(t))
+}
+
+func TestBitOpOr(t *testing.T) {
+	record.BitOpOr(t, liveDriver(t))
+}
+
+func TestBitOpXor(t *testing.T) {
+	record.BitOpXor(t, liveDriver(t))
+}
+
+func TestBitOpNot(t *testing.T) {
+	record.BitOpNot(t, liveDriver(t))
+}
+
+func TestBitPos(t *testing.T) {
+	record.BitPos(t, liveDriver(t))
+}
+
+func TestGetBit(t *testing.T) {
+	record.GetBit(t, liveDriver(t))
+}
+
+func TestSetBit(t *testing.T) {
+	record.SetBit(t, liveDriver(t))
+}
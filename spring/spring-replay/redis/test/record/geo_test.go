@@ -0,0 +1,47 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package record_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-replay/redis/test/record"
+)
+
+func TestGeoAdd(t *testing.T) {
+	record.GeoAdd(t, liveDriver(t))
+}
+
+func TestGeoDist(t *testing.T) {
+	record.GeoDist(t, liveDriver(t))
+}
+
+func TestGeoHash(t *testing.T) {
+	record.GeoHash(t, liveDriver(t))
+}
+
+func TestGeoPos(t *testing.T) {
+	record.GeoPos(t, liveDriver(t))
+}
+
+func TestGeoSearch(t *testing.T) {
+	record.GeoSearch(t, liveDriver(t))
+}
+
+func TestGeoSearchStore(t *testing.T) {
+	record.GeoSearchStore(t, liveDriver(t))
+}
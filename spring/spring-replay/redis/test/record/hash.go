@@ -20,57 +20,60 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
 )
 
-func HDel(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HDel)
+func HDel(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HDel())
 }
 
-func HExists(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HExists)
+func HExists(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HExists())
 }
 
-func HGet(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HGet)
+func HGet(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HGet())
 }
 
-func HGetAll(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HGetAll)
+func HGetAll(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HGetAll())
 }
 
-func HIncrBy(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HIncrBy)
+func HIncrBy(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HIncrBy())
 }
 
-func HIncrByFloat(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HIncrByFloat)
+func HIncrByFloat(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HIncrByFloat())
 }
 
-func HKeys(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HKeys)
+func HKeys(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HKeys())
 }
 
-func HLen(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HLen)
+func HLen(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HLen())
 }
 
-func HMGet(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HMGet)
+func HMGet(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HMGet())
 }
 
-func HSet(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HSet)
+func HScan(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HScan())
 }
 
-func HSetNX(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HSetNX)
+func HSet(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HSet())
 }
 
-func HStrLen(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HStrLen)
+func HSetNX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HSetNX())
 }
 
-func HVals(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.HVals)
+func HStrLen(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HStrLen())
+}
+
+func HVals(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).HVals())
 }
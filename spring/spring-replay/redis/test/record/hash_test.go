@@ -14,57 +14,66 @@
  * limitations under the License.
  */
 
-package record
-
-//
-//func TestHDel(t *testing.T) {
-//	RunCase(t, record.HDel)
-//}
-//
-//func TestHExists(t *testing.T) {
-//	RunCase(t, record.HExists)
-//}
-//
-//func TestHGet(t *testing.T) {
-//	RunCase(t, record.HGet)
-//}
-//
-//func TestHGetAll(t *testing.T) {
-//	RunCase(t, record.HGetAll)
-//}
-//
-//func TestHIncrBy(t *testing.T) {
-//	RunCase(t, record.HIncrBy)
-//}
-//
-//func TestHIncrByFloat(t *testing.T) {
-//	RunCase(t, record.HIncrByFloat)
-//}
-//
-//func TestHKeys(t *testing.T) {
-//	RunCase(t, record.HKeys)
-//}
-//
-//func TestHLen(t *testing.T) {
-//	RunCase(t, record.HLen)
-//}
-//
-//func TestHMGet(t *testing.T) {
-//	RunCase(t, record.HMGet)
-//}
-//
-//func TestHSet(t *testing.T) {
-//	RunCase(t, record.HSet)
-//}
-//
-//func TestHSetNX(t *testing.T) {
-//	RunCase(t, record.HSetNX)
-//}
-//
-//func TestHStrLen(t *testing.T) {
-//	RunCase(t, record.HStrLen)
-//}
-//
-//func TestHVals(t *testing.T) {
-//	RunCase(t, record.HVals)
-//}
+package record_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-replay/redis/test/record"
+)
+
+func TestHDel(t *testing.T) {
+	record.HDel(t, liveDriver(t))
+}
+
+func TestHExists(t *testing.T) {
+	record.HExists(t, liveDriver(t))
+}
+
+func TestHGet(t *testing.T) {
+	record.HGet(t, liveDriver(t))
+}
+
+func TestHGetAll(t *testing.T) {
+	record.HGetAll(t, liveDriver(t))
+}
+
+func TestHIncrBy(t *testing.T) {
+	record.HIncrBy(t, liveDriver(t))
+}
+
+func TestHIncrByFloat(t *testing.T) {
+	record.HIncrByFloat(t, liveDriver(t))
+}
+
+func TestHKeys(t *testing.T) {
+	record.HKeys(t, liveDriver(t))
+}
+
+func TestHLen(t *testing.T) {
+	record.HLen(t, liveDriver(t))
+}
+
+func TestHMGet(t *testing.T) {
+	record.HMGet(t, liveDriver(t))
+}
+
+func TestHScan(t *testing.T) {
+	record.HScan(t, liveDriver(t))
+}
+
+func TestHSet(t *testing.T) {
+	record.HSet(t, liveDriver(t))
+}
+
+func TestHSetNX(t *testing.T) {
+	record.HSetNX(t, liveDriver(t))
+}
+
+func TestHStrLen(t *testing.T) {
+	record.HStrLen(t, liveDriver(t))
+}
+
+func TestHVals(t *testing.T) {
+	record.HVals(t, liveDriver(t))
+}
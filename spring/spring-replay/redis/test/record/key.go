@@ -20,65 +20,72 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
 )
 
-func Del(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Del)
+func Del(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Del())
 }
 
-func Dump(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Dump)
+func Dump(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Dump())
 }
 
-func Exists(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Exists)
+func Exists(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Exists())
 }
 
-func Expire(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Expire)
+func Expire(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Expire())
 }
 
-func ExpireAt(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ExpireAt)
+func ExpireAt(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ExpireAt())
 }
 
-func Keys(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Keys)
+func Keys(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Keys())
 }
 
-func Persist(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Persist)
+func Persist(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Persist())
 }
 
-func PExpire(t *testing.T, conn redis.ConnPool) {
-	// RunCase(t, conn, cases.PExpire)
+func PExpire(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).PExpire())
 }
 
-func PExpireAt(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.PExpireAt)
+func PExpireAt(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).PExpireAt())
 }
 
-func PTTL(t *testing.T, conn redis.ConnPool) {
-	// RunCase(t, conn, cases.PTTL)
+func PTTL(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).PTTL())
 }
 
-func Rename(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Rename)
+func RandomKey(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).RandomKey())
 }
 
-func RenameNX(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.RenameNX)
+func Rename(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Rename())
 }
 
-func Touch(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Touch)
+func RenameNX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).RenameNX())
 }
 
-func TTL(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.TTL)
+func Scan(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Scan())
 }
 
-func Type(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Type)
+func Touch(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Touch())
+}
+
+func TTL(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).TTL())
+}
+
+func Type(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Type())
 }
@@ -14,65 +14,78 @@
  * limitations under the License.
  */
 
-package record
-
-//
-//func TestDel(t *testing.T) {
-//	RunCase(t, record.Del)
-//}
-//
-//func TestDump(t *testing.T) {
-//	RunCase(t, record.Dump)
-//}
-//
-//func TestExists(t *testing.T) {
-//	RunCase(t, record.Exists)
-//}
-//
-//func TestExpire(t *testing.T) {
-//	RunCase(t, record.Expire)
-//}
-//
-//func TestExpireAt(t *testing.T) {
-//	RunCase(t, record.ExpireAt)
-//}
-//
-//func TestKeys(t *testing.T) {
-//	RunCase(t, record.Keys)
-//}
-//
-//func TestPersist(t *testing.T) {
-//	RunCase(t, record.Persist)
-//}
-//
-//func TestPExpire(t *testing.T) {
-//	RunCase(t, record.PExpire)
-//}
-//
-//func TestPExpireAt(t *testing.T) {
-//	RunCase(t, record.PExpireAt)
-//}
-//
-//func TestPTTL(t *testing.T) {
-//	RunCase(t, record.PTTL)
-//}
-//
-//func TestRename(t *testing.T) {
-//	RunCase(t, record.Rename)
-//}
-//
-//func TestRenameNX(t *testing.T) {
-//	RunCase(t, record.RenameNX)
-//}
-//
-//func TestTouch(t *testing.T) {
-//	RunCase(t, record.Touch)
-//}
-//
-//func TestTTL(t *testing.T) {
-//	RunCase(t, record.TTL)
-//}
-//
-//func TestType(t *testing.T) {
-//	RunCase(t, record.Type)
-//}
+package record_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-replay/redis/test/record"
+)
+
+func TestDel(t *testing.T) {
+	record.Del(t, liveDriver(t))
+}
+
+func TestDump(t *testing.T) {
+	record.Dump(t, liveDriver(t))
+}
+
+func TestExists(t *testing.T) {
+	record.Exists(t, liveDriver(t))
+}
+
+func TestExpire(t *testing.T) {
+	record.Expire(t, liveDriver(t))
+}
+
+func TestExpireAt(t *testing.T) {
+	record.ExpireAt(t, liveDriver(t))
+}
+
+func TestKeys(t *testing.T) {
+	record.Keys(t, liveDriver(t))
+}
+
+func TestPersist(t *testing.T) {
+	record.Persist(t, liveDriver(t))
+}
+
+func TestPExpire(t *testing.T) {
+	record.PExpire(t, liveDriver(t))
+}
+
+func TestPExpireAt(t *testing.T) {
+	record.PExpireAt(t, liveDriver(t))
+}
+
+func TestPTTL(t *testing.T) {
+	record.PTTL(t, liveDriver(t))
+}
+
+func TestRandomKey(t *testing.T) {
+	record.RandomKey(t, liveDriver(t))
+}
+
+func TestRename(t *testing.T) {
+	record.Rename(t, liveDriver(t))
+}
+
+func TestRenameNX(t *testing.T) {
+	record.RenameNX(t, liveDriver(t))
+}
+
+func TestScan(t *testing.T) {
+	record.Scan(t, liveDriver(t))
+}
+
+func TestTouch(t *testing.T) {
+	record.Touch(t, liveDriver(t))
+}
+
+func TestTTL(t *testing.T) {
+	record.TTL(t, liveDriver(t))
+}
+
+func TestType(t *testing.T) {
+	record.Type(t, liveDriver(t))
+}
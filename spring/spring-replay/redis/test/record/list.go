@@ -20,69 +20,72 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
 )
 
-func LIndex(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LIndex)
+func LIndex(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LIndex())
 }
 
-func LInsert(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LInsert)
+func LInsertBefore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LInsertBefore())
 }
 
-func LLen(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LLen)
+func LInsertAfter(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LInsertAfter())
 }
 
-func LMove(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LMove)
+func LLen(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LLen())
 }
 
-func LPop(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LPop)
+func LMove(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LMove())
 }
 
-func LPos(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LPos)
+func LPop(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LPop())
 }
 
-func LPush(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LPush)
+func LPos(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LPos())
 }
 
-func LPushX(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LPushX)
+func LPush(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LPush())
 }
 
-func LRange(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LRange)
+func LPushX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LPushX())
 }
 
-func LRem(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LRem)
+func LRange(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LRange())
 }
 
-func LSet(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LSet)
+func LRem(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LRem())
 }
 
-func LTrim(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.LTrim)
+func LSet(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LSet())
 }
 
-func RPop(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.RPop)
+func LTrim(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).LTrim())
 }
 
-func RPopLPush(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.RPopLPush)
+func RPop(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).RPop())
 }
 
-func RPush(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.RPush)
+func RPopLPush(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).RPopLPush())
 }
 
-func RPushX(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.RPushX)
+func RPush(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).RPush())
+}
+
+func RPushX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).RPushX())
 }
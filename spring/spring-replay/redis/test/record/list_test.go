@@ -14,69 +14,78 @@
  * limitations under the License.
  */
 
-package record
-
-//
-//func TestLIndex(t *testing.T) {
-//	RunCase(t, record.LIndex)
-//}
-//
-//func TestLInsert(t *testing.T) {
-//	RunCase(t, record.LInsert)
-//}
-//
-//func TestLLen(t *testing.T) {
-//	RunCase(t, record.LLen)
-//}
-//
-//func TestLMove(t *testing.T) {
-//	RunCase(t, record.LMove)
-//}
-//
-//func TestLPop(t *testing.T) {
-//	RunCase(t, record.LPop)
-//}
-//
-//func TestLPos(t *testing.T) {
-//	RunCase(t, record.LPos)
-//}
-//
-//func TestLPush(t *testing.T) {
-//	RunCase(t, record.LPush)
-//}
-//
-//func TestLPushX(t *testing.T) {
-//	RunCase(t, record.LPushX)
-//}
-//
-//func TestLRange(t *testing.T) {
-//	RunCase(t, record.LRange)
-//}
-//
-//func TestLRem(t *testing.T) {
-//	RunCase(t, record.LRem)
-//}
-//
-//func TestLSet(t *testing.T) {
-//	RunCase(t, record.LSet)
-//}
-//
-//func TestLTrim(t *testing.T) {
-//	RunCase(t, record.LTrim)
-//}
-//
-//func TestRPop(t *testing.T) {
-//	RunCase(t, record.RPop)
-//}
-//
-//func TestRPopLPush(t *testing.T) {
-//	RunCase(t, record.RPopLPush)
-//}
-//
-//func TestRPush(t *testing.T) {
-//	RunCase(t, record.RPush)
-//}
-//
-//func TestRPushX(t *testing.T) {
-//	RunCase(t, record.RPushX)
-//}
+package record_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-replay/redis/test/record"
+)
+
+func TestLIndex(t *testing.T) {
+	record.LIndex(t, liveDriver(t))
+}
+
+func TestLInsertBefore(t *testing.T) {
+	record.LInsertBefore(t, liveDriver(t))
+}
+
+func TestLInsertAfter(t *testing.T) {
+	record.LInsertAfter(t, liveDriver(t))
+}
+
+func TestLLen(t *testing.T) {
+	record.LLen(t, liveDriver(t))
+}
+
+func TestLMove(t *testing.T) {
+	record.LMove(t, liveDriver(t))
+}
+
+func TestLPop(t *testing.T) {
+	record.LPop(t, liveDriver(t))
+}
+
+func TestLPos(t *testing.T) {
+	record.LPos(t, liveDriver(t))
+}
+
+func TestLPush(t *testing.T) {
+	record.LPush(t, liveDriver(t))
+}
+
+func TestLPushX(t *testing.T) {
+	record.LPushX(t, liveDriver(t))
+}
+
+func TestLRange(t *testing.T) {
+	record.LRange(t, liveDriver(t))
+}
+
+func TestLRem(t *testing.T) {
+	record.LRem(t, liveDriver(t))
+}
+
+func TestLSet(t *testing.T) {
+	record.LSet(t, liveDriver(t))
+}
+
+func TestLTrim(t *testing.T) {
+	record.LTrim(t, liveDriver(t))
+}
+
+func TestRPop(t *testing.T) {
+	record.RPop(t, liveDriver(t))
+}
+
+func TestRPopLPush(t *testing.T) {
+	record.RPopLPush(t, liveDriver(t))
+}
+
+func TestRPush(t *testing.T) {
+	record.RPush(t, liveDriver(t))
+}
+
+func TestRPushX(t *testing.T) {
+	record.RPushX(t, liveDriver(t))
+}
@@ -20,61 +20,72 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
 )
 
-func SAdd(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SAdd)
+func SAdd(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SAdd())
 }
 
-func SCard(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SCard)
+func SCard(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SCard())
 }
 
-func SDiff(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SDiff)
+func SDiff(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SDiff())
 }
 
-func SDiffStore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SDiffStore)
+func SDiffStore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SDiffStore())
 }
 
-func SInter(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SInter)
+func SInter(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SInter())
 }
 
-func SInterStore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SInterStore)
+func SInterStore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SInterStore())
 }
 
-func SMembers(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SMembers)
+func SIsMember(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SIsMember())
 }
 
-func SMIsMember(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SMIsMember)
+func SMembers(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SMembers())
 }
 
-func SMove(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SMove)
+func SMIsMember(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SMIsMember())
 }
 
-func SPop(t *testing.T, conn redis.ConnPool) {
-	// RunCase(t, conn, cases.SPop)
+func SMove(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SMove())
 }
 
-func SRandMember(t *testing.T, conn redis.ConnPool) {
-	//RunCase(t, conn, cases.SRandMember)
+func SPop(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SPop())
 }
 
-func SRem(t *testing.T, conn redis.ConnPool) {
-	// RunCase(t, conn, cases.SRem)
+func SPopN(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SPopN())
 }
 
-func SUnion(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SUnion)
+func SRandMember(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SRandMember())
 }
 
-func SUnionStore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SUnionStore)
+func SRem(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SRem())
+}
+
+func SScan(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SScan())
+}
+
+func SUnion(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SUnion())
+}
+
+func SUnionStore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SUnionStore())
 }
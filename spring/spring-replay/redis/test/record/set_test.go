@@ -14,61 +14,78 @@
  * limitations under the License.
  */
 
-package record
-
-//
-//func TestSAdd(t *testing.T) {
-//	RunCase(t, record.SAdd)
-//}
-//
-//func TestSCard(t *testing.T) {
-//	RunCase(t, record.SCard)
-//}
-//
-//func TestSDiff(t *testing.T) {
-//	RunCase(t, record.SDiff)
-//}
-//
-//func TestSDiffStore(t *testing.T) {
-//	RunCase(t, record.SDiffStore)
-//}
-//
-//func TestSInter(t *testing.T) {
-//	RunCase(t, record.SInter)
-//}
-//
-//func TestSInterStore(t *testing.T) {
-//	RunCase(t, record.SInterStore)
-//}
-//
-//func TestSMembers(t *testing.T) {
-//	RunCase(t, record.SMembers)
-//}
-//
-//func TestSMIsMember(t *testing.T) {
-//	RunCase(t, record.SMIsMember)
-//}
-//
-//func TestSMove(t *testing.T) {
-//	RunCase(t, record.SMove)
-//}
-//
-//func TestSPop(t *testing.T) {
-//	RunCase(t, record.SPop)
-//}
-//
-//func TestSRandMember(t *testing.T) {
-//	RunCase(t, record.SRandMember)
-//}
-//
-//func TestSRem(t *testing.T) {
-//	RunCase(t, record.SRem)
-//}
-//
-//func TestSUnion(t *testing.T) {
-//	RunCase(t, record.SUnion)
-//}
-//
-//func TestSUnionStore(t *testing.T) {
-//	RunCase(t, record.SUnionStore)
-//}
+package record_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-replay/redis/test/record"
+)
+
+func TestSAdd(t *testing.T) {
+	record.SAdd(t, liveDriver(t))
+}
+
+func TestSCard(t *testing.T) {
+	record.SCard(t, liveDriver(t))
+}
+
+func TestSDiff(t *testing.T) {
+	record.SDiff(t, liveDriver(t))
+}
+
+func TestSDiffStore(t *testing.T) {
+	record.SDiffStore(t, liveDriver(t))
+}
+
+func TestSInter(t *testing.T) {
+	record.SInter(t, liveDriver(t))
+}
+
+func TestSInterStore(t *testing.T) {
+	record.SInterStore(t, liveDriver(t))
+}
+
+func TestSIsMember(t *testing.T) {
+	record.SIsMember(t, liveDriver(t))
+}
+
+func TestSMembers(t *testing.T) {
+	record.SMembers(t, liveDriver(t))
+}
+
+func TestSMIsMember(t *testing.T) {
+	record.SMIsMember(t, liveDriver(t))
+}
+
+func TestSMove(t *testing.T) {
+	record.SMove(t, liveDriver(t))
+}
+
+func TestSPop(t *testing.T) {
+	record.SPop(t, liveDriver(t))
+}
+
+func TestSPopN(t *testing.T) {
+	record.SPopN(t, liveDriver(t))
+}
+
+func TestSRandMember(t *testing.T) {
+	record.SRandMember(t, liveDriver(t))
+}
+
+func TestSRem(t *testing.T) {
+	record.SRem(t, liveDriver(t))
+}
+
+func TestSScan(t *testing.T) {
+	record.SScan(t, liveDriver(t))
+}
+
+func TestSUnion(t *testing.T) {
+	record.SUnion(t, liveDriver(t))
+}
+
+func TestSUnionStore(t *testing.T) {
+	record.SUnionStore(t, liveDriver(t))
+}
@@ -20,81 +20,84 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
 )
 
-func Append(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Append)
+func Append(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Append())
 }
 
-func Decr(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Decr)
+func Decr(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Decr())
 }
 
-func DecrBy(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.DecrBy)
+func DecrBy(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).DecrBy())
 }
 
-func Get(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Get)
+func Get(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Get())
 }
 
-func GetDel(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.GetDel)
+func GetDel(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).GetDel())
 }
 
-func GetRange(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.GetRange)
+func GetEx(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).GetEx())
 }
 
-func GetSet(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.GetSet)
+func GetRange(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).GetRange())
 }
 
-func Incr(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Incr)
+func GetSet(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).GetSet())
 }
 
-func IncrBy(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.IncrBy)
+func Incr(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Incr())
 }
 
-func IncrByFloat(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.IncrByFloat)
+func IncrBy(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).IncrBy())
 }
 
-func MGet(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.MGet)
+func IncrByFloat(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).IncrByFloat())
 }
 
-func MSet(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.MSet)
+func MGet(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).MGet())
 }
 
-func MSetNX(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.MSetNX)
+func MSet(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).MSet())
 }
 
-func PSetEX(t *testing.T, conn redis.ConnPool) {
-	// RunCase(t, conn, cases.PSetEX)
+func MSetNX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).MSetNX())
 }
 
-func Set(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.Set)
+func PSetEX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).PSetEX())
 }
 
-func SetEX(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SetEX)
+func Set(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).Set())
 }
 
-func SetNX(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SetNX)
+func SetEX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SetEX())
 }
 
-func SetRange(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.SetRange)
+func SetNX(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SetNX())
 }
 
-func StrLen(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.StrLen)
+func SetRange(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).SetRange())
+}
+
+func StrLen(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).StrLen())
 }
@@ -14,81 +14,90 @@
  * limitations under the License.
  */
 
-package record
-
-//
-//func TestAppend(t *testing.T) {
-//	RunCase(t, record.Append)
-//}
-//
-//func TestDecr(t *testing.T) {
-//	RunCase(t, record.Decr)
-//}
-//
-//func TestDecrBy(t *testing.T) {
-//	RunCase(t, record.DecrBy)
-//}
-//
-//func TestGet(t *testing.T) {
-//	RunCase(t, record.Get)
-//}
-//
-//func TestGetDel(t *testing.T) {
-//	RunCase(t, record.GetDel)
-//}
-//
-//func TestGetRange(t *testing.T) {
-//	RunCase(t, record.GetRange)
-//}
-//
-//func TestGetSet(t *testing.T) {
-//	RunCase(t, record.GetSet)
-//}
-//
-//func TestIncr(t *testing.T) {
-//	RunCase(t, record.Incr)
-//}
-//
-//func TestIncrBy(t *testing.T) {
-//	RunCase(t, record.IncrBy)
-//}
-//
-//func TestIncrByFloat(t *testing.T) {
-//	RunCase(t, record.IncrByFloat)
-//}
-//
-//func TestMGet(t *testing.T) {
-//	RunCase(t, record.MGet)
-//}
-//
-//func TestMSet(t *testing.T) {
-//	RunCase(t, record.MSet)
-//}
-//
-//func TestMSetNX(t *testing.T) {
-//	RunCase(t, record.MSetNX)
-//}
-//
-//func TestPSetEX(t *testing.T) {
-//	RunCase(t, record.PSetEX)
-//}
-//
-//func TestSet(t *testing.T) {
-//	RunCase(t, record.Set)
-//}
-//
-//func TestSetEX(t *testing.T) {
-//	RunCase(t, record.SetEX)
-//}
-//
-//func TestSetNX(t *testing.T) {
-//	RunCase(t, record.SetNX)
-//}
-//
-//func TestSetRange(t *testing.T) {
-//	RunCase(t, record.SetRange)
-//}
-//
-//func TestStrLen(t *testing.T) {
-//	RunCase(t, record.StrLen)
-//}
+package record_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-replay/redis/test/record"
+)
+
+func TestAppend(t *testing.T) {
+	record.Append(t, liveDriver(t))
+}
+
+func TestDecr(t *testing.T) {
+	record.Decr(t, liveDriver(t))
+}
+
+func TestDecrBy(t *testing.T) {
+	record.DecrBy(t, liveDriver(t))
+}
+
+func TestGet(t *testing.T) {
+	record.Get(t, liveDriver(t))
+}
+
+func TestGetDel(t *testing.T) {
+	record.GetDel(t, liveDriver(t))
+}
+
+func TestGetEx(t *testing.T) {
+	record.GetEx(t, liveDriver(t))
+}
+
+func TestGetRange(t *testing.T) {
+	record.GetRange(t, liveDriver(t))
+}
+
+func TestGetSet(t *testing.T) {
+	record.GetSet(t, liveDriver(t))
+}
+
+func TestIncr(t *testing.T) {
+	record.Incr(t, liveDriver(t))
+}
+
+func TestIncrBy(t *testing.T) {
+	record.IncrBy(t, liveDriver(t))
+}
+
+func TestIncrByFloat(t *testing.T) {
+	record.IncrByFloat(t, liveDriver(t))
+}
+
+func TestMGet(t *testing.T) {
+	record.MGet(t, liveDriver(t))
+}
+
+func TestMSet(t *testing.T) {
+	record.MSet(t, liveDriver(t))
+}
+
+func TestMSetNX(t *testing.T) {
+	record.MSetNX(t, liveDriver(t))
+}
+
+func TestPSetEX(t *testing.T) {
+	record.PSetEX(t, liveDriver(t))
+}
+
+func TestSet(t *testing.T) {
+	record.Set(t, liveDriver(t))
+}
+
+func TestSetEX(t *testing.T) {
+	record.SetEX(t, liveDriver(t))
+}
+
+func TestSetNX(t *testing.T) {
+	record.SetNX(t, liveDriver(t))
+}
+
+func TestSetRange(t *testing.T) {
+	record.SetRange(t, liveDriver(t))
+}
+
+func TestStrLen(t *testing.T) {
+	record.StrLen(t, liveDriver(t))
+}
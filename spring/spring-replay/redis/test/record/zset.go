@@ -20,109 +20,128 @@ import (
 	"testing"
 
 	"github.com/go-spring/spring-core/redis"
-	"github.com/go-spring/spring-core/redis/test/cases"
 )
 
-func ZAdd(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZAdd)
+func ZAdd(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZAdd())
 }
 
-func ZCard(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZCard)
+func ZCard(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZCard())
 }
 
-func ZCount(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZCount)
+func ZCount(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZCount())
 }
 
-func ZDiff(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZDiff)
+func ZDiff(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZDiff())
 }
 
-func ZIncrBy(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZIncrBy)
+func ZIncrBy(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZIncrBy())
 }
 
-func ZInter(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZInter)
+func ZInter(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZInter())
 }
 
-func ZLexCount(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZLexCount)
+func ZLexCount(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZLexCount())
 }
 
-func ZMScore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZMScore)
+func ZMScore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZMScore())
 }
 
-func ZPopMax(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZPopMax)
+func ZPopMax(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZPopMax())
 }
 
-func ZPopMin(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZPopMin)
+func ZPopMaxN(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZPopMaxN())
 }
 
-func ZRandMember(t *testing.T, conn redis.ConnPool) {
-	// RunCase(t, conn, cases.ZRandMember)
+func ZPopMin(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZPopMin())
 }
 
-func ZRange(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRange)
+func ZPopMinN(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZPopMinN())
 }
 
-func ZRangeByLex(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRangeByLex)
+func ZRandMember(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRandMember())
 }
 
-func ZRangeByScore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRangeByScore)
+func ZRandMemberN(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRandMemberN())
 }
 
-func ZRank(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRank)
+func ZRange(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRange())
 }
 
-func ZRem(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRem)
+func ZRangeByLex(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRangeByLex())
 }
 
-func ZRemRangeByLex(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRemRangeByLex)
+func ZRangeByScore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRangeByScore())
 }
 
-func ZRemRangeByRank(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRemRangeByRank)
+func ZRank(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRank())
 }
 
-func ZRemRangeByScore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRemRangeByScore)
+func ZRem(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRem())
 }
 
-func ZRevRange(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRevRange)
+func ZRemRangeByLex(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRemRangeByLex())
 }
 
-func ZRevRangeByLex(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRevRangeByLex)
+func ZRemRangeByRank(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRemRangeByRank())
 }
 
-func ZRevRangeByScore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRevRangeByScore)
+func ZRemRangeByScore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRemRangeByScore())
 }
 
-func ZRevRank(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZRevRank)
+func ZRevRange(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRevRange())
 }
 
-func ZScore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZScore)
+func ZRevRangeWithScores(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRevRangeWithScores())
 }
 
-func ZUnion(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZUnion)
+func ZRevRangeByLex(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRevRangeByLex())
 }
 
-func ZUnionStore(t *testing.T, conn redis.ConnPool) {
-	RunCase(t, conn, cases.ZUnionStore)
+func ZRevRangeByScore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRevRangeByScore())
+}
+
+func ZRevRank(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZRevRank())
+}
+
+func ZScan(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZScan())
+}
+
+func ZScore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZScore())
+}
+
+func ZUnion(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZUnion())
+}
+
+func ZUnionStore(t *testing.T, d redis.Driver) {
+	RunCase(t, d, new(redis.Cases).ZUnionStore())
 }
@@ -14,109 +14,134 @@
  * limitations under the License.
  */
 
-package record
-
-//
-//func TestZAdd(t *testing.T) {
-//	RunCase(t, record.ZAdd)
-//}
-//
-//func TestZCard(t *testing.T) {
-//	RunCase(t, record.ZCard)
-//}
-//
-//func TestZCount(t *testing.T) {
-//	RunCase(t, record.ZCount)
-//}
-//
-//func TestZDiff(t *testing.T) {
-//	RunCase(t, record.ZDiff)
-//}
-//
-//func TestZIncrBy(t *testing.T) {
-//	RunCase(t, record.ZIncrBy)
-//}
-//
-//func TestZInter(t *testing.T) {
-//	RunCase(t, record.ZInter)
-//}
-//
-//func TestZLexCount(t *testing.T) {
-//	RunCase(t, record.ZLexCount)
-//}
-//
-//func TestZMScore(t *testing.T) {
-//	RunCase(t, record.ZMScore)
-//}
-//
-//func TestZPopMax(t *testing.T) {
-//	RunCase(t, record.ZPopMax)
-//}
-//
-//func TestZPopMin(t *testing.T) {
-//	RunCase(t, record.ZPopMin)
-//}
-//
-//func TestZRandMember(t *testing.T) {
-//	RunCase(t, record.ZRandMember)
-//}
-//
-//func TestZRange(t *testing.T) {
-//	RunCase(t, record.ZRange)
-//}
-//
-//func TestZRangeByLex(t *testing.T) {
-//	RunCase(t, record.ZRangeByLex)
-//}
-//
-//func TestZRangeByScore(t *testing.T) {
-//	RunCase(t, record.ZRangeByScore)
-//}
-//
-//func TestZRank(t *testing.T) {
-//	RunCase(t, record.ZRank)
-//}
-//
-//func TestZRem(t *testing.T) {
-//	RunCase(t, record.ZRem)
-//}
-//
-//func TestZRemRangeByLex(t *testing.T) {
-//	RunCase(t, record.ZRemRangeByLex)
-//}
-//
-//func TestZRemRangeByRank(t *testing.T) {
-//	RunCase(t, record.ZRemRangeByRank)
-//}
-//
-//func TestZRemRangeByScore(t *testing.T) {
-//	RunCase(t, record.ZRemRangeByScore)
-//}
-//
-//func TestZRevRange(t *testing.T) {
-//	RunCase(t, record.ZRevRange)
-//}
-//
-//func TestZRevRangeByLex(t *testing.T) {
-//	RunCase(t, record.ZRevRangeByLex)
-//}
-//
-//func TestZRevRangeByScore(t *testing.T) {
-//	RunCase(t, record.ZRevRangeByScore)
-//}
-//
-//func TestZRevRank(t *testing.T) {
-//	RunCase(t, record.ZRevRank)
-//}
-//
-//func TestZScore(t *testing.T) {
-//	RunCase(t, record.ZScore)
-//}
-//
-//func TestZUnion(t *testing.T) {
-//	RunCase(t, record.ZUnion)
-//}
-//
-//func TestZUnionStore(t *testing.T) {
-//	RunCase(t, record.ZUnionStore)
-//}
+package record_test
+
+import (
+	"testing"
+
+	"github.com/go-spring/spring-replay/redis/test/record"
+)
+
+func TestZAdd(t *testing.T) {
+	record.ZAdd(t, liveDriver(t))
+}
+
+func TestZCard(t *testing.T) {
+	record.ZCard(t, liveDriver(t))
+}
+
+func TestZCount(t *testing.T) {
+	record.ZCount(t, liveDriver(t))
+}
+
+func TestZDiff(t *testing.T) {
+	record.ZDiff(t, liveDriver(t))
+}
+
+func TestZIncrBy(t *testing.T) {
+	record.ZIncrBy(t, liveDriver(t))
+}
+
+func TestZInter(t *testing.T) {
+	record.ZInter(t, liveDriver(t))
+}
+
+func TestZLexCount(t *testing.T) {
+	record.ZLexCount(t, liveDriver(t))
+}
+
+func TestZMScore(t *testing.T) {
+	record.ZMScore(t, liveDriver(t))
+}
+
+func TestZPopMax(t *testing.T) {
+	record.ZPopMax(t, liveDriver(t))
+}
+
+func TestZPopMaxN(t *testing.T) {
+	record.ZPopMaxN(t, liveDriver(t))
+}
+
+func TestZPopMin(t *testing.T) {
+	record.ZPopMin(t, liveDriver(t))
+}
+
+func TestZPopMinN(t *testing.T) {
+	record.ZPopMinN(t, liveDriver(t))
+}
+
+func TestZRandMember(t *testing.T) {
+	record.ZRandMember(t, liveDriver(t))
+}
+
+func TestZRandMemberN(t *testing.T) {
+	record.ZRandMemberN(t, liveDriver(t))
+}
+
+func TestZRange(t *testing.T) {
+	record.ZRange(t, liveDriver(t))
+}
+
+func TestZRangeByLex(t *testing.T) {
+	record.ZRangeByLex(t, liveDriver(t))
+}
+
+func TestZRangeByScore(t *testing.T) {
+	record.ZRangeByScore(t, liveDriver(t))
+}
+
+func TestZRank(t *testing.T) {
+	record.ZRank(t, liveDriver(t))
+}
+
+func TestZRem(t *testing.T) {
+	record.ZRem(t, liveDriver(t))
+}
+
+func TestZRemRangeByLex(t *testing.T) {
+	record.ZRemRangeByLex(t, liveDriver(t))
+}
+
+func TestZRemRangeByRank(t *testing.T) {
+	record.ZRemRangeByRank(t, liveDriver(t))
+}
+
+func TestZRemRangeByScore(t *testing.T) {
+	record.ZRemRangeByScore(t, liveDriver(t))
+}
+
+func TestZRevRange(t *testing.T) {
+	record.ZRevRange(t, liveDriver(t))
+}
+
+func TestZRevRangeWithScores(t *testing.T) {
+	record.ZRevRangeWithScores(t, liveDriver(t))
+}
+
+func TestZRevRangeByLex(t *testing.T) {
+	record.ZRevRangeByLex(t, liveDriver(t))
+}
+
+func TestZRevRangeByScore(t *testing.T) {
+	record.ZRevRangeByScore(t, liveDriver(t))
+}
+
+func TestZRevRank(t *testing.T) {
+	record.ZRevRank(t, liveDriver(t))
+}
+
+func TestZScan(t *testing.T) {
+	record.ZScan(t, liveDriver(t))
+}
+
+func TestZScore(t *testing.T) {
+	record.ZScore(t, liveDriver(t))
+}
+
+func TestZUnion(t *testing.T) {
+	record.ZUnion(t, liveDriver(t))
+}
+
+func TestZUnionStore(t *testing.T) {
+	record.ZUnionStore(t, liveDriver(t))
+}
@@ -19,25 +19,37 @@ package replay
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-core/redis"
 )
 
 func TestBitCount(t *testing.T) {
-	RunCase(t, cases.BitCount)
+	RunCase(t, new(redis.Cases).BitCount())
 }
 
 func TestBitOpAnd(t *testing.T) {
-	RunCase(t, cases.BitOpAnd)
+	RunCase(t, new(redis.Cases).BitOpAnd())
+}
+
+func TestBitOpOr(t *testing.T) {
+	RunCase(t, new(redis.Cases).BitOpOr())
+}
+
+func TestBitOpXor(t *testing.T) {
+	RunCase(t, new(redis.Cases).BitOpXor())
+}
+
+func TestBitOpNot(t *testing.T) {
+	RunCase(t, new(redis.Cases).BitOpNot())
 }
 
 func TestBitPos(t *testing.T) {
-	RunCase(t, cases.BitPos)
+	RunCase(t, new(redis.Cases).BitPos())
 }
 
 func TestGetBit(t *testing.T) {
-	RunCase(t, cases.GetBit)
+	RunCase(t, new(redis.Cases).GetBit())
 }
 
 func TestSetBit(t *testing.T) {
-	RunCase(t, cases.SetBit)
+	RunCase(t, new(redis.Cases).SetBit())
 }
@@ -19,57 +19,61 @@ package replay
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-core/redis"
 )
 
 func TestHDel(t *testing.T) {
-	RunCase(t, cases.HDel)
+	RunCase(t, new(redis.Cases).HDel())
 }
 
 func TestHExists(t *testing.T) {
-	RunCase(t, cases.HExists)
+	RunCase(t, new(redis.Cases).HExists())
 }
 
 func TestHGet(t *testing.T) {
-	RunCase(t, cases.HGet)
+	RunCase(t, new(redis.Cases).HGet())
 }
 
 func TestHGetAll(t *testing.T) {
-	RunCase(t, cases.HGetAll)
+	RunCase(t, new(redis.Cases).HGetAll())
 }
 
 func TestHIncrBy(t *testing.T) {
-	RunCase(t, cases.HIncrBy)
+	RunCase(t, new(redis.Cases).HIncrBy())
 }
 
 func TestHIncrByFloat(t *testing.T) {
-	RunCase(t, cases.HIncrByFloat)
+	RunCase(t, new(redis.Cases).HIncrByFloat())
 }
 
 func TestHKeys(t *testing.T) {
-	RunCase(t, cases.HKeys)
+	RunCase(t, new(redis.Cases).HKeys())
 }
 
 func TestHLen(t *testing.T) {
-	RunCase(t, cases.HLen)
+	RunCase(t, new(redis.Cases).HLen())
 }
 
 func TestHMGet(t *testing.T) {
-	RunCase(t, cases.HMGet)
+	RunCase(t, new(redis.Cases).HMGet())
+}
+
+func TestHScan(t *testing.T) {
+	RunCase(t, new(redis.Cases).HScan())
 }
 
 func TestHSet(t *testing.T) {
-	RunCase(t, cases.HSet)
+	RunCase(t, new(redis.Cases).HSet())
 }
 
 func TestHSetNX(t *testing.T) {
-	RunCase(t, cases.HSetNX)
+	RunCase(t, new(redis.Cases).HSetNX())
 }
 
 func TestHStrLen(t *testing.T) {
-	RunCase(t, cases.HStrLen)
+	RunCase(t, new(redis.Cases).HStrLen())
 }
 
 func TestHVals(t *testing.T) {
-	RunCase(t, cases.HVals)
+	RunCase(t, new(redis.Cases).HVals())
 }
@@ -19,65 +19,73 @@ package replay
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-core/redis"
 )
 
 func TestDel(t *testing.T) {
-	RunCase(t, cases.Del)
+	RunCase(t, new(redis.Cases).Del())
 }
 
 func TestDump(t *testing.T) {
-	RunCase(t, cases.Dump)
+	RunCase(t, new(redis.Cases).Dump())
 }
 
 func TestExists(t *testing.T) {
-	RunCase(t, cases.Exists)
+	RunCase(t, new(redis.Cases).Exists())
 }
 
 func TestExpire(t *testing.T) {
-	RunCase(t, cases.Expire)
+	RunCase(t, new(redis.Cases).Expire())
 }
 
 func TestExpireAt(t *testing.T) {
-	RunCase(t, cases.ExpireAt)
+	RunCase(t, new(redis.Cases).ExpireAt())
 }
 
 func TestKeys(t *testing.T) {
-	RunCase(t, cases.Keys)
+	RunCase(t, new(redis.Cases).Keys())
 }
 
 func TestPersist(t *testing.T) {
-	RunCase(t, cases.Persist)
+	RunCase(t, new(redis.Cases).Persist())
 }
 
 func TestPExpire(t *testing.T) {
-	RunCase(t, cases.PExpire)
+	RunCase(t, new(redis.Cases).PExpire())
 }
 
 func TestPExpireAt(t *testing.T) {
-	RunCase(t, cases.PExpireAt)
+	RunCase(t, new(redis.Cases).PExpireAt())
 }
 
 func TestPTTL(t *testing.T) {
-	RunCase(t, cases.PTTL)
+	RunCase(t, new(redis.Cases).PTTL())
+}
+
+func TestRandomKey(t *testing.T) {
+	RunCase(t, new(redis.Cases).RandomKey())
 }
 
 func TestRename(t *testing.T) {
-	RunCase(t, cases.Rename)
+	RunCase(t, new(redis.Cases).Rename())
 }
 
 func TestRenameNX(t *testing.T) {
-	RunCase(t, cases.RenameNX)
+	RunCase(t, new(redis.Cases).RenameNX())
+}
+
+func TestScan(t *testing.T) {
+	RunCase(t, new(redis.Cases).Scan())
 }
 
 func TestTouch(t *testing.T) {
-	RunCase(t, cases.Touch)
+	RunCase(t, new(redis.Cases).Touch())
 }
 
 func TestTTL(t *testing.T) {
-	RunCase(t, cases.TTL)
+	RunCase(t, new(redis.Cases).TTL())
 }
 
 func TestType(t *testing.T) {
-	RunCase(t, cases.Type)
+	RunCase(t, new(redis.Cases).Type())
 }
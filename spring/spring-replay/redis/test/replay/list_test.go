@@ -19,69 +19,73 @@ package replay
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-core/redis"
 )
 
 func TestLIndex(t *testing.T) {
-	RunCase(t, cases.LIndex)
+	RunCase(t, new(redis.Cases).LIndex())
 }
 
-func TestLInsert(t *testing.T) {
-	RunCase(t, cases.LInsert)
+func TestLInsertBefore(t *testing.T) {
+	RunCase(t, new(redis.Cases).LInsertBefore())
+}
+
+func TestLInsertAfter(t *testing.T) {
+	RunCase(t, new(redis.Cases).LInsertAfter())
 }
 
 func TestLLen(t *testing.T) {
-	RunCase(t, cases.LLen)
+	RunCase(t, new(redis.Cases).LLen())
 }
 
 func TestLMove(t *testing.T) {
-	RunCase(t, cases.LMove)
+	RunCase(t, new(redis.Cases).LMove())
 }
 
 func TestLPop(t *testing.T) {
-	RunCase(t, cases.LPop)
+	RunCase(t, new(redis.Cases).LPop())
 }
 
 func TestLPos(t *testing.T) {
-	RunCase(t, cases.LPos)
+	RunCase(t, new(redis.Cases).LPos())
 }
 
 func TestLPush(t *testing.T) {
-	RunCase(t, cases.LPush)
+	RunCase(t, new(redis.Cases).LPush())
 }
 
 func TestLPushX(t *testing.T) {
-	RunCase(t, cases.LPushX)
+	RunCase(t, new(redis.Cases).LPushX())
 }
 
 func TestLRange(t *testing.T) {
-	RunCase(t, cases.LRange)
+	RunCase(t, new(redis.Cases).LRange())
 }
 
 func TestLRem(t *testing.T) {
-	RunCase(t, cases.LRem)
+	RunCase(t, new(redis.Cases).LRem())
 }
 
 func TestLSet(t *testing.T) {
-	RunCase(t, cases.LSet)
+	RunCase(t, new(redis.Cases).LSet())
 }
 
 func TestLTrim(t *testing.T) {
-	RunCase(t, cases.LTrim)
+	RunCase(t, new(redis.Cases).LTrim())
 }
 
 func TestRPop(t *testing.T) {
-	RunCase(t, cases.RPop)
+	RunCase(t, new(redis.Cases).RPop())
 }
 
 func TestRPopLPush(t *testing.T) {
-	RunCase(t, cases.RPopLPush)
+	RunCase(t, new(redis.Cases).RPopLPush())
 }
 
 func TestRPush(t *testing.T) {
-	RunCase(t, cases.RPush)
+	RunCase(t, new(redis.Cases).RPush())
 }
 
 func TestRPushX(t *testing.T) {
-	RunCase(t, cases.RPushX)
+	RunCase(t, new(redis.Cases).RPushX())
 }
@@ -17,34 +17,41 @@
 package replay
 
 import (
+	"context"
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-base/knife"
+	"github.com/go-spring/spring-core/redis"
+	"github.com/go-spring/spring-replay/replayer"
+
+	replay "github.com/go-spring/spring-replay/redis"
 )
 
-func RunCase(t *testing.T, c cases.Case) {
-
-	//replayer.SetReplayMode(true)
-	//defer func() {
-	//	replayer.SetReplayMode(false)
-	//}()
-	//
-	//agent := replayer.NewLocalAgent()
-	//replayer.SetReplayAgent(agent)
-	//
-	//session, err := agent.Store(c.Data)
-	//assert.Nil(t, err)
-	//
-	//ctx, _ := knife.New(context.Background())
-	//err = replayer.SetSessionID(ctx, session.Session)
-	//if err != nil {
-	//	t.Fatal(err)
-	//}
-	//
-	//client, err := redis.NewClient(nil)
-	//if err != nil {
-	//	t.Fatal(err)
-	//}
-	//
-	//c.Func(t, ctx, client)
+// RunCase 使用测试用例 c 中录制的数据回放 c ，不需要真实的 redis 服务。
+func RunCase(t *testing.T, c *redis.Case) {
+
+	if c.Skip || c.Data == "" {
+		t.Skip()
+	}
+
+	replayer.SetReplayMode(true)
+	defer func() {
+		replayer.SetReplayMode(false)
+	}()
+
+	agent := replayer.NewLocalAgent()
+	replayer.SetReplayAgent(agent)
+
+	session, err := agent.Store(c.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, _ := knife.New(context.Background())
+	err = replayer.SetSessionID(ctx, session.Session)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Func(t, ctx, redis.NewClient(replay.NewReplayer(nil)))
 }
@@ -19,61 +19,73 @@ package replay
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-core/redis"
 )
 
 func TestSAdd(t *testing.T) {
-	RunCase(t, cases.SAdd)
+	RunCase(t, new(redis.Cases).SAdd())
 }
 
 func TestSCard(t *testing.T) {
-	RunCase(t, cases.SCard)
+	RunCase(t, new(redis.Cases).SCard())
 }
 
 func TestSDiff(t *testing.T) {
-	RunCase(t, cases.SDiff)
+	RunCase(t, new(redis.Cases).SDiff())
 }
 
 func TestSDiffStore(t *testing.T) {
-	RunCase(t, cases.SDiffStore)
+	RunCase(t, new(redis.Cases).SDiffStore())
 }
 
 func TestSInter(t *testing.T) {
-	RunCase(t, cases.SInter)
+	RunCase(t, new(redis.Cases).SInter())
 }
 
 func TestSInterStore(t *testing.T) {
-	RunCase(t, cases.SInterStore)
+	RunCase(t, new(redis.Cases).SInterStore())
+}
+
+func TestSIsMember(t *testing.T) {
+	RunCase(t, new(redis.Cases).SIsMember())
 }
 
 func TestSMembers(t *testing.T) {
-	RunCase(t, cases.SMembers)
+	RunCase(t, new(redis.Cases).SMembers())
 }
 
 func TestSMIsMember(t *testing.T) {
-	RunCase(t, cases.SMIsMember)
+	RunCase(t, new(redis.Cases).SMIsMember())
 }
 
 func TestSMove(t *testing.T) {
-	RunCase(t, cases.SMove)
+	RunCase(t, new(redis.Cases).SMove())
 }
 
 func TestSPop(t *testing.T) {
-	RunCase(t, cases.SPop)
+	RunCase(t, new(redis.Cases).SPop())
+}
+
+func TestSPopN(t *testing.T) {
+	RunCase(t, new(redis.Cases).SPopN())
 }
 
 func TestSRandMember(t *testing.T) {
-	RunCase(t, cases.SRandMember)
+	RunCase(t, new(redis.Cases).SRandMember())
 }
 
 func TestSRem(t *testing.T) {
-	RunCase(t, cases.SRem)
+	RunCase(t, new(redis.Cases).SRem())
+}
+
+func TestSScan(t *testing.T) {
+	RunCase(t, new(redis.Cases).SScan())
 }
 
 func TestSUnion(t *testing.T) {
-	RunCase(t, cases.SUnion)
+	RunCase(t, new(redis.Cases).SUnion())
 }
 
 func TestSUnionStore(t *testing.T) {
-	RunCase(t, cases.SUnionStore)
+	RunCase(t, new(redis.Cases).SUnionStore())
 }
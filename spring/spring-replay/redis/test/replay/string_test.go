@@ -19,81 +19,85 @@ package replay
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-core/redis"
 )
 
 func TestAppend(t *testing.T) {
-	RunCase(t, cases.Append)
+	RunCase(t, new(redis.Cases).Append())
 }
 
 func TestDecr(t *testing.T) {
-	RunCase(t, cases.Decr)
+	RunCase(t, new(redis.Cases).Decr())
 }
 
 func TestDecrBy(t *testing.T) {
-	RunCase(t, cases.DecrBy)
+	RunCase(t, new(redis.Cases).DecrBy())
 }
 
 func TestGet(t *testing.T) {
-	RunCase(t, cases.Get)
+	RunCase(t, new(redis.Cases).Get())
 }
 
 func TestGetDel(t *testing.T) {
-	RunCase(t, cases.GetDel)
+	RunCase(t, new(redis.Cases).GetDel())
+}
+
+func TestGetEx(t *testing.T) {
+	RunCase(t, new(redis.Cases).GetEx())
 }
 
 func TestGetRange(t *testing.T) {
-	RunCase(t, cases.GetRange)
+	RunCase(t, new(redis.Cases).GetRange())
 }
 
 func TestGetSet(t *testing.T) {
-	RunCase(t, cases.GetSet)
+	RunCase(t, new(redis.Cases).GetSet())
 }
 
 func TestIncr(t *testing.T) {
-	RunCase(t, cases.Incr)
+	RunCase(t, new(redis.Cases).Incr())
 }
 
 func TestIncrBy(t *testing.T) {
-	RunCase(t, cases.IncrBy)
+	RunCase(t, new(redis.Cases).IncrBy())
 }
 
 func TestIncrByFloat(t *testing.T) {
-	RunCase(t, cases.IncrByFloat)
+	RunCase(t, new(redis.Cases).IncrByFloat())
 }
 
 func TestMGet(t *testing.T) {
-	RunCase(t, cases.MGet)
+	RunCase(t, new(redis.Cases).MGet())
 }
 
 func TestMSet(t *testing.T) {
-	RunCase(t, cases.MSet)
+	RunCase(t, new(redis.Cases).MSet())
 }
 
 func TestMSetNX(t *testing.T) {
-	RunCase(t, cases.MSetNX)
+	RunCase(t, new(redis.Cases).MSetNX())
 }
 
 func TestPSetEX(t *testing.T) {
-	RunCase(t, cases.PSetEX)
+	RunCase(t, new(redis.Cases).PSetEX())
 }
 
 func TestSet(t *testing.T) {
-	RunCase(t, cases.Set)
+	RunCase(t, new(redis.Cases).Set())
 }
 
 func TestSetEX(t *testing.T) {
-	RunCase(t, cases.SetEX)
+	RunCase(t, new(redis.Cases).SetEX())
 }
 
 func TestSetNX(t *testing.T) {
-	RunCase(t, cases.SetNX)
+	RunCase(t, new(redis.Cases).SetNX())
 }
 
 func TestSetRange(t *testing.T) {
-	RunCase(t, cases.SetRange)
+	RunCase(t, new(redis.Cases).SetRange())
 }
 
 func TestStrLen(t *testing.T) {
-	RunCase(t, cases.StrLen)
+	RunCase(t, new(redis.Cases).StrLen())
 }
@@ -19,109 +19,129 @@ package replay
 import (
 	"testing"
 
-	"github.com/go-spring/spring-core/redis/test/cases"
+	"github.com/go-spring/spring-core/redis"
 )
 
 func TestZAdd(t *testing.T) {
-	RunCase(t, cases.ZAdd)
+	RunCase(t, new(redis.Cases).ZAdd())
 }
 
 func TestZCard(t *testing.T) {
-	RunCase(t, cases.ZCard)
+	RunCase(t, new(redis.Cases).ZCard())
 }
 
 func TestZCount(t *testing.T) {
-	RunCase(t, cases.ZCount)
+	RunCase(t, new(redis.Cases).ZCount())
 }
 
 func TestZDiff(t *testing.T) {
-	RunCase(t, cases.ZDiff)
+	RunCase(t, new(redis.Cases).ZDiff())
 }
 
 func TestZIncrBy(t *testing.T) {
-	RunCase(t, cases.ZIncrBy)
+	RunCase(t, new(redis.Cases).ZIncrBy())
 }
 
 func TestZInter(t *testing.T) {
-	RunCase(t, cases.ZInter)
+	RunCase(t, new(redis.Cases).ZInter())
 }
 
 func TestZLexCount(t *testing.T) {
-	RunCase(t, cases.ZLexCount)
+	RunCase(t, new(redis.Cases).ZLexCount())
 }
 
 func TestZMScore(t *testing.T) {
-	RunCase(t, cases.ZMScore)
+	RunCase(t, new(redis.Cases).ZMScore())
 }
 
 func TestZPopMax(t *testing.T) {
-	RunCase(t, cases.ZPopMax)
+	RunCase(t, new(redis.Cases).ZPopMax())
+}
+
+func TestZPopMaxN(t *testing.T) {
+	RunCase(t, new(redis.Cases).ZPopMaxN())
 }
 
 func TestZPopMin(t *testing.T) {
-	RunCase(t, cases.ZPopMin)
+	RunCase(t, new(redis.Cases).ZPopMin())
+}
+
+func TestZPopMinN(t *testing.T) {
+	RunCase(t, new(redis.Cases).ZPopMinN())
 }
 
 func TestZRandMember(t *testing.T) {
-	RunCase(t, cases.ZRandMember)
+	RunCase(t, new(redis.Cases).ZRandMember())
+}
+
+func TestZRandMemberN(t *testing.T) {
+	RunCase(t, new(redis.Cases).ZRandMemberN())
 }
 
 func TestZRange(t *testing.T) {
-	RunCase(t, cases.ZRange)
+	RunCase(t, new(redis.Cases).ZRange())
 }
 
 func TestZRangeByLex(t *testing.T) {
-	RunCase(t, cases.ZRangeByLex)
+	RunCase(t, new(redis.Cases).ZRangeByLex())
 }
 
 func TestZRangeByScore(t *testing.T) {
-	RunCase(t, cases.ZRangeByScore)
+	RunCase(t, new(redis.Cases).ZRangeByScore())
 }
 
 func TestZRank(t *testing.T) {
-	RunCase(t, cases.ZRank)
+	RunCase(t, new(redis.Cases).ZRank())
 }
 
 func TestZRem(t *testing.T) {
-	RunCase(t, cases.ZRem)
+	RunCase(t, new(redis.Cases).ZRem())
 }
 
 func TestZRemRangeByLex(t *testing.T) {
-	RunCase(t, cases.ZRemRangeByLex)
+	RunCase(t, new(redis.Cases).ZRemRangeByLex())
 }
 
 func TestZRemRangeByRank(t *testing.T) {
-	RunCase(t, cases.ZRemRangeByRank)
+	RunCase(t, new(redis.Cases).ZRemRangeByRank())
 }
 
 func TestZRemRangeByScore(t *testing.T) {
-	RunCase(t, cases.ZRemRangeByScore)
+	RunCase(t, new(redis.Cases).ZRemRangeByScore())
 }
 
 func TestZRevRange(t *testing.T) {
-	RunCase(t, cases.ZRevRange)
+	RunCase(t, new(redis.Cases).ZRevRange())
+}
+
+func TestZRevRangeWithScores(t *testing.T) {
+	RunCase(t, new(redis.Cases).ZRevRangeWithScores())
 }
 
 func TestZRevRangeByLex(t *testing.T) {
-	RunCase(t, cases.ZRevRangeByLex)
+	RunCase(t, new(redis.Cases).ZRevRangeByLex())
 }
 
 func TestZRevRangeByScore(t *testing.T) {
-	RunCase(t, cases.ZRevRangeByScore)
+	RunCase(t, new(redis.Cases).ZRevRangeByScore())
 }
 
 func TestZRevRank(t *testing.T) {
-	RunCase(t, cases.ZRevRank)
+	RunCase(t, new(redis.Cases).ZRevRank())
+}
+
+func TestZScan(t *testing.T) {
+	RunCase(t, new(redis.Cases).ZScan())
 }
 
 func TestZScore(t *testing.T) {
-	RunCase(t, cases.ZScore)
+	RunCase(t, new(redis.Cases).ZScore())
 }
 
 func TestZUnion(t *testing.T) {
-	RunCase(t, cases.ZUnion)
+	RunCase(t, new(redis.Cases).ZUnion())
 }
 
 func TestZUnionStore(t *testing.T) {
-	RunCase(t, cases.ZUnionStore)
+	RunCase(t, new(redis.Cases).ZUnionStore())
 }
@@ -23,7 +23,7 @@ import (
 	"strings"
 
 	"github.com/go-spring/spring-base/knife"
-	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-base/run"
 	"github.com/go-spring/spring-replay/internal/json"
 	"github.com/go-spring/spring-replay/recorder"
 )
@@ -55,13 +55,13 @@ func ReplayMode() bool {
 
 // SetReplayMode 打开或者关闭回放模式，仅用于单元测试。
 func SetReplayMode(enable bool) {
-	util.MustTestMode()
+	run.MustTestMode()
 	replayer.enable = enable
 }
 
 // SetReplayAgent 设置本地还是远程回放。
 func SetReplayAgent(agent Agent) {
-	util.MustTestMode()
+	run.MustTestMode()
 	replayer.agent = agent
 }
 
@@ -0,0 +1,128 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package StarterGoRedisMQ 基于 Redis Streams 实现 mq.Producer 和 mq.Consumer ，
+// 当 mq.broker 属性为 redis 时生效。
+package StarterGoRedisMQ
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-spring/spring-base/log"
+	"github.com/go-spring/spring-base/util"
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/cond"
+	"github.com/go-spring/spring-core/mq"
+	"github.com/go-spring/spring-core/redis"
+)
+
+func init() {
+	onBroker := cond.OnProperty("mq.broker", cond.HavingValue("redis"))
+	gs.Provide(NewProducer, "${redis.mq}").
+		Export((*mq.Producer)(nil)).
+		On(onBroker)
+	gs.Object(new(Starter)).
+		Export((*gs.AppEvent)(nil)).
+		On(onBroker)
+}
+
+// Config Redis Streams 消息队列的配置。
+type Config struct {
+	Prefix        string        `value:"${prefix:=}"`            // stream key 的前缀
+	MaxLen        int64         `value:"${max-len:=0}"`          // stream 的最大长度，0 表示不裁剪
+	Group         string        `value:"${group:=default}"`      // 消费者组
+	GroupStart    string        `value:"${group-start:=0}"`      // 新建消费者组时的起始消息 ID
+	Consumer      string        `value:"${consumer:=}"`          // 消费者名称，默认为 hostname-pid
+	Count         int64         `value:"${count:=10}"`           // 每次读取的最大消息数
+	Block         time.Duration `value:"${block:=1s}"`           // 没有消息时的阻塞时间
+	MinIdleTime   time.Duration `value:"${min-idle-time:=30s}"`  // 未确认的消息被重新认领前的空闲时间
+	ClaimInterval time.Duration `value:"${claim-interval:=10s}"` // 重新认领消息的时间间隔
+	MaxDeliveries int64         `value:"${max-deliveries:=10}"`  // 消息的最大投递次数，0 表示不限制
+	DeadLetter    string        `value:"${dead-letter:=:dead}"`  // 死信 stream key 的后缀，为空时丢弃消息
+	ConsumerIdle  time.Duration `value:"${consumer-idle:=1h}"`   // 没有未确认消息的消费者空闲多久后被删除
+}
+
+func (c Config) options() []redis.StreamOption {
+	return []redis.StreamOption{
+		redis.StreamPrefix(c.Prefix),
+		redis.StreamGroupStart(c.GroupStart),
+		redis.StreamMaxLen(c.MaxLen),
+		redis.StreamCount(c.Count),
+		redis.StreamBlock(c.Block),
+		redis.StreamClaim(c.MinIdleTime, c.ClaimInterval),
+		redis.StreamDeadLetter(c.MaxDeliveries, c.DeadLetter),
+		redis.StreamConsumerIdle(c.ConsumerIdle),
+	}
+}
+
+// NewProducer 创建基于 Redis Streams 的消息生产者。
+func NewProducer(config Config, client *redis.Client) *redis.StreamProducer {
+	return redis.NewStreamProducer(client, config.options()...)
+}
+
+// Starter 在应用启动后从 Redis Streams 消费消息，应用关闭时停止消费。
+type Starter struct {
+	Logger *log.Logger   `logger:""`
+	Client *redis.Client `autowire:""`
+	Config Config        `value:"${redis.mq}"`
+}
+
+func (starter *Starter) OnAppStart(ctx gs.Context) {
+
+	name := starter.Config.Consumer
+	if name == "" {
+		hostname, err := os.Hostname()
+		util.Panic(err).When(err != nil)
+		// 同一台主机上的多个实例使用不同的消费者名称，已停止实例的消费者空闲一段时间后被删除。
+		name = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	c := redis.NewStreamConsumer(starter.Client, starter.Config.Group, name, starter.Config.options()...)
+	{
+		var consumers []mq.Consumer
+		err := ctx.Get(&consumers, "*?")
+		util.Panic(err).When(err != nil)
+
+		var bindConsumers *gs.Consumers
+		err = ctx.Get(&bindConsumers)
+		util.Panic(err).When(err != nil)
+
+		bindConsumers.ForEach(func(consumer mq.Consumer) {
+			consumers = append(consumers, consumer)
+		})
+
+		for _, consumer := range consumers {
+			c.Subscribe(consumer)
+		}
+	}
+
+	if len(c.Topics()) == 0 {
+		return
+	}
+
+	ctx.Go(func(ctx context.Context) {
+		if err := c.Run(ctx); err != nil {
+			starter.Logger.Errorf("consume redis streams error: %v", err)
+		}
+	})
+}
+
+func (starter *Starter) OnAppStop(ctx context.Context) {
+
+}
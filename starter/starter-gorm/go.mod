@@ -4,8 +4,10 @@ go 1.14
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.5.0
+	github.com/go-sql-driver/mysql v1.6.0
 	github.com/go-spring/spring-base v1.1.3
 	github.com/go-spring/spring-core v1.1.3
+	github.com/go-spring/spring-replay v1.1.3
 	gorm.io/driver/mysql v1.2.1
 	gorm.io/gorm v1.22.4
 )
@@ -13,4 +15,5 @@ require (
 //replace (
 //	github.com/go-spring/spring-base => ../../spring/spring-base
 //	github.com/go-spring/spring-core => ../../spring/spring-core
+//	github.com/go-spring/spring-replay => ../../spring/spring-replay
 //)
@@ -22,6 +22,10 @@ import (
 	"github.com/go-spring/spring-core/gs"
 	"github.com/go-spring/spring-core/gs/arg"
 	"github.com/go-spring/spring-core/gs/cond"
+	replay "github.com/go-spring/spring-replay/database"
+	"github.com/go-spring/spring-replay/recorder"
+	"github.com/go-spring/spring-replay/replayer"
+	driver "github.com/go-sql-driver/mysql"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -32,7 +36,20 @@ type Factory struct {
 
 func (factory *Factory) CreateDB(config database.Config) (*gorm.DB, error) {
 	factory.Logger.Infof("open gorm mysql %s", config.URL)
-	db, err := gorm.Open(mysql.Open(config.URL))
+	dialector := mysql.Open(config.URL)
+	if recorder.RecordMode() || replayer.ReplayMode() {
+		sqlDB, err := replay.OpenDB(&driver.MySQLDriver{}, config.URL)
+		if err != nil {
+			return nil, err
+		}
+		// 回放模式下不访问真实的数据库，所以不查询数据库的版本。
+		dialector = mysql.New(mysql.Config{
+			DSN:                       config.URL,
+			Conn:                      sqlDB,
+			SkipInitializeWithVersion: replayer.ReplayMode(),
+		})
+	}
+	db, err := gorm.Open(dialector)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package StarterMQMemory 注册进程内的 mq.Producer ，消息直接投递给 gs.Consume 和
+// mq.Consumer Bean 定义的消费者，当 mq.broker 属性为 memory 时生效，适用于单元测试和本地开发。
+package StarterMQMemory
+
+import (
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/cond"
+	"github.com/go-spring/spring-core/mq"
+)
+
+func init() {
+	gs.Provide(NewBroker, "${mq.memory}", "*?").
+		Export((*mq.Producer)(nil)).
+		Destroy((*mq.MemoryBroker).Close).
+		On(cond.OnProperty("mq.broker", cond.HavingValue("memory")))
+}
+
+// Config 进程内消息队列的配置。
+type Config struct {
+	Async       bool `value:"${async:=false}"`   // 是否异步投递消息
+	Concurrency int  `value:"${concurrency:=1}"` // 异步投递消息的协程数量
+}
+
+// NewBroker 创建进程内的消息队列并订阅所有的消费者。
+func NewBroker(config Config, consumers []mq.Consumer, bindConsumers *gs.Consumers) *mq.MemoryBroker {
+	var opts []mq.MemoryOption
+	if config.Async {
+		opts = append(opts, mq.Async(config.Concurrency))
+	}
+	b := mq.NewMemoryBroker(opts...)
+	for _, c := range consumers {
+		b.Subscribe(c)
+	}
+	bindConsumers.ForEach(b.Subscribe)
+	return b
+}
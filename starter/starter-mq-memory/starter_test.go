@@ -0,0 +1,103 @@
+/*
+ * Copyright 2012-2019 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package StarterMQMemory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-spring/spring-base/assert"
+	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/cond"
+	"github.com/go-spring/spring-core/mq"
+	_ "github.com/go-spring/starter-mq-memory"
+)
+
+// rabbitSender 模拟 rabbit starter 注册的 mq.Producer ，使用相同的生效条件。
+type rabbitSender struct{}
+
+func (s *rabbitSender) SendMessage(ctx context.Context, msg mq.Message) error {
+	return nil
+}
+
+type event struct {
+	Name string `json:"name"`
+}
+
+type starter struct {
+	Producer mq.Producer      `autowire:""`
+	Broker   *mq.MemoryBroker `autowire:""`
+	started  chan struct{}
+}
+
+func (s *starter) OnAppStart(ctx gs.Context) {
+	close(s.started)
+}
+
+func (s *starter) OnAppStop(ctx context.Context) {}
+
+func TestMemoryBroker(t *testing.T) {
+
+	gs.Property("mq.broker", "memory")
+	gs.Object(new(rabbitSender)).
+		Export((*mq.Producer)(nil)).
+		On(cond.OnProperty("mq.broker", cond.HavingValue("rabbit"), cond.MatchIfMissing()))
+
+	var (
+		mutex sync.Mutex
+		names []string
+	)
+	gs.Consume(func(ctx context.Context, e *event) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		names = append(names, e.Name)
+		return nil
+	}, "user")
+
+	s := &starter{started: make(chan struct{})}
+	gs.Object(s).Export((*gs.AppEvent)(nil))
+
+	exited := make(chan error)
+	go func() {
+		exited <- gs.Web(false).Run()
+	}()
+
+	select {
+	case <-s.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("start timeout")
+	}
+
+	// mq.broker=memory 时只有 MemoryBroker 生效，rabbit 的 mq.Producer 不再生效。
+	b, ok := s.Producer.(*mq.MemoryBroker)
+	assert.True(t, ok)
+	assert.Equal(t, b, s.Broker)
+
+	msg := mq.NewMessage().WithTopic("user").WithBody([]byte(`{"name":"jim"}`))
+	assert.Nil(t, s.Producer.SendMessage(context.Background(), msg))
+	assert.Equal(t, names, []string{"jim"})
+	assert.Equal(t, len(s.Broker.Messages("user")), 1)
+
+	gs.ShutDown("test end")
+	assert.Nil(t, <-exited)
+
+	// 应用停止时关闭消息队列。
+	err := s.Producer.SendMessage(context.Background(), msg)
+	assert.Equal(t, err, mq.ErrBrokerClosed)
+}
@@ -22,12 +22,15 @@ import (
 	"github.com/go-spring/spring-base/log"
 	"github.com/go-spring/spring-base/util"
 	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/cond"
 	"github.com/go-spring/spring-core/mq"
 	"github.com/go-spring/starter-rabbit/server"
 )
 
 func init() {
-	gs.Object(new(Starter)).Export((*gs.AppEvent)(nil))
+	gs.Object(new(Starter)).
+		Export((*gs.AppEvent)(nil)).
+		On(cond.OnProperty("mq.broker", cond.HavingValue("rabbit"), cond.MatchIfMissing()))
 }
 
 type Starter struct {
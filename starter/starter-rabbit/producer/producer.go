@@ -20,13 +20,16 @@ import (
 	"context"
 
 	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/cond"
 	"github.com/go-spring/spring-core/mq"
 	"github.com/go-spring/starter-rabbit/server"
 	"github.com/streadway/amqp"
 )
 
 func init() {
-	gs.Object(new(Sender)).Export((*mq.Producer)(nil))
+	gs.Object(new(Sender)).
+		Export((*mq.Producer)(nil)).
+		On(cond.OnProperty("mq.broker", cond.HavingValue("rabbit"), cond.MatchIfMissing()))
 }
 
 type Sender struct {
@@ -19,11 +19,14 @@ package StarterRabbitServer
 
 import (
 	"github.com/go-spring/spring-core/gs"
+	"github.com/go-spring/spring-core/gs/cond"
 	"github.com/streadway/amqp"
 )
 
 func init() {
-	gs.Provide(CreateServer).Destroy(DestroyServer)
+	gs.Provide(CreateServer).
+		Destroy(DestroyServer).
+		On(cond.OnProperty("mq.broker", cond.HavingValue("rabbit"), cond.MatchIfMissing()))
 }
 
 type AMQPServerConfig struct {
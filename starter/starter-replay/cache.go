@@ -35,16 +35,16 @@ func NewReplayInterface(next cache.Driver) cache.Driver {
 	}
 }
 
-func (d *replayInterface) Load(ctx context.Context, m *sync.Map, key string, loader cache.ResultLoader, arg cache.OptionArg) (loadType cache.LoadType, result cache.Result, err error) {
+func (d *replayInterface) Load(ctx context.Context, m *cache.Shard, key string, loader cache.ResultLoader, arg cache.OptionArg) (loadType cache.LoadType, result cache.Result, err error) {
 
 	if run.ReplayMode() {
 		var v interface{}
 		const ctxKey = "::CacheOnContext::"
-		v, _, err = knife.LoadOrStore(ctx, ctxKey, &sync.Map{})
+		v, _, err = knife.LoadOrStore(ctx, ctxKey, cache.NewShard(0, nil))
 		if err != nil {
 			return cache.LoadNone, nil, err
 		}
-		m = v.(*sync.Map)
+		m = v.(*cache.Shard)
 	}
 
 	defer func() {
@@ -84,7 +84,7 @@ func newCtxItem() *ctxItem {
 	return c
 }
 
-func (d *contextInterface) Load(ctx context.Context, m *sync.Map, key string, loader cache.ResultLoader, arg cache.OptionArg) (loadType cache.LoadType, result cache.Result, err error) {
+func (d *contextInterface) Load(ctx context.Context, m *cache.Shard, key string, loader cache.ResultLoader, arg cache.OptionArg) (loadType cache.LoadType, result cache.Result, err error) {
 
 	i, loaded, err := knife.LoadOrStore(ctx, key, newCtxItem())
 	if err != nil {
@@ -17,28 +17,48 @@
 package StarterGoPlayground
 
 import (
+	"fmt"
+
 	"github.com/go-playground/validator/v10"
 	"github.com/go-spring/spring-core/validate"
 )
 
+// TagName 使用 go-playground 校验规则的结构体标签。
+const TagName = "validate"
+
 func init() {
-	validate.Validator = &Validate{
+	validate.Register(TagName, &Validate{
 		v: validator.New(),
-	}
+	})
 }
 
+// Validate 使用 go-playground 的规则校验字段，校验失败时返回 validate.Errors 。
 type Validate struct {
 	v *validator.Validate
 }
 
-func (v *Validate) TagName() string {
-	return "validate"
-}
-
-func (v *Validate) Struct(i interface{}) error {
-	return v.v.Struct(i)
-}
-
-func (v *Validate) Field(i interface{}, tag string) error {
-	return v.v.Var(i, tag)
+// Field validates a single variable.
+func (v *Validate) Field(tag string, i interface{}) error {
+	err := v.v.Var(i, tag)
+	if err == nil {
+		return nil
+	}
+	ves, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	var errs validate.Errors
+	for _, fe := range ves {
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		errs = append(errs, &validate.FieldError{
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Value:   fe.Value(),
+			Message: fmt.Sprintf("validate failed on %q", rule),
+		})
+	}
+	return errs
 }